@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -22,6 +23,10 @@ type Applies interface {
 
 	// Logs retrieves the logs of an apply.
 	Logs(ctx context.Context, applyID string) (io.Reader, error)
+
+	// ReadErroredState downloads the state that was stored when an apply
+	// failed to upload its new state.
+	ReadErroredState(ctx context.Context, applyID string) (io.ReadCloser, error)
 }
 
 // applies implements Applys.
@@ -52,6 +57,7 @@ type Apply struct {
 	ResourceAdditions    int                    `jsonapi:"attr,resource-additions"`
 	ResourceChanges      int                    `jsonapi:"attr,resource-changes"`
 	ResourceDestructions int                    `jsonapi:"attr,resource-destructions"`
+	ResourceImports      int                    `jsonapi:"attr,resource-imports"`
 	Status               ApplyStatus            `jsonapi:"attr,status"`
 	StatusTimestamps     *ApplyStatusTimestamps `jsonapi:"attr,status-timestamps"`
 }
@@ -62,13 +68,14 @@ type ApplyStatusTimestamps struct {
 	ErroredAt       time.Time `json:"errored-at"`
 	FinishedAt      time.Time `json:"finished-at"`
 	ForceCanceledAt time.Time `json:"force-canceled-at"`
+	PendingAt       time.Time `json:"pending-at"`
 	QueuedAt        time.Time `json:"queued-at"`
 	StartedAt       time.Time `json:"started-at"`
 }
 
 // Read an apply by its ID.
 func (s *applies) Read(ctx context.Context, applyID string) (*Apply, error) {
-	if !validStringID(&applyID) {
+	if !validResourceID(&applyID, "apply-") {
 		return nil, errors.New("invalid value for apply ID")
 	}
 
@@ -87,9 +94,10 @@ func (s *applies) Read(ctx context.Context, applyID string) (*Apply, error) {
 	return a, nil
 }
 
-// Logs retrieves the logs of an apply.
+// Logs retrieves the logs of an apply. If the apply has not started yet,
+// an empty reader is returned.
 func (s *applies) Logs(ctx context.Context, applyID string) (io.Reader, error) {
-	if !validStringID(&applyID) {
+	if !validResourceID(&applyID, "apply-") {
 		return nil, errors.New("invalid value for apply ID")
 	}
 
@@ -99,9 +107,10 @@ func (s *applies) Logs(ctx context.Context, applyID string) (io.Reader, error) {
 		return nil, err
 	}
 
-	// Return an error if the log URL is empty.
+	// The log URL is only set once the apply has started, until then
+	// there are no logs to read.
 	if a.LogReadURL == "" {
-		return nil, fmt.Errorf("apply %s does not have a log URL", applyID)
+		return strings.NewReader(""), nil
 	}
 
 	u, err := url.Parse(a.LogReadURL)
@@ -130,3 +139,28 @@ func (s *applies) Logs(ctx context.Context, applyID string) (io.Reader, error) {
 		logURL: u,
 	}, nil
 }
+
+// ReadErroredState downloads the state that was stored when an apply failed
+// to upload its new state, so it can be recovered manually. The caller is
+// responsible for closing the returned reader. If the apply exists but did
+// not store an errored state, ErrErroredStateNotFound is returned.
+func (s *applies) ReadErroredState(ctx context.Context, applyID string) (io.ReadCloser, error) {
+	if !validResourceID(&applyID, "apply-") {
+		return nil, errors.New("invalid value for apply ID")
+	}
+
+	// The state is served by archivist, which the API redirects to.
+	u := fmt.Sprintf("applies/%s/errored-state", url.QueryEscape(applyID))
+	body, err := s.client.doDownload(ctx, u)
+	if errors.Is(err, ErrResourceNotFound) {
+		// Tell a missing errored state apart from a missing apply.
+		if _, rerr := s.Read(ctx, applyID); rerr == nil {
+			return nil, ErrErroredStateNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
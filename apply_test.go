@@ -2,8 +2,16 @@ package tfe
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,9 +33,9 @@ func TestAppliesRead(t *testing.T) {
 	})
 
 	t.Run("when the apply does not exist", func(t *testing.T) {
-		a, err := client.Applies.Read(ctx, "nonexisting")
+		a, err := client.Applies.Read(ctx, "apply-nonexisting")
 		assert.Nil(t, a)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid apply ID", func(t *testing.T) {
@@ -58,8 +66,249 @@ func TestAppliesLogs(t *testing.T) {
 	})
 
 	t.Run("when the log does not exist", func(t *testing.T) {
-		logs, err := client.Applies.Logs(ctx, "nonexisting")
+		logs, err := client.Applies.Logs(ctx, "apply-nonexisting")
 		assert.Nil(t, logs)
 		assert.Error(t, err)
 	})
 }
+
+func TestAppliesLogsFixture(t *testing.T) {
+	const logs = "Terraform v0.12.24\nApply complete! Resources: 1 added, 0 changed, 0 destroyed.\n"
+
+	// The first part of the logs is available right away, the rest only
+	// once the apply has finished.
+	var logURL string
+	status := ApplyRunning
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/applies/apply-123":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": "apply-123", "type": "applies", "attributes": {
+				"log-read-url": %q,
+				"resource-additions": 1,
+				"resource-changes": 0,
+				"resource-destructions": 0,
+				"status": %q,
+				"status-timestamps": {"queued-at": "2020-03-16T10:00:00Z"}
+			}}}`, logURL, status)
+		case "/logs/apply-123":
+			available := len("Terraform v0.12.24\n")
+			if status == ApplyFinished {
+				available = len(logs)
+			}
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if offset > available {
+				offset = available
+			}
+			end := offset + limit
+			if end > available {
+				end = available
+			}
+			w.Write([]byte(logs[offset:end]))
+
+			// Finish the apply once the first part has been read.
+			if offset == available {
+				status = ApplyFinished
+			}
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+	logURL = ts.URL + "/logs/apply-123"
+
+	ctx := context.Background()
+
+	a, err := client.Applies.Read(ctx, "apply-123")
+	require.NoError(t, err)
+	assert.Equal(t, ApplyRunning, a.Status)
+	assert.Equal(t, 1, a.ResourceAdditions)
+	assert.Equal(t, time.Date(2020, 3, 16, 10, 0, 0, 0, time.UTC), a.StatusTimestamps.QueuedAt)
+
+	logReader, err := client.Applies.Logs(ctx, "apply-123")
+	require.NoError(t, err)
+
+	body, err := ioutil.ReadAll(logReader)
+	require.NoError(t, err)
+	assert.Equal(t, logs, string(body))
+	assert.Equal(t, ApplyFinished, status)
+}
+
+func TestAppliesReadErroredStateFixture(t *testing.T) {
+	state, err := ioutil.ReadFile(filepath.Join("test-fixtures", "errored-state", "terraform.tfstate"))
+	require.NoError(t, err)
+
+	var archivistAuth string
+	archivist := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archivistAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(state)
+	}))
+	defer archivist.Close()
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/applies/apply-errored", "/api/v2/applies/apply-finished":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": %q, "type": "applies"}}`, filepath.Base(r.URL.Path))
+		case "/api/v2/applies/apply-errored/errored-state":
+			http.Redirect(w, r, archivist.URL+"/object/errored-state", http.StatusTemporaryRedirect)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when the apply stored an errored state", func(t *testing.T) {
+		body, err := client.Applies.ReadErroredState(ctx, "apply-errored")
+		require.NoError(t, err)
+		defer body.Close()
+
+		data, err := ioutil.ReadAll(body)
+		require.NoError(t, err)
+		assert.Equal(t, state, data)
+		assert.Empty(t, archivistAuth)
+	})
+
+	t.Run("when the apply did not store an errored state", func(t *testing.T) {
+		body, err := client.Applies.ReadErroredState(ctx, "apply-finished")
+		assert.Nil(t, body)
+		assert.True(t, errors.Is(err, ErrErroredStateNotFound), err)
+	})
+
+	t.Run("when the apply does not exist", func(t *testing.T) {
+		body, err := client.Applies.ReadErroredState(ctx, "apply-nonexisting")
+		assert.Nil(t, body)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with an invalid apply ID", func(t *testing.T) {
+		body, err := client.Applies.ReadErroredState(ctx, badIdentifier)
+		assert.Nil(t, body)
+		assert.EqualError(t, err, "invalid value for apply ID")
+	})
+}
+
+func TestAppliesLogsStreamingFixture(t *testing.T) {
+	chunks := []string{
+		"Terraform v0.12.24\n",
+		"null_resource.foo: Creating...\n",
+		"Apply complete! Resources: 1 added, 0 changed, 0 destroyed.\n",
+	}
+
+	// Every request for the logs appends the next chunk, and the apply
+	// finishes once all chunks are appended.
+	var logURL string
+	var logs string
+	var mu sync.Mutex
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/v2/applies/apply-123":
+			status := ApplyRunning
+			if len(chunks) == 0 {
+				status = ApplyFinished
+			}
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": "apply-123", "type": "applies", "attributes": {
+				"log-read-url": %q,
+				"status": %q
+			}}}`, logURL, status)
+		case "/logs/apply-123":
+			if len(chunks) > 0 {
+				logs += chunks[0]
+				chunks = chunks[1:]
+			}
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if offset > len(logs) {
+				offset = len(logs)
+			}
+			end := offset + limit
+			if end > len(logs) {
+				end = len(logs)
+			}
+			w.Write([]byte(logs[offset:end]))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+	logURL = ts.URL + "/logs/apply-123"
+
+	t.Run("when following the logs until the apply finished", func(t *testing.T) {
+		logReader, err := client.Applies.Logs(context.Background(), "apply-123")
+		require.NoError(t, err)
+
+		// The first chunk must be returned while the apply is running.
+		buf := make([]byte, 1024)
+		n, err := logReader.Read(buf)
+		require.NoError(t, err)
+		assert.Equal(t, "Terraform v0.12.24\n", string(buf[:n]))
+
+		rest, err := ioutil.ReadAll(logReader)
+		require.NoError(t, err)
+		assert.Equal(t, "null_resource.foo: Creating...\n"+
+			"Apply complete! Resources: 1 added, 0 changed, 0 destroyed.\n", string(rest))
+	})
+
+	t.Run("when the context is canceled while waiting for logs", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		mu.Lock()
+		chunks = nil
+		logs = ""
+		mu.Unlock()
+
+		// Pretend the apply is still running, so the reader keeps polling.
+		logReader, err := client.Applies.Logs(ctx, "apply-123")
+		require.NoError(t, err)
+		logReader.(*LogReader).done = func() (bool, error) { return false, nil }
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+		}()
+
+		start := time.Now()
+		_, err = ioutil.ReadAll(logReader)
+		assert.Equal(t, context.Canceled, err)
+		assert.True(t, time.Since(start) < 2*time.Second, "reading did not stop when canceled")
+	})
+}
+
+func TestAppliesReadFixture(t *testing.T) {
+	apply, err := ioutil.ReadFile("test-fixtures/apply/read.json")
+	require.NoError(t, err)
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(apply)
+	})
+	defer ts.Close()
+
+	a, err := client.Applies.Read(context.Background(), "apply-47MBvjwzBG8YKc2v")
+	require.NoError(t, err)
+
+	assert.Equal(t, ApplyErrored, a.Status)
+	assert.Equal(t, 1, a.ResourceAdditions)
+	assert.Equal(t, 0, a.ResourceChanges)
+	assert.Equal(t, 4, a.ResourceDestructions)
+	assert.Equal(t, 2, a.ResourceImports)
+
+	require.NotNil(t, a.StatusTimestamps)
+	assert.True(t, a.StatusTimestamps.QueuedAt.Equal(time.Date(2021, 7, 1, 8, 2, 11, 0, time.UTC)))
+	assert.True(t, a.StatusTimestamps.StartedAt.Equal(time.Date(2021, 7, 1, 8, 2, 14, 0, time.UTC)))
+
+	// Offsets other than UTC are kept, but refer to the same instant.
+	_, offset := a.StatusTimestamps.ErroredAt.Zone()
+	assert.Equal(t, 2*60*60, offset)
+	assert.True(t, a.StatusTimestamps.ErroredAt.Equal(time.Date(2021, 7, 1, 7, 48, 30, 123456000, time.UTC)))
+
+	assert.True(t, a.StatusTimestamps.FinishedAt.IsZero())
+}
@@ -0,0 +1,176 @@
+package tfe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+)
+
+// ErrNoCredentials is returned when the Terraform CLI configuration does
+// not contain any credentials for the requested host.
+var ErrNoCredentials = errors.New("no credentials found for host")
+
+// ConfigFromCLIConfig returns a default config structure populated with
+// the API token that the Terraform CLI stored for the host of the given
+// address, e.g. by running `terraform login`. If address is empty, the
+// address from DefaultConfig is used.
+//
+// The CLI configuration file (TF_CLI_CONFIG_FILE, ~/.terraformrc or
+// %APPDATA%\terraform.rc) is searched first, followed by the credentials
+// file (~/.terraform.d/credentials.tfrc.json or
+// %APPDATA%\terraform.d\credentials.tfrc.json). ErrNoCredentials is
+// returned if neither of them contains credentials for the host.
+func ConfigFromCLIConfig(address string) (*Config, error) {
+	config := DefaultConfig()
+	if address != "" {
+		config.Address = address
+	}
+
+	host, err := cliConfigHost(config.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	configFile, credentialsFile, err := cliConfigPaths(runtime.GOOS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range []string{configFile, credentialsFile} {
+		token, err := cliConfigToken(path, host)
+		if err == ErrNoCredentials {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		config.Token = token
+		return config, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoCredentials, host)
+}
+
+// cliConfigHost returns the hostname used as the credentials key for the
+// given address. Addresses without a scheme are treated as hostnames.
+func cliConfigHost(address string) (string, error) {
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid address: %v", err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid address: missing host")
+	}
+
+	return strings.ToLower(u.Host), nil
+}
+
+// cliConfigPaths returns the locations of the CLI configuration file and
+// the credentials file for the given operating system.
+func cliConfigPaths(goos string) (configFile, credentialsFile string, err error) {
+	var configDir string
+
+	if goos == "windows" {
+		appData := os.Getenv("APPDATA")
+		if appData == "" {
+			return "", "", fmt.Errorf("unable to locate the CLI configuration: APPDATA is not set")
+		}
+		configFile = filepath.Join(appData, "terraform.rc")
+		configDir = filepath.Join(appData, "terraform.d")
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", fmt.Errorf("unable to locate the CLI configuration: %v", err)
+		}
+		configFile = filepath.Join(home, ".terraformrc")
+		configDir = filepath.Join(home, ".terraform.d")
+	}
+
+	if v := os.Getenv("TF_CLI_CONFIG_FILE"); v != "" {
+		configFile = v
+	}
+
+	return configFile, filepath.Join(configDir, "credentials.tfrc.json"), nil
+}
+
+// cliConfigToken reads the file at path and returns the token stored for
+// host. A missing file is treated the same as a file without credentials.
+func cliConfigToken(path, host string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", ErrNoCredentials
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var credentials map[string]string
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		credentials, err = parseJSONCredentials(data)
+	} else {
+		credentials, err = parseHCLCredentials(data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error parsing %s: %v", path, err)
+	}
+
+	for h, token := range credentials {
+		if strings.ToLower(h) == host && token != "" {
+			return token, nil
+		}
+	}
+
+	return "", ErrNoCredentials
+}
+
+// parseJSONCredentials returns the tokens from the credentials object of a
+// JSON formatted CLI configuration or credentials file.
+func parseJSONCredentials(data []byte) (map[string]string, error) {
+	var file struct {
+		Credentials map[string]struct {
+			Token string `json:"token"`
+		} `json:"credentials"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	credentials := make(map[string]string, len(file.Credentials))
+	for host, c := range file.Credentials {
+		credentials[host] = c.Token
+	}
+
+	return credentials, nil
+}
+
+// parseHCLCredentials returns the tokens from the credentials blocks of an
+// HCL formatted CLI configuration file.
+func parseHCLCredentials(data []byte) (map[string]string, error) {
+	var file struct {
+		Credentials map[string]map[string]interface{} `hcl:"credentials"`
+	}
+	if err := hcl.Decode(&file, string(data)); err != nil {
+		return nil, err
+	}
+
+	credentials := make(map[string]string, len(file.Credentials))
+	for host, c := range file.Credentials {
+		if token, ok := c["token"].(string); ok {
+			credentials[host] = token
+		}
+	}
+
+	return credentials, nil
+}
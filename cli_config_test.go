@@ -0,0 +1,148 @@
+package tfe
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromCLIConfig(t *testing.T) {
+	home, err := ioutil.TempDir("", "go-tfe-home")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	defer setupCLIConfigEnvVars(home, "", "")()
+	defer setupEnvVars("", "")()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".terraform.d"), 0700))
+	require.NoError(t, ioutil.WriteFile(
+		filepath.Join(home, ".terraform.d", "credentials.tfrc.json"),
+		[]byte(`{"credentials": {"app.terraform.io": {"token": "json-token"}}}`),
+		0600,
+	))
+
+	t.Run("with a credentials file", func(t *testing.T) {
+		config, err := ConfigFromCLIConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, DefaultAddress, config.Address)
+		assert.Equal(t, "json-token", config.Token)
+	})
+
+	t.Run("with a terraformrc file", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(
+			filepath.Join(home, ".terraformrc"),
+			[]byte(`
+# Comment
+plugin_cache_dir = "$HOME/.terraform.d/plugin-cache"
+
+credentials "app.terraform.io" {
+  token = "hcl-token"
+}
+
+/* credentials "mytfe.local" { token = "commented" } */
+credentials "MyTFE.local:8443" {
+  token = "hcl-port-token" // Comment
+}
+`),
+			0600,
+		))
+		defer os.Remove(filepath.Join(home, ".terraformrc"))
+
+		config, err := ConfigFromCLIConfig("")
+		require.NoError(t, err)
+		assert.Equal(t, "hcl-token", config.Token)
+
+		config, err = ConfigFromCLIConfig("https://mytfe.local:8443")
+		require.NoError(t, err)
+		assert.Equal(t, "https://mytfe.local:8443", config.Address)
+		assert.Equal(t, "hcl-port-token", config.Token)
+	})
+
+	t.Run("with TF_CLI_CONFIG_FILE", func(t *testing.T) {
+		path := filepath.Join(home, "custom.tfrc")
+		require.NoError(t, ioutil.WriteFile(
+			path,
+			[]byte(`credentials "mytfe.local" { token = "custom-token" }`),
+			0600,
+		))
+		defer setupCLIConfigEnvVars(home, "", path)()
+
+		config, err := ConfigFromCLIConfig("mytfe.local")
+		require.NoError(t, err)
+		assert.Equal(t, "mytfe.local", config.Address)
+		assert.Equal(t, "custom-token", config.Token)
+	})
+
+	t.Run("without credentials for the host", func(t *testing.T) {
+		config, err := ConfigFromCLIConfig("https://unknown.local")
+		assert.Nil(t, config)
+		assert.True(t, errors.Is(err, ErrNoCredentials))
+	})
+
+	t.Run("with an invalid file", func(t *testing.T) {
+		path := filepath.Join(home, "invalid.tfrc")
+		require.NoError(t, ioutil.WriteFile(path, []byte(`credentials "mytfe.local" {`), 0600))
+		defer setupCLIConfigEnvVars(home, "", path)()
+
+		_, err := ConfigFromCLIConfig("mytfe.local")
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrNoCredentials))
+	})
+}
+
+func TestConfigFromCLIConfig_paths(t *testing.T) {
+	t.Run("on unix", func(t *testing.T) {
+		defer setupCLIConfigEnvVars("/home/user", "", "")()
+
+		configFile, credentialsFile, err := cliConfigPaths("linux")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("/home/user", ".terraformrc"), configFile)
+		assert.Equal(t, filepath.Join("/home/user", ".terraform.d", "credentials.tfrc.json"), credentialsFile)
+	})
+
+	t.Run("on windows", func(t *testing.T) {
+		defer setupCLIConfigEnvVars("", "/appdata", "")()
+
+		configFile, credentialsFile, err := cliConfigPaths("windows")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("/appdata", "terraform.rc"), configFile)
+		assert.Equal(t, filepath.Join("/appdata", "terraform.d", "credentials.tfrc.json"), credentialsFile)
+	})
+
+	t.Run("on windows without APPDATA", func(t *testing.T) {
+		defer setupCLIConfigEnvVars("", "", "")()
+
+		_, _, err := cliConfigPaths("windows")
+		assert.Error(t, err)
+	})
+
+	t.Run("with TF_CLI_CONFIG_FILE", func(t *testing.T) {
+		defer setupCLIConfigEnvVars("/home/user", "", "/etc/terraformrc")()
+
+		configFile, credentialsFile, err := cliConfigPaths("linux")
+		require.NoError(t, err)
+		assert.Equal(t, "/etc/terraformrc", configFile)
+		assert.Equal(t, filepath.Join("/home/user", ".terraform.d", "credentials.tfrc.json"), credentialsFile)
+	})
+}
+
+func setupCLIConfigEnvVars(home, appData, configFile string) func() {
+	origHome := os.Getenv("HOME")
+	origAppData := os.Getenv("APPDATA")
+	origConfigFile := os.Getenv("TF_CLI_CONFIG_FILE")
+
+	os.Setenv("HOME", home)
+	os.Setenv("APPDATA", appData)
+	os.Setenv("TF_CLI_CONFIG_FILE", configFile)
+
+	return func() {
+		os.Setenv("HOME", origHome)
+		os.Setenv("APPDATA", origAppData)
+		os.Setenv("TF_CLI_CONFIG_FILE", origConfigFile)
+	}
+}
@@ -0,0 +1,7 @@
+package tfe
+
+// Comment represents a comment on a run.
+type Comment struct {
+	ID   string `jsonapi:"primary,comments"`
+	Body string `jsonapi:"attr,body"`
+}
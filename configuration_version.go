@@ -1,12 +1,17 @@
 package tfe
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	slug "github.com/hashicorp/go-slug"
@@ -31,10 +36,35 @@ type ConfigurationVersions interface {
 	// Read a configuration version by its ID.
 	Read(ctx context.Context, cvID string) (*ConfigurationVersion, error)
 
+	// ReadWithOptions reads a configuration version by its ID using the
+	// given options.
+	ReadWithOptions(ctx context.Context, cvID string, options ConfigurationVersionReadOptions) (*ConfigurationVersion, error)
+
+	// ReadIngressAttributes reads the commit details of a configuration
+	// version that was ingressed from a VCS repository.
+	ReadIngressAttributes(ctx context.Context, cvID string) (*IngressAttributes, error)
+
 	// Upload packages and uploads Terraform configuration files. It requires
 	// the upload URL from a configuration version and the full path to the
 	// configuration files on disk.
 	Upload(ctx context.Context, url string, path string) error
+
+	// UploadTarGzip uploads an already packaged .tar.gz archive of
+	// Terraform configuration files to the upload URL of a configuration
+	// version.
+	UploadTarGzip(ctx context.Context, url string, archive io.Reader) error
+
+	// CreateAndUpload creates a new configuration version and uploads the
+	// Terraform configuration files in the given directory to it.
+	CreateAndUpload(ctx context.Context, workspaceID string, path string, options ConfigurationVersionCreateAndUploadOptions) (*ConfigurationVersionUpload, error)
+
+	// Download the uploaded Terraform configuration files of a
+	// configuration version as a .tar.gz archive.
+	Download(ctx context.Context, cvID string) (io.ReadCloser, error)
+
+	// Archive the uploaded Terraform configuration files of a
+	// configuration version to free storage.
+	Archive(ctx context.Context, cvID string) error
 }
 
 // configurationVersions implements ConfigurationVersions.
@@ -45,9 +75,11 @@ type configurationVersions struct {
 // ConfigurationStatus represents a configuration version status.
 type ConfigurationStatus string
 
-//List all available configuration version statuses.
+// List all available configuration version statuses.
 const (
+	ConfigurationArchived ConfigurationStatus = "archived"
 	ConfigurationErrored  ConfigurationStatus = "errored"
+	ConfigurationFetching ConfigurationStatus = "fetching"
 	ConfigurationPending  ConfigurationStatus = "pending"
 	ConfigurationUploaded ConfigurationStatus = "uploaded"
 )
@@ -78,11 +110,38 @@ type ConfigurationVersion struct {
 	AutoQueueRuns    bool                `jsonapi:"attr,auto-queue-runs"`
 	Error            string              `jsonapi:"attr,error"`
 	ErrorMessage     string              `jsonapi:"attr,error-message"`
+	Provisional      bool                `jsonapi:"attr,provisional"`
 	Source           ConfigurationSource `jsonapi:"attr,source"`
-	Speculative      bool                `jsonapi:"attr,speculative "`
+	Speculative      bool                `jsonapi:"attr,speculative"`
 	Status           ConfigurationStatus `jsonapi:"attr,status"`
 	StatusTimestamps *CVStatusTimestamps `jsonapi:"attr,status-timestamps"`
 	UploadURL        string              `jsonapi:"attr,upload-url"`
+
+	// Relations
+	IngressAttributes *IngressAttributes `jsonapi:"relation,ingress-attributes"`
+}
+
+// IngressAttributes contains the commit details of a configuration version
+// that was ingressed from a VCS repository.
+type IngressAttributes struct {
+	ID                string `jsonapi:"primary,ingress-attributes"`
+	Branch            string `jsonapi:"attr,branch"`
+	CloneURL          string `jsonapi:"attr,clone-url"`
+	CommitMessage     string `jsonapi:"attr,commit-message"`
+	CommitSHA         string `jsonapi:"attr,commit-sha"`
+	CommitURL         string `jsonapi:"attr,commit-url"`
+	CompareURL        string `jsonapi:"attr,compare-url"`
+	Identifier        string `jsonapi:"attr,identifier"`
+	IsPullRequest     bool   `jsonapi:"attr,is-pull-request"`
+	OnDefaultBranch   bool   `jsonapi:"attr,on-default-branch"`
+	PullRequestBody   string `jsonapi:"attr,pull-request-body"`
+	PullRequestNumber int    `jsonapi:"attr,pull-request-number"`
+	PullRequestTitle  string `jsonapi:"attr,pull-request-title"`
+	PullRequestURL    string `jsonapi:"attr,pull-request-url"`
+	SenderAvatarURL   string `jsonapi:"attr,sender-avatar-url"`
+	SenderHTMLURL     string `jsonapi:"attr,sender-html-url"`
+	SenderUsername    string `jsonapi:"attr,sender-username"`
+	Tag               string `jsonapi:"attr,tag"`
 }
 
 // CVStatusTimestamps holds the timestamps for individual configuration version
@@ -101,7 +160,7 @@ type ConfigurationVersionListOptions struct {
 
 // List returns all configuration versions of a workspace.
 func (s *configurationVersions) List(ctx context.Context, workspaceID string, options ConfigurationVersionListOptions) (*ConfigurationVersionList, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -132,14 +191,31 @@ type ConfigurationVersionCreateOptions struct {
 
 	// When true, this configuration version can only be used for planning.
 	Speculative *bool `jsonapi:"attr,speculative,omitempty"`
+
+	// When true, this configuration version does not become the current
+	// configuration version of the workspace until a run using it is
+	// applied.
+	Provisional *bool `jsonapi:"attr,provisional,omitempty"`
+}
+
+func (o ConfigurationVersionCreateOptions) valid() error {
+	// A speculative configuration version can never become the current
+	// configuration version, so it cannot be provisional.
+	if o.Speculative != nil && *o.Speculative && o.Provisional != nil && *o.Provisional {
+		return errors.New("only one of speculative or provisional can be set")
+	}
+	return nil
 }
 
 // Create is used to create a new configuration version. The created
 // configuration version will be usable once data is uploaded to it.
 func (s *configurationVersions) Create(ctx context.Context, workspaceID string, options ConfigurationVersionCreateOptions) (*ConfigurationVersion, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
 
 	// Make sure we don't send a user provided ID.
 	options.ID = ""
@@ -161,12 +237,34 @@ func (s *configurationVersions) Create(ctx context.Context, workspaceID string,
 
 // Read a configuration version by its ID.
 func (s *configurationVersions) Read(ctx context.Context, cvID string) (*ConfigurationVersion, error) {
-	if !validStringID(&cvID) {
+	return s.ReadWithOptions(ctx, cvID, ConfigurationVersionReadOptions{})
+}
+
+// ConfigurationVersionIncludeOpt represents the available options for
+// include query params.
+type ConfigurationVersionIncludeOpt string
+
+// List all available configuration version include options.
+const (
+	ConfigurationVersionIngressAttributes ConfigurationVersionIncludeOpt = "ingress_attributes"
+)
+
+// ConfigurationVersionReadOptions represents the options for reading a
+// configuration version.
+type ConfigurationVersionReadOptions struct {
+	// A list of relations to include.
+	Include []ConfigurationVersionIncludeOpt `url:"include,omitempty,comma"`
+}
+
+// ReadWithOptions reads a configuration version by its ID using the given
+// options.
+func (s *configurationVersions) ReadWithOptions(ctx context.Context, cvID string, options ConfigurationVersionReadOptions) (*ConfigurationVersion, error) {
+	if !validResourceID(&cvID, "cv-") {
 		return nil, errors.New("invalid value for configuration version ID")
 	}
 
 	u := fmt.Sprintf("configuration-versions/%s", url.QueryEscape(cvID))
-	req, err := s.client.newRequest("GET", u, nil)
+	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
 		return nil, err
 	}
@@ -180,26 +278,484 @@ func (s *configurationVersions) Read(ctx context.Context, cvID string) (*Configu
 	return cv, nil
 }
 
+// ReadIngressAttributes reads the commit details of a configuration version
+// that was ingressed from a VCS repository. For a configuration version with
+// another source, like one uploaded through the API, nil is returned.
+func (s *configurationVersions) ReadIngressAttributes(ctx context.Context, cvID string) (*IngressAttributes, error) {
+	cv, err := s.ReadWithOptions(ctx, cvID, ConfigurationVersionReadOptions{
+		Include: []ConfigurationVersionIncludeOpt{ConfigurationVersionIngressAttributes},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cv.IngressAttributes, nil
+}
+
 // Upload packages and uploads Terraform configuration files. It requires the
 // upload URL from a configuration version and the path to the configuration
 // files on disk.
 func (s *configurationVersions) Upload(ctx context.Context, url, path string) error {
-	file, err := os.Stat(path)
+	body, _, err := packConfiguration(path, true)
 	if err != nil {
 		return err
 	}
+
+	return s.client.doUpload(ctx, url, body)
+}
+
+// packConfiguration packs the Terraform configuration files in the
+// directory at path into a .tar.gz archive, honoring any .terraformignore
+// file. When dereference is true, symlinks with a target outside of the
+// directory are replaced by their target, otherwise they are rejected.
+func packConfiguration(path string, dereference bool) (*bytes.Buffer, *slug.Meta, error) {
+	file, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
 	if !file.Mode().IsDir() {
-		return errors.New("path needs to be an existing directory")
+		return nil, nil, errors.New("path needs to be an existing directory")
+	}
+
+	if !dereference {
+		if err := checkSymlinks(path); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	body := bytes.NewBuffer(nil)
 
-	_, err = slug.Pack(path, body, true)
+	meta, err := slug.Pack(path, body, dereference)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return body, meta, nil
+}
+
+// checkSymlinks returns an error for the first symlink in the directory at
+// root that has a target outside of root.
+func checkSymlinks(root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return fmt.Errorf("failed to get symbolic link destination for %q: %v", path, err)
+		}
+
+		if !withinDir(root, target) {
+			return fmt.Errorf("symbolic link %q has a target outside of %q", path, root)
+		}
+
+		return nil
+	})
+}
+
+// UploadTarGzip uploads an already packaged .tar.gz archive of Terraform
+// configuration files to the upload URL of a configuration version. The
+// archive is streamed when it implements io.ReadSeeker, like an *os.File,
+// and read into memory first otherwise. The API token is never sent to the
+// upload URL.
+func (s *configurationVersions) UploadTarGzip(ctx context.Context, url string, archive io.Reader) error {
+	if url == "" {
+		return errors.New("invalid value for upload URL")
+	}
+	if archive == nil {
+		return errors.New("archive is required")
+	}
+
+	return s.client.doUpload(ctx, url, archive)
+}
+
+// ConfigurationVersionCreateAndUploadOptions represents the options for
+// creating a configuration version and uploading a directory to it.
+type ConfigurationVersionCreateAndUploadOptions struct {
+	// The options used to create the configuration version.
+	ConfigurationVersionCreateOptions
+
+	// When true, symlinks with a target outside of the uploaded directory
+	// are replaced by their target. Otherwise such symlinks are rejected.
+	DereferenceSymlinks bool
+
+	// When true, wait until the uploaded configuration files are processed
+	// and the configuration version is uploaded.
+	WaitForUpload bool
+}
+
+// ConfigurationVersionUpload represents the result of creating a
+// configuration version and uploading a directory to it.
+type ConfigurationVersionUpload struct {
+	// The created configuration version. When waiting for the upload, it
+	// reflects the final status of the configuration version.
+	ConfigurationVersion *ConfigurationVersion
+
+	// The files contained in the uploaded archive.
+	Files []string
+
+	// The total size of the packed files in bytes.
+	Size int64
+
+	// The size of the uploaded (compressed) archive in bytes.
+	ArchiveSize int64
+}
+
+// CreateAndUpload creates a new configuration version and uploads the
+// Terraform configuration files in the directory at path to it. The
+// directory is packed before creating the configuration version, so invalid
+// directories do not result in configuration versions that are never used.
+func (s *configurationVersions) CreateAndUpload(ctx context.Context, workspaceID, path string, options ConfigurationVersionCreateAndUploadOptions) (*ConfigurationVersionUpload, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+
+	body, meta, err := packConfiguration(path, options.DereferenceSymlinks)
+	if err != nil {
+		return nil, err
+	}
+
+	cv, err := s.Create(ctx, workspaceID, options.ConfigurationVersionCreateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveSize := int64(body.Len())
+	if err := s.client.doUpload(ctx, cv.UploadURL, bytes.NewReader(body.Bytes())); err != nil {
+		return nil, err
+	}
+
+	if options.WaitForUpload {
+		cv, err = s.waitForUpload(ctx, cv.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ConfigurationVersionUpload{
+		ConfigurationVersion: cv,
+		Files:                meta.Files,
+		Size:                 meta.Size,
+		ArchiveSize:          archiveSize,
+	}, nil
+}
+
+// waitForUpload polls the configuration version until the uploaded files
+// are processed, and returns an error if processing them failed.
+func (s *configurationVersions) waitForUpload(ctx context.Context, cvID string) (*ConfigurationVersion, error) {
+	for i := 0; ; i++ {
+		cv, err := s.Read(ctx, cvID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch cv.Status {
+		case ConfigurationUploaded:
+			return cv, nil
+		case ConfigurationErrored:
+			if cv.ErrorMessage != "" {
+				return nil, fmt.Errorf("configuration version %s errored: %s", cv.ID, cv.ErrorMessage)
+			}
+			return nil, fmt.Errorf("configuration version %s errored", cv.ID)
+		}
+
+		timer := time.NewTimer(backoff(500, 2000, i))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Download the uploaded Terraform configuration files of a configuration
+// version as a .tar.gz archive, which can be extracted with
+// UnpackConfiguration. The caller is responsible for closing the returned
+// reader. If the configuration version was never uploaded or has been
+// archived, a *ConfigurationVersionNotAvailableError is returned.
+func (s *configurationVersions) Download(ctx context.Context, cvID string) (io.ReadCloser, error) {
+	if !validResourceID(&cvID, "cv-") {
+		return nil, errors.New("invalid value for configuration version ID")
+	}
+
+	// The archive is served by archivist, which the API redirects to.
+	u := fmt.Sprintf("configuration-versions/%s/download", url.QueryEscape(cvID))
+	body, err := s.client.doDownload(ctx, u)
+	if err == nil {
+		return body, nil
+	}
+
+	// Check the status of the configuration version to tell a missing
+	// archive apart from a missing configuration version.
+	cv, rerr := s.Read(ctx, cvID)
+	if rerr != nil {
+		return nil, err
+	}
+	if cv.Status != ConfigurationUploaded {
+		return nil, &ConfigurationVersionNotAvailableError{Status: cv.Status}
+	}
+
+	return nil, err
+}
+
+// UnpackConfiguration extracts a .tar.gz archive of Terraform configuration
+// files, like the one returned by Download, into the directory at dst. It
+// is the inverse of packing a directory for Upload. Entries that would end
+// up outside of dst, either directly or by way of symlinks in the archive,
+// and symlinks pointing outside of dst result in an error.
+func UnpackConfiguration(r io.Reader, dst string) error {
+	uncompressed, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to uncompress archive: %v", err)
+	}
+	defer uncompressed.Close()
+
+	dst, err = filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %q: %v", dst, err)
+	}
+
+	// All checks are done against the real path of the destination, as
+	// the destination itself may be reached through a symlink.
+	dst, err = filepath.EvalSymlinks(dst)
 	if err != nil {
 		return err
 	}
 
-	req, err := s.client.newRequest("PUT", url, body)
+	// Keep track of the created symlinks, so they can be checked again once
+	// all entries are unpacked. A symlink with a target that does not exist
+	// yet may point outside of dst after the rest of the archive is unpacked.
+	var symlinks []*tar.Header
+
+	untar := tar.NewReader(uncompressed)
+	for {
+		header, err := untar.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to untar archive: %v", err)
+		}
+
+		path, err := unpackPath(dst, header.Name)
+		if err != nil {
+			return err
+		}
+		if path == dst {
+			// The destination itself is created up front.
+			if header.Typeflag == tar.TypeDir {
+				continue
+			}
+			return fmt.Errorf("archive entry %q is outside of the destination", header.Name)
+		}
+
+		// Resolve any symlinks that were unpacked earlier, to make sure the
+		// entry is really written inside of the destination.
+		dir, err := resolvePath(dst, filepath.Dir(path))
+		if err != nil {
+			return fmt.Errorf("failed to resolve %q: %v", header.Name, err)
+		}
+		if !withinDir(dst, dir) {
+			return fmt.Errorf("archive entry %q is outside of the destination", header.Name)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %q: %v", dir, err)
+		}
+		path = filepath.Join(dir, filepath.Base(path))
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			path, err = resolvePath(dir, filepath.Base(path))
+			if err != nil {
+				return fmt.Errorf("failed to resolve %q: %v", header.Name, err)
+			}
+			if !withinDir(dst, path) {
+				return fmt.Errorf("archive entry %q is outside of the destination", header.Name)
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return fmt.Errorf("failed to create directory %q: %v", path, err)
+			}
+
+		case tar.TypeSymlink:
+			// Resolve the target relative to the symlink, to make sure
+			// it does not point outside of the destination.
+			target, err := resolvePath(dir, header.Linkname)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %q: %v", header.Name, err)
+			}
+			if !withinDir(dst, target) {
+				return fmt.Errorf("symbolic link %q has a target outside of the destination", header.Name)
+			}
+			if err := os.Symlink(header.Linkname, path); err != nil {
+				return fmt.Errorf("failed to create symbolic link %q: %v", path, err)
+			}
+			symlinks = append(symlinks, header)
+
+		case tar.TypeReg, tar.TypeRegA:
+			if err := unpackFile(path, header.FileInfo().Mode(), untar); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("failed to create %q: unsupported type %c", header.Name, header.Typeflag)
+		}
+	}
+
+	for _, header := range symlinks {
+		path, err := unpackPath(dst, header.Name)
+		if err != nil {
+			return err
+		}
+		target, err := resolvePath(dst, path)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %q: %v", header.Name, err)
+		}
+		if !withinDir(dst, target) {
+			return fmt.Errorf("symbolic link %q has a target outside of the destination", header.Name)
+		}
+	}
+
+	return nil
+}
+
+// unpackPath returns the path in dst of an archive entry with the given
+// name, or an error if the entry would end up outside of dst.
+func unpackPath(dst, name string) (string, error) {
+	path := filepath.Join(dst, filepath.FromSlash(strings.TrimPrefix(name, "/")))
+	if !withinDir(dst, path) {
+		return "", fmt.Errorf("archive entry %q is outside of the destination", name)
+	}
+	return path, nil
+}
+
+// maxSymlinks is the maximum number of symlinks resolvePath follows before
+// giving up, which protects against symlink loops.
+const maxSymlinks = 255
+
+// resolvePath returns the real path of name, following any symlinks in it.
+// A relative name is resolved against base, which must be a real absolute
+// path. Unlike filepath.EvalSymlinks, the path does not have to exist: the
+// part of it that does not exist is joined to the resolved part as is.
+func resolvePath(base, name string) (string, error) {
+	resolved := base
+	if filepath.IsAbs(name) {
+		resolved = filepath.VolumeName(name) + string(filepath.Separator)
+		name = name[len(filepath.VolumeName(name)):]
+	}
+
+	// Components are consumed one by one and never cleaned up front, as a
+	// ".." following a symlink has to be applied to the symlink target.
+	rest := strings.Split(filepath.ToSlash(name), "/")
+	for links := 0; len(rest) > 0; {
+		elem := rest[0]
+		rest = rest[1:]
+
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, elem)
+		info, err := os.Lstat(next)
+		if os.IsNotExist(err) {
+			// Nothing below a missing path can be a symlink (yet).
+			return filepath.Join(append([]string{next}, rest...)...), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", fmt.Errorf("too many levels of symbolic links")
+		}
+
+		link, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(link) {
+			resolved = filepath.VolumeName(link) + string(filepath.Separator)
+			link = link[len(filepath.VolumeName(link)):]
+		}
+		rest = append(strings.Split(filepath.ToSlash(link), "/"), rest...)
+	}
+
+	return resolved, nil
+}
+
+// withinDir reports if path is dir itself or inside of dir. Both paths must
+// be absolute.
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// unpackFile writes the contents of a regular file in an archive to path.
+func unpackFile(path string, mode os.FileMode, r io.Reader) error {
+	// Never write through an existing symlink, which could point anywhere.
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("failed to create file %q: path is a symbolic link", path)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create file %q: %v", path, err)
+	}
+
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write file %q: %v", path, err)
+	}
+
+	// Restore the file mode after writing, as it may be read-only.
+	if err := os.Chmod(path, mode.Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions on %q: %v", path, err)
+	}
+
+	return nil
+}
+
+// Archive the uploaded Terraform configuration files of a configuration
+// version to free storage. The archiving happens asynchronously, until it
+// is done the configuration version keeps its current status. The current
+// configuration version of a workspace and configuration versions that
+// were ingressed from a VCS repository cannot be archived, in which case
+// an error wrapping a *ConfigurationVersionNotArchivableError is returned.
+func (s *configurationVersions) Archive(ctx context.Context, cvID string) error {
+	if !validResourceID(&cvID, "cv-") {
+		return errors.New("invalid value for configuration version ID")
+	}
+
+	u := fmt.Sprintf("configuration-versions/%s/actions/archive", url.QueryEscape(cvID))
+	req, err := s.client.newRequest("POST", u, nil)
 	if err != nil {
 		return err
 	}
@@ -1,10 +1,25 @@
 package tfe
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	slug "github.com/hashicorp/go-slug"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -130,9 +145,9 @@ func TestConfigurationVersionsRead(t *testing.T) {
 	})
 
 	t.Run("when the configuration version does not exist", func(t *testing.T) {
-		cv, err := client.ConfigurationVersions.Read(ctx, "nonexisting")
+		cv, err := client.ConfigurationVersions.Read(ctx, "cv-nonexisting")
 		assert.Nil(t, cv)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid configuration version id", func(t *testing.T) {
@@ -193,3 +208,631 @@ func TestConfigurationVersionsUpload(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestConfigurationVersionsListFixture(t *testing.T) {
+	list, err := ioutil.ReadFile("test-fixtures/configuration-version/list.json")
+	require.NoError(t, err)
+
+	var path string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(list)
+	})
+	defer ts.Close()
+
+	cvl, err := client.ConfigurationVersions.List(context.Background(), "ws-123", ConfigurationVersionListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v2/workspaces/ws-123/configuration-versions", path)
+	require.Len(t, cvl.Items, 3)
+	assert.Equal(t, 3, cvl.TotalCount)
+
+	uploaded := cvl.Items[0]
+	assert.Equal(t, ConfigurationUploaded, uploaded.Status)
+	assert.Equal(t, ConfigurationSourceAPI, uploaded.Source)
+	assert.True(t, uploaded.Speculative)
+	assert.False(t, uploaded.AutoQueueRuns)
+	assert.Empty(t, uploaded.Error)
+	assert.Equal(t, time.Date(2021, 3, 2, 11, 5, 9, 0, time.UTC), uploaded.StatusTimestamps.FinishedAt.UTC())
+
+	errored := cvl.Items[1]
+	assert.Equal(t, ConfigurationErrored, errored.Status)
+	assert.Equal(t, ConfigurationSourceGithub, errored.Source)
+	assert.False(t, errored.Speculative)
+	assert.Equal(t, "fetch_failed", errored.Error)
+	assert.Equal(t, "Failed to fetch the configuration from the VCS provider.", errored.ErrorMessage)
+
+	pending := cvl.Items[2]
+	assert.Equal(t, ConfigurationPending, pending.Status)
+	assert.True(t, pending.AutoQueueRuns)
+	assert.NotEmpty(t, pending.UploadURL)
+}
+
+func TestConfigurationVersionsUploadTarGzipFixture(t *testing.T) {
+	const size = 1 << 20
+
+	var uploaded []byte
+	var auth, contentType string
+	var contentLength int64
+	archivist := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/object/expired" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("upload URL has expired\n"))
+			return
+		}
+
+		auth = r.Header.Get("Authorization")
+		contentType = r.Header.Get("Content-Type")
+		contentLength = r.ContentLength
+
+		var err error
+		uploaded, err = ioutil.ReadAll(io.LimitReader(r.Body, size+1))
+		if err != nil {
+			t.Errorf("error reading upload: %v", err)
+		}
+	}))
+	defer archivist.Close()
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	archive := bytes.Repeat([]byte{0x1f, 0x8b, 0x08, 0x00}, size/4)
+
+	t.Run("with a streamed archive", func(t *testing.T) {
+		// Hide the io.ReadSeeker implementation of the bytes.Reader.
+		r := struct{ io.Reader }{bytes.NewReader(archive)}
+
+		err := client.ConfigurationVersions.UploadTarGzip(ctx, archivist.URL+"/object/cv-123", r)
+		require.NoError(t, err)
+		assert.Equal(t, archive, uploaded)
+		assert.Empty(t, auth)
+		assert.Equal(t, "application/octet-stream", contentType)
+	})
+
+	t.Run("with a seekable archive", func(t *testing.T) {
+		err := client.ConfigurationVersions.UploadTarGzip(ctx, archivist.URL+"/object/cv-123", bytes.NewReader(archive))
+		require.NoError(t, err)
+		assert.Equal(t, archive, uploaded)
+		assert.Equal(t, int64(size), contentLength)
+		assert.Empty(t, auth)
+	})
+
+	t.Run("when archivist returns an error", func(t *testing.T) {
+		err := client.ConfigurationVersions.UploadTarGzip(ctx, archivist.URL+"/object/expired", bytes.NewReader(archive))
+		assert.True(t, errors.Is(err, ErrUnexpectedStatus), err)
+		assert.EqualError(t, err, "unexpected status code: 403 Forbidden: upload URL has expired")
+	})
+
+	t.Run("without an upload URL", func(t *testing.T) {
+		err := client.ConfigurationVersions.UploadTarGzip(ctx, "", bytes.NewReader(archive))
+		assert.EqualError(t, err, "invalid value for upload URL")
+	})
+
+	t.Run("without an archive", func(t *testing.T) {
+		err := client.ConfigurationVersions.UploadTarGzip(ctx, archivist.URL+"/object/cv-123", nil)
+		assert.EqualError(t, err, "archive is required")
+	})
+}
+
+func TestConfigurationVersionsCreateAndUploadFixture(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-tfe-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	root := filepath.Join(dir, "config")
+	files := map[string]string{
+		"main.tf":                "module \"network\" {\n  source = \"./modules/network\"\n}\n",
+		"modules/network/vpc.tf": "resource \"null_resource\" \"vpc\" {}\n",
+		"secrets.auto.tfvars":    "password = \"hunter2\"\n",
+		".terraformignore":       "secrets.auto.tfvars\n",
+		".terraform/plugins/foo": "not uploaded by default\n",
+		"../outside/shared.tf":   "locals {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	var uploaded []byte
+	var cvReads int
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v2/workspaces/ws-123/configuration-versions":
+			cvReads = 0
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(201)
+			fmt.Fprintf(w, `{"data": {"id": "cv-123", "type": "configuration-versions", "attributes": {
+				"status": "pending",
+				"upload-url": %q
+			}}}`, "http://"+r.Host+"/object/cv-123")
+		case "PUT /object/cv-123":
+			uploaded, _ = ioutil.ReadAll(r.Body)
+		case "GET /api/v2/configuration-versions/cv-123":
+			cvReads++
+			status := ConfigurationPending
+			if cvReads > 1 {
+				status = ConfigurationUploaded
+			}
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": "cv-123", "type": "configuration-versions", "attributes": {
+				"status": %q
+			}}}`, status)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	// unpacked returns the sorted list of files in the uploaded archive.
+	unpacked := func(t *testing.T) []string {
+		dst, err := ioutil.TempDir(dir, "unpacked")
+		require.NoError(t, err)
+		require.NoError(t, slug.Unpack(bytes.NewReader(uploaded), dst))
+
+		var names []string
+		err = filepath.Walk(dst, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(dst, path)
+			names = append(names, filepath.ToSlash(rel))
+			return err
+		})
+		require.NoError(t, err)
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("with valid options", func(t *testing.T) {
+		upload, err := client.ConfigurationVersions.CreateAndUpload(ctx, "ws-123", root, ConfigurationVersionCreateAndUploadOptions{
+			WaitForUpload: true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, ConfigurationUploaded, upload.ConfigurationVersion.Status)
+		assert.Equal(t, 2, cvReads)
+		assert.Equal(t, int64(len(uploaded)), upload.ArchiveSize)
+		assert.Equal(t, int64(len(files["main.tf"])+len(files["modules/network/vpc.tf"])+len(files[".terraformignore"])), upload.Size)
+		assert.Equal(t, []string{".terraformignore", "main.tf", "modules/network/vpc.tf"}, unpacked(t))
+	})
+
+	t.Run("without waiting for the upload", func(t *testing.T) {
+		upload, err := client.ConfigurationVersions.CreateAndUpload(ctx, "ws-123", root, ConfigurationVersionCreateAndUploadOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, ConfigurationPending, upload.ConfigurationVersion.Status)
+		assert.Equal(t, 0, cvReads)
+	})
+
+	// Symlinks are not supported on all platforms.
+	link := filepath.Join(root, "shared.tf")
+	if err := os.Symlink(filepath.Join(dir, "outside", "shared.tf"), link); err != nil {
+		t.Skipf("unable to create symlink: %v", err)
+	}
+
+	t.Run("with a symlink outside of the directory", func(t *testing.T) {
+		uploaded = nil
+
+		upload, err := client.ConfigurationVersions.CreateAndUpload(ctx, "ws-123", root, ConfigurationVersionCreateAndUploadOptions{})
+		assert.Nil(t, upload)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has a target outside of")
+		assert.Nil(t, uploaded)
+	})
+
+	t.Run("when dereferencing symlinks", func(t *testing.T) {
+		_, err := client.ConfigurationVersions.CreateAndUpload(ctx, "ws-123", root, ConfigurationVersionCreateAndUploadOptions{
+			DereferenceSymlinks: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{".terraformignore", "main.tf", "modules/network/vpc.tf", "shared.tf"}, unpacked(t))
+	})
+
+	t.Run("with a file instead of a directory", func(t *testing.T) {
+		upload, err := client.ConfigurationVersions.CreateAndUpload(ctx, "ws-123", filepath.Join(root, "main.tf"), ConfigurationVersionCreateAndUploadOptions{})
+		assert.Nil(t, upload)
+		assert.EqualError(t, err, "path needs to be an existing directory")
+	})
+
+	t.Run("with an invalid workspace ID", func(t *testing.T) {
+		upload, err := client.ConfigurationVersions.CreateAndUpload(ctx, badIdentifier, root, ConfigurationVersionCreateAndUploadOptions{})
+		assert.Nil(t, upload)
+		assert.EqualError(t, err, "invalid value for workspace ID")
+	})
+}
+
+func TestConfigurationVersionsDownloadFixture(t *testing.T) {
+	archive, _, err := packConfiguration("test-fixtures/config-version", false)
+	require.NoError(t, err)
+
+	var archivistAuth string
+	archivist := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archivistAuth = r.Header.Get("Authorization")
+		w.Write(archive.Bytes())
+	}))
+	defer archivist.Close()
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/configuration-versions/cv-uploaded/download":
+			http.Redirect(w, r, archivist.URL+"/object/cv-uploaded", http.StatusFound)
+		case "/api/v2/configuration-versions/cv-uploaded",
+			"/api/v2/configuration-versions/cv-pending",
+			"/api/v2/configuration-versions/cv-archived":
+			id := filepath.Base(r.URL.Path)
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": %q, "type": "configuration-versions", "attributes": {
+				"status": %q
+			}}}`, id, id[len("cv-"):])
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when the configuration version is uploaded", func(t *testing.T) {
+		body, err := client.ConfigurationVersions.Download(ctx, "cv-uploaded")
+		require.NoError(t, err)
+		defer body.Close()
+
+		dst, err := ioutil.TempDir("", "go-tfe-download")
+		require.NoError(t, err)
+		defer os.RemoveAll(dst)
+
+		require.NoError(t, UnpackConfiguration(body, dst))
+		assert.Empty(t, archivistAuth)
+
+		expected, err := ioutil.ReadFile("test-fixtures/config-version/main.tf")
+		require.NoError(t, err)
+		unpacked, err := ioutil.ReadFile(filepath.Join(dst, "main.tf"))
+		require.NoError(t, err)
+		assert.Equal(t, expected, unpacked)
+	})
+
+	for _, status := range []ConfigurationStatus{ConfigurationPending, ConfigurationArchived} {
+		t.Run(fmt.Sprintf("when the configuration version is %s", status), func(t *testing.T) {
+			body, err := client.ConfigurationVersions.Download(ctx, "cv-"+string(status))
+			assert.Nil(t, body)
+			assert.True(t, errors.Is(err, ErrConfigurationVersionNotAvailable), err)
+
+			var notAvailable *ConfigurationVersionNotAvailableError
+			require.True(t, errors.As(err, &notAvailable))
+			assert.Equal(t, status, notAvailable.Status)
+		})
+	}
+
+	t.Run("when the configuration version does not exist", func(t *testing.T) {
+		body, err := client.ConfigurationVersions.Download(ctx, "cv-nonexisting")
+		assert.Nil(t, body)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with an invalid configuration version ID", func(t *testing.T) {
+		body, err := client.ConfigurationVersions.Download(ctx, badIdentifier)
+		assert.Nil(t, body)
+		assert.EqualError(t, err, "invalid value for configuration version ID")
+	})
+}
+
+func TestUnpackConfigurationPathTraversal(t *testing.T) {
+	// archive returns a .tar.gz archive containing the given entries.
+	archive := func(t *testing.T, headers ...*tar.Header) io.Reader {
+		buf := bytes.NewBuffer(nil)
+		gzipW := gzip.NewWriter(buf)
+		tarW := tar.NewWriter(gzipW)
+		for _, h := range headers {
+			require.NoError(t, tarW.WriteHeader(h))
+			if h.Typeflag == tar.TypeReg {
+				_, err := tarW.Write(make([]byte, h.Size))
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, tarW.Close())
+		require.NoError(t, gzipW.Close())
+		return buf
+	}
+
+	dir, err := ioutil.TempDir("", "go-tfe-unpack")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cases := map[string]struct {
+		headers []*tar.Header
+		err     string
+	}{
+		"with nested files": {
+			headers: []*tar.Header{
+				{Name: "modules/", Typeflag: tar.TypeDir, Mode: 0755},
+				{Name: "modules/vpc.tf", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+				{Name: "/main.tf", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+				{Name: "vpc.tf", Typeflag: tar.TypeSymlink, Linkname: "modules/vpc.tf"},
+				{Name: "lib", Typeflag: tar.TypeSymlink, Linkname: "modules"},
+				{Name: "lib/extra.tf", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+			},
+		},
+		"with an entry outside of the destination": {
+			headers: []*tar.Header{
+				{Name: "../../evil.tf", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+			},
+			err: `archive entry "../../evil.tf" is outside of the destination`,
+		},
+		"with a relative symlink outside of the destination": {
+			headers: []*tar.Header{
+				{Name: "modules/evil", Typeflag: tar.TypeSymlink, Linkname: "../../outside"},
+			},
+			err: `symbolic link "modules/evil" has a target outside of the destination`,
+		},
+		"with chained symlinks outside of the destination": {
+			headers: []*tar.Header{
+				{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+				{Name: "b/evil", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+			},
+			err: `symbolic link "a/b" has a target outside of the destination`,
+		},
+		"with a symlink target leaving through another symlink": {
+			headers: []*tar.Header{
+				{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+				{Name: "b", Typeflag: tar.TypeSymlink, Linkname: "a/.."},
+			},
+			err: `symbolic link "b" has a target outside of the destination`,
+		},
+		"with a symlink that ends up outside of the destination": {
+			headers: []*tar.Header{
+				{Name: "b", Typeflag: tar.TypeSymlink, Linkname: "a/../evil"},
+				{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+			},
+			err: `symbolic link "b" has a target outside of the destination`,
+		},
+		"with an absolute symlink outside of the destination": {
+			headers: []*tar.Header{
+				{Name: "evil", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+			},
+			err: `symbolic link "evil" has a target outside of the destination`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			dst, err := ioutil.TempDir(dir, "dst")
+			require.NoError(t, err)
+
+			err = UnpackConfiguration(archive(t, c.headers...), dst)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, name := range []string{"main.tf", "modules/vpc.tf", "vpc.tf", "modules/extra.tf"} {
+				_, err := os.Stat(filepath.Join(dst, filepath.FromSlash(name)))
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	// Nothing should have been written next to the destinations.
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.True(t, strings.HasPrefix(e.Name(), "dst"), e.Name())
+	}
+}
+
+func TestConfigurationVersionsArchiveConflicts(t *testing.T) {
+	var method string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/configuration-versions/cv-old/actions/archive":
+			method = r.Method
+			w.WriteHeader(202)
+		case "/api/v2/configuration-versions/cv-current/actions/archive":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(409)
+			w.Write([]byte(`{"errors": [{
+				"status": "409",
+				"title": "conflict",
+				"detail": "Cannot archive the current configuration version of a workspace"
+			}]}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with an old configuration version", func(t *testing.T) {
+		err := client.ConfigurationVersions.Archive(ctx, "cv-old")
+		require.NoError(t, err)
+		assert.Equal(t, "POST", method)
+	})
+
+	t.Run("with the current configuration version", func(t *testing.T) {
+		err := client.ConfigurationVersions.Archive(ctx, "cv-current")
+		assert.True(t, errors.Is(err, ErrConfigurationVersionNotArchivable), err)
+
+		var notArchivable *ConfigurationVersionNotArchivableError
+		require.True(t, errors.As(err, &notArchivable))
+		assert.Equal(t, "Cannot archive the current configuration version of a workspace", notArchivable.Detail)
+		assert.EqualError(t, err, "configuration version is not archivable: Cannot archive the current configuration version of a workspace")
+	})
+
+	t.Run("when the configuration version does not exist", func(t *testing.T) {
+		err := client.ConfigurationVersions.Archive(ctx, "cv-nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with an invalid configuration version ID", func(t *testing.T) {
+		err := client.ConfigurationVersions.Archive(ctx, badIdentifier)
+		assert.EqualError(t, err, "invalid value for configuration version ID")
+	})
+}
+
+func TestConfigurationVersionsReadIngressAttributesFixture(t *testing.T) {
+	vcs, err := ioutil.ReadFile("test-fixtures/configuration-version/read-with-ingress-attributes.json")
+	require.NoError(t, err)
+
+	var include string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/configuration-versions/cv-TrHjxIzad7Ae9i8a":
+			include = r.URL.Query().Get("include")
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(vcs)
+		case "/api/v2/configuration-versions/cv-api":
+			include = r.URL.Query().Get("include")
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write([]byte(`{"data": {"id": "cv-api", "type": "configuration-versions",
+				"attributes": {"source": "tfe-api", "status": "uploaded"},
+				"relationships": {"ingress-attributes": {"data": null}}
+			}}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when reading with includes", func(t *testing.T) {
+		cv, err := client.ConfigurationVersions.ReadWithOptions(ctx, "cv-TrHjxIzad7Ae9i8a", ConfigurationVersionReadOptions{
+			Include: []ConfigurationVersionIncludeOpt{ConfigurationVersionIngressAttributes},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "ingress_attributes", include)
+		assert.Equal(t, ConfigurationSourceGithub, cv.Source)
+		require.NotNil(t, cv.IngressAttributes)
+		assert.Equal(t, "1e1c1018d1bbc0b8517d072718e0d87c1a0eda95", cv.IngressAttributes.CommitSHA)
+	})
+
+	t.Run("with a VCS configuration version", func(t *testing.T) {
+		ia, err := client.ConfigurationVersions.ReadIngressAttributes(ctx, "cv-TrHjxIzad7Ae9i8a")
+		require.NoError(t, err)
+		assert.Equal(t, "ingress_attributes", include)
+
+		assert.Equal(t, &IngressAttributes{
+			ID:                "ia-i4MrTxmQXYxH2nYD",
+			Branch:            "add-cache",
+			CloneURL:          "https://github.com/hashicorp/foobar.git",
+			CommitMessage:     "Add a cache in front of the database",
+			CommitSHA:         "1e1c1018d1bbc0b8517d072718e0d87c1a0eda95",
+			CommitURL:         "https://github.com/hashicorp/foobar/commit/1e1c1018d1bbc0b8517d072718e0d87c1a0eda95",
+			CompareURL:        "https://github.com/hashicorp/foobar/pull/4",
+			Identifier:        "hashicorp/foobar",
+			IsPullRequest:     true,
+			OnDefaultBranch:   false,
+			PullRequestBody:   "Adds a cache in front of the database to speed up reads.",
+			PullRequestNumber: 4,
+			PullRequestTitle:  "Add a cache",
+			PullRequestURL:    "https://github.com/hashicorp/foobar/pull/4",
+			SenderAvatarURL:   "https://avatars.githubusercontent.com/u/42?v=4",
+			SenderHTMLURL:     "https://github.com/emlanctot",
+			SenderUsername:    "emlanctot",
+		}, ia)
+	})
+
+	t.Run("with an API configuration version", func(t *testing.T) {
+		ia, err := client.ConfigurationVersions.ReadIngressAttributes(ctx, "cv-api")
+		require.NoError(t, err)
+		assert.Nil(t, ia)
+	})
+
+	t.Run("when the configuration version does not exist", func(t *testing.T) {
+		ia, err := client.ConfigurationVersions.ReadIngressAttributes(ctx, "cv-nonexisting")
+		assert.Nil(t, ia)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+}
+
+func TestConfigurationVersionsCreatePayload(t *testing.T) {
+	var body map[string]interface{}
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(201)
+		w.Write([]byte(`{"data": {
+			"id": "cv-123",
+			"type": "configuration-versions",
+			"attributes": {
+				"auto-queue-runs": false,
+				"provisional": true,
+				"speculative": false,
+				"status": "pending"
+			}
+		}}`))
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	attributes := func(t *testing.T) map[string]interface{} {
+		data, ok := body["data"].(map[string]interface{})
+		require.True(t, ok)
+		attrs, _ := data["attributes"].(map[string]interface{})
+		return attrs
+	}
+
+	t.Run("without options", func(t *testing.T) {
+		_, err := client.ConfigurationVersions.Create(ctx, "ws-123", ConfigurationVersionCreateOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, attributes(t))
+	})
+
+	t.Run("with provisional", func(t *testing.T) {
+		cv, err := client.ConfigurationVersions.Create(ctx, "ws-123", ConfigurationVersionCreateOptions{
+			AutoQueueRuns: Bool(false),
+			Provisional:   Bool(true),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"auto-queue-runs": false,
+			"provisional":     true,
+		}, attributes(t))
+		assert.True(t, cv.Provisional)
+		assert.False(t, cv.Speculative)
+	})
+
+	t.Run("with speculative", func(t *testing.T) {
+		_, err := client.ConfigurationVersions.Create(ctx, "ws-123", ConfigurationVersionCreateOptions{
+			Speculative: Bool(true),
+			Provisional: Bool(false),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"provisional": false,
+			"speculative": true,
+		}, attributes(t))
+	})
+}
+
+func TestConfigurationVersionCreateOptionsValid(t *testing.T) {
+	t.Run("with speculative and provisional", func(t *testing.T) {
+		options := ConfigurationVersionCreateOptions{
+			Speculative: Bool(true),
+			Provisional: Bool(true),
+		}
+		assert.EqualError(t, options.valid(), "only one of speculative or provisional can be set")
+	})
+
+	t.Run("with speculative and not provisional", func(t *testing.T) {
+		options := ConfigurationVersionCreateOptions{
+			Speculative: Bool(true),
+			Provisional: Bool(false),
+		}
+		assert.NoError(t, options.valid())
+	})
+
+	t.Run("without options", func(t *testing.T) {
+		assert.NoError(t, ConfigurationVersionCreateOptions{}.valid())
+	})
+}
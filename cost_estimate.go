@@ -67,7 +67,7 @@ type CostEstimateStatusTimestamps struct {
 
 // Read a costEstimate by its ID.
 func (s *costEstimates) Read(ctx context.Context, costEstimateID string) (*CostEstimate, error) {
-	if !validStringID(&costEstimateID) {
+	if !validResourceID(&costEstimateID, "ce-") {
 		return nil, errors.New("invalid value for cost estimate ID")
 	}
 
@@ -88,7 +88,7 @@ func (s *costEstimates) Read(ctx context.Context, costEstimateID string) (*CostE
 
 // Logs retrieves the logs of a costEstimate.
 func (s *costEstimates) Logs(ctx context.Context, costEstimateID string) (io.Reader, error) {
-	if !validStringID(&costEstimateID) {
+	if !validResourceID(&costEstimateID, "ce-") {
 		return nil, errors.New("invalid value for cost estimate ID")
 	}
 
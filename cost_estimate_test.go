@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -36,9 +37,9 @@ func TestCostEstimatesRead(t *testing.T) {
 	})
 
 	t.Run("when the costEstimate does not exist", func(t *testing.T) {
-		ce, err := client.CostEstimates.Read(ctx, "nonexisting")
+		ce, err := client.CostEstimates.Read(ctx, "ce-nonexisting")
 		assert.Nil(t, ce)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid costEstimate ID", func(t *testing.T) {
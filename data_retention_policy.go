@@ -0,0 +1,117 @@
+package tfe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/svanharmelen/jsonapi"
+)
+
+// minDataRetentionPolicyAPIVersion is the first API version that supports
+// data retention policies.
+const minDataRetentionPolicyAPIVersion = "2.6"
+
+// DataRetentionPolicyChoice represents the data retention policy of a
+// workspace. A policy is either of one of the two kinds, so at most one of
+// the fields is set. If none are set, the workspace has no policy of its
+// own and inherits the policy of its organization.
+type DataRetentionPolicyChoice struct {
+	DeleteOlder *DataRetentionPolicyDeleteOlder
+	DontDelete  *DataRetentionPolicyDontDelete
+}
+
+// IsPopulated reports if the choice contains a data retention policy.
+func (d *DataRetentionPolicyChoice) IsPopulated() bool {
+	return d.DeleteOlder != nil || d.DontDelete != nil
+}
+
+// DataRetentionPolicyDeleteOlder represents a data retention policy that
+// deletes the state and configuration versions that are older than the
+// given number of days.
+type DataRetentionPolicyDeleteOlder struct {
+	ID                   string `jsonapi:"primary,data-retention-policy-delete-olders"`
+	DeleteOlderThanNDays int    `jsonapi:"attr,delete-older-than-n-days"`
+}
+
+// DataRetentionPolicyDontDelete represents a data retention policy that
+// never deletes any data.
+type DataRetentionPolicyDontDelete struct {
+	ID string `jsonapi:"primary,data-retention-policy-dont-deletes"`
+}
+
+// DataRetentionPolicyDeleteOlderSetOptions represents the options for
+// setting a data retention policy that deletes old data.
+type DataRetentionPolicyDeleteOlderSetOptions struct {
+	// For internal use only!
+	ID string `jsonapi:"primary,data-retention-policy-delete-olders"`
+
+	// The number of days after which the data is deleted.
+	DeleteOlderThanNDays int `jsonapi:"attr,delete-older-than-n-days"`
+}
+
+func (o DataRetentionPolicyDeleteOlderSetOptions) valid() error {
+	if o.DeleteOlderThanNDays < 1 {
+		return errors.New("invalid value for delete older than n days: must be at least 1")
+	}
+	return nil
+}
+
+// DataRetentionPolicyDontDeleteSetOptions represents the options for
+// setting a data retention policy that never deletes any data.
+type DataRetentionPolicyDontDeleteSetOptions struct {
+	// For internal use only!
+	ID string `jsonapi:"primary,data-retention-policy-dont-deletes"`
+}
+
+// unmarshalDataRetentionPolicy decodes a data retention policy document,
+// using the type of its primary data to select the kind of policy. A
+// document without primary data results in an empty choice.
+func unmarshalDataRetentionPolicy(body []byte) (*DataRetentionPolicyChoice, error) {
+	choice := &DataRetentionPolicyChoice{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return choice, nil
+	}
+
+	var doc struct {
+		Data *struct {
+			Type string `json:"type"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Data == nil {
+		return choice, nil
+	}
+
+	var v interface{}
+	switch doc.Data.Type {
+	case "data-retention-policy-delete-olders":
+		choice.DeleteOlder = &DataRetentionPolicyDeleteOlder{}
+		v = choice.DeleteOlder
+	case "data-retention-policy-dont-deletes":
+		choice.DontDelete = &DataRetentionPolicyDontDelete{}
+		v = choice.DontDelete
+	default:
+		return nil, fmt.Errorf("unknown data retention policy type %q", doc.Data.Type)
+	}
+
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(body), v); err != nil {
+		return nil, err
+	}
+
+	return choice, nil
+}
+
+// dataRetentionPolicyError translates the 404 returned by a server that
+// predates data retention policies into ErrDataRetentionPolicyNotSupported.
+// A 404 from a newer server, or from a server that did not report its API
+// version, is returned as is, as the workspace may not exist.
+func (c *Client) dataRetentionPolicyError(err error) error {
+	if errors.Is(err, ErrResourceNotFound) && c.remoteAPIVersionBefore(minDataRetentionPolicyAPIVersion) {
+		return ErrDataRetentionPolicyNotSupported
+	}
+	return err
+}
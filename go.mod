@@ -7,9 +7,10 @@ require (
 	github.com/hashicorp/go-retryablehttp v0.5.2
 	github.com/hashicorp/go-slug v0.4.1
 	github.com/hashicorp/go-uuid v1.0.1
+	github.com/hashicorp/hcl v1.0.0
 	github.com/stretchr/testify v1.3.0
 	github.com/svanharmelen/jsonapi v0.0.0-20180618144545-0c0828c3f16d
 	golang.org/x/time v0.0.0-20190308202827-9d24e82272b4
 )
 
-go 1.12
+go 1.13
@@ -6,6 +6,8 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -27,6 +29,43 @@ func testClient(t *testing.T) *Client {
 	return client
 }
 
+// newTestServer starts a test server that answers the ping request sent by
+// NewClient and passes all other requests to handler. If handler is nil, all
+// other requests are answered with a 404. The caller must close the server.
+func newTestServer(handler http.HandlerFunc) *httptest.Server {
+	if handler == nil {
+		handler = http.NotFound
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/ping" {
+			w.WriteHeader(204)
+			return
+		}
+		handler(w, r)
+	}))
+}
+
+// newTestClient starts a test server using newTestServer and returns a
+// client that is configured to use it. The caller must close the server.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	ts := newTestServer(handler)
+
+	// Server errors are retried by default, so keep the backoff short.
+	client, err := NewClient(&Config{
+		Address:                 ts.URL,
+		Token:                   "dummy-token",
+		HTTPClient:              ts.Client(),
+		ServerErrorRetryWaitMin: time.Millisecond,
+		ServerErrorRetryWaitMax: time.Millisecond,
+	})
+	if err != nil {
+		ts.Close()
+		t.Fatal(err)
+	}
+
+	return client, ts
+}
+
 func fetchTestAccountDetails(t *testing.T, client *Client) *TestAccountDetails {
 	if _testAccountDetails == nil {
 		_testAccountDetails = FetchTestAccountDetails(t, client)
@@ -2,15 +2,23 @@ package tfe
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"math"
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/google/go-querystring/query"
 )
 
-// LogReader implements io.Reader for streaming logs.
+// maxLogChunkSize is the maximum number of bytes requested in a single
+// chunk, regardless of the size of the buffer passed to Read.
+const maxLogChunkSize = 64 * 1024
+
+// LogReader implements io.Reader for streaming logs. It follows the logs
+// until the operation they belong to is done, polling for new chunks with
+// an exponential backoff. Once all logs are read, every following call to
+// Read returns io.EOF without making any further requests.
 type LogReader struct {
 	client      *Client
 	ctx         context.Context
@@ -20,6 +28,17 @@ type LogReader struct {
 	reads       int
 	startOfText bool
 	endOfText   bool
+	finished    bool
+	eof         bool
+}
+
+// logReadOptions represents the options for reading a chunk of the logs.
+type logReadOptions struct {
+	// The maximum number of bytes to read.
+	Limit int `url:"limit"`
+
+	// The number of bytes already read.
+	Offset int64 `url:"offset"`
 }
 
 // backoff will perform exponential backoff based on the iteration and
@@ -33,6 +52,13 @@ func backoff(min, max float64, iter int) time.Duration {
 }
 
 func (r *LogReader) Read(l []byte) (int, error) {
+	if r.eof {
+		return 0, io.EOF
+	}
+	if len(l) == 0 {
+		return 0, nil
+	}
+
 	if written, err := r.read(l); err != io.ErrNoProgress {
 		return written, err
 	}
@@ -41,10 +67,14 @@ func (r *LogReader) Read(l []byte) (int, error) {
 	// run is finsished. If we would return right away without any
 	// data, we could and up causing a io.ErrNoProgress error.
 	for r.reads = 1; ; r.reads++ {
+		// Use a timer instead of time.After, so it is released right
+		// away when the context is canceled.
+		timer := time.NewTimer(backoff(500, 2000, r.reads))
 		select {
 		case <-r.ctx.Done():
+			timer.Stop()
 			return 0, r.ctx.Err()
-		case <-time.After(backoff(500, 2000, r.reads)):
+		case <-timer.C:
 			if written, err := r.read(l); err != io.ErrNoProgress {
 				return written, err
 			}
@@ -53,8 +83,16 @@ func (r *LogReader) Read(l []byte) (int, error) {
 }
 
 func (r *LogReader) read(l []byte) (int, error) {
+	if len(l) > maxLogChunkSize {
+		l = l[:maxLogChunkSize]
+	}
+
 	// Update the query string.
-	r.logURL.RawQuery = fmt.Sprintf("limit=%d&offset=%d", len(l), r.offset)
+	q, err := query.Values(logReadOptions{Limit: len(l), Offset: r.offset})
+	if err != nil {
+		return 0, err
+	}
+	r.logURL.RawQuery = q.Encode()
 
 	// Create a new request.
 	req, err := http.NewRequest("GET", r.logURL.String(), nil)
@@ -63,13 +101,12 @@ func (r *LogReader) read(l []byte) (int, error) {
 	}
 	req = req.WithContext(r.ctx)
 
-	// Attach the default headers.
-	for k, v := range r.client.headers {
-		req.Header[k] = v
-	}
+	// Attach the default headers and ask for compressed content.
+	r.client.setHeaders(req.Header)
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	// Retrieve the next chunk.
-	resp, err := r.client.http.HTTPClient.Do(req)
+	resp, err := r.client.httpClient().Do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -80,8 +117,14 @@ func (r *LogReader) read(l []byte) (int, error) {
 		return 0, err
 	}
 
+	body, err := decodeBody(resp)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
 	// Read the retrieved chunk.
-	written, err := resp.Body.Read(l)
+	written, err := body.Read(l)
 	if err != nil && err != io.EOF {
 		// Ignore io.EOF errors returned when reading from the response
 		// body as this indicates the end of the chunk and not the end
@@ -122,6 +165,10 @@ func (r *LogReader) read(l []byte) (int, error) {
 	// before checking if there is a new chunk available or that the
 	// run is finished and we are done reading all chunks.
 	if written == 0 {
+		if r.finished {
+			r.eof = true
+			return 0, io.EOF
+		}
 		if (r.startOfText && r.endOfText) || // The logstream finished without issues.
 			(r.startOfText && r.reads%10 == 0) || // The logstream terminated unexpectedly.
 			(!r.startOfText && r.reads > 1) { // The logstream doesn't support STX/ETX.
@@ -130,7 +177,16 @@ func (r *LogReader) read(l []byte) (int, error) {
 				return 0, err
 			}
 			if done {
-				return 0, io.EOF
+				if r.endOfText {
+					r.eof = true
+					return 0, io.EOF
+				}
+
+				// Without an ETX marker we cannot tell if the last chunk
+				// was written after our previous read, so read once more
+				// now that no new chunks can be added.
+				r.finished = true
+				return r.read(l)
 			}
 		}
 		return 0, io.ErrNoProgress
@@ -1,11 +1,16 @@
 package tfe
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -202,8 +207,8 @@ func TestLogReader_withoutMarkers(t *testing.T) {
 	if doneReads != 25 {
 		t.Fatalf("expected 14 done reads, got %d reads", doneReads)
 	}
-	if logReads != 32 {
-		t.Fatalf("expected 32 log reads, got %d reads", logReads)
+	if logReads != 33 {
+		t.Fatalf("expected 33 log reads, got %d reads", logReads)
 	}
 }
 
@@ -247,7 +252,109 @@ func TestLogReader_withoutEndOfTextMarker(t *testing.T) {
 	if doneReads != 3 {
 		t.Fatalf("expected 3 done reads, got %d reads", doneReads)
 	}
-	if logReads != 42 {
-		t.Fatalf("expected 42 log reads, got %d reads", logReads)
+	if logReads != 43 {
+		t.Fatalf("expected 43 log reads, got %d reads", logReads)
+	}
+}
+
+func TestLogReader_withChunkedFixture(t *testing.T) {
+	t.Parallel()
+
+	var log strings.Builder
+	for i := 1; i <= 50; i++ {
+		fmt.Fprintf(&log, "line %d of the terraform logs\n", i)
+	}
+	expected := log.String()
+
+	// Every request makes another 97 bytes of the logs available, and the
+	// requested offset and limit are honored, like the real log server.
+	available := 0
+	logReads := 0
+	ts, lr := testLogReader(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/ping" {
+			return
+		}
+
+		logReads++
+		available += 97
+		if available > len(expected) {
+			available = len(expected)
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil {
+			t.Errorf("invalid offset: %v", err)
+			return
+		}
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil {
+			t.Errorf("invalid limit: %v", err)
+			return
+		}
+		if limit > maxLogChunkSize {
+			t.Errorf("expected a limit of at most %d, got %d", maxLogChunkSize, limit)
+		}
+
+		end := offset + limit
+		if end > available {
+			end = available
+		}
+		if offset < end {
+			w.Write([]byte(expected[offset:end]))
+		}
+	}))
+	defer ts.Close()
+
+	lr.done = func() (bool, error) {
+		return available == len(expected), nil
+	}
+
+	// Read using a small buffer to make sure chunks are split and
+	// continued at the right offsets.
+	var logs bytes.Buffer
+	buf := make([]byte, 64)
+	for {
+		n, err := lr.Read(buf)
+		logs.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if logs.String() != expected {
+		t.Fatalf("expected %q, got: %q", expected, logs.String())
+	}
+
+	// All following reads should return io.EOF without any requests.
+	reads := logReads
+	for i := 0; i < 3; i++ {
+		if n, err := lr.Read(buf); n != 0 || err != io.EOF {
+			t.Fatalf("expected 0 bytes and io.EOF, got %d bytes and %v", n, err)
+		}
+	}
+	if logReads != reads {
+		t.Fatalf("expected no more log reads after io.EOF, got %d", logReads-reads)
+	}
+}
+
+func TestLogReader_canceledContext(t *testing.T) {
+	t.Parallel()
+
+	ts, lr := testLogReader(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lr.ctx = ctx
+	lr.done = func() (bool, error) {
+		cancel()
+		return false, nil
+	}
+
+	_, err := ioutil.ReadAll(lr)
+	if err != context.Canceled {
+		t.Fatalf("expected %v, got: %v", context.Canceled, err)
 	}
 }
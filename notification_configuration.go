@@ -101,7 +101,7 @@ type NotificationConfigurationListOptions struct {
 
 // List all the notification configurations associated with a workspace.
 func (s *notificationConfigurations) List(ctx context.Context, workspaceID string, options NotificationConfigurationListOptions) (*NotificationConfigurationList, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -163,7 +163,7 @@ func (o NotificationConfigurationCreateOptions) valid() error {
 
 // Creates a notification configuration with the given options.
 func (s *notificationConfigurations) Create(ctx context.Context, workspaceID string, options NotificationConfigurationCreateOptions) (*NotificationConfiguration, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 	if err := options.valid(); err != nil {
@@ -190,7 +190,7 @@ func (s *notificationConfigurations) Create(ctx context.Context, workspaceID str
 
 // Read a notitification configuration by its ID.
 func (s *notificationConfigurations) Read(ctx context.Context, notificationConfigurationID string) (*NotificationConfiguration, error) {
-	if !validStringID(&notificationConfigurationID) {
+	if !validResourceID(&notificationConfigurationID, "nc-") {
 		return nil, errors.New("invalid value for notification configuration ID")
 	}
 
@@ -231,11 +231,24 @@ type NotificationConfigurationUpdateOptions struct {
 	URL *string `jsonapi:"attr,url,omitempty"`
 }
 
+func (o NotificationConfigurationUpdateOptions) valid() error {
+	if o.Name != nil && !validString(o.Name) {
+		return errors.New("invalid value for name")
+	}
+	if o.URL != nil && !validString(o.URL) {
+		return errors.New("invalid value for url")
+	}
+	return nil
+}
+
 // Updates a notification configuration with the given options.
 func (s *notificationConfigurations) Update(ctx context.Context, notificationConfigurationID string, options NotificationConfigurationUpdateOptions) (*NotificationConfiguration, error) {
-	if !validStringID(&notificationConfigurationID) {
+	if !validResourceID(&notificationConfigurationID, "nc-") {
 		return nil, errors.New("invalid value for notification configuration ID")
 	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
 
 	// Make sure we don't send a user provided ID.
 	options.ID = ""
@@ -257,7 +270,7 @@ func (s *notificationConfigurations) Update(ctx context.Context, notificationCon
 
 // Delete a notifications configuration by its ID.
 func (s *notificationConfigurations) Delete(ctx context.Context, notificationConfigurationID string) error {
-	if !validStringID(&notificationConfigurationID) {
+	if !validResourceID(&notificationConfigurationID, "nc-") {
 		return errors.New("invalid value for notification configuration ID")
 	}
 
@@ -273,7 +286,7 @@ func (s *notificationConfigurations) Delete(ctx context.Context, notificationCon
 // Verifies a notification configuration by delivering a verification
 // payload to the configured url.
 func (s *notificationConfigurations) Verify(ctx context.Context, notificationConfigurationID string) (*NotificationConfiguration, error) {
-	if !validStringID(&notificationConfigurationID) {
+	if !validResourceID(&notificationConfigurationID, "nc-") {
 		return nil, errors.New("invalid value for notification configuration ID")
 	}
 
@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -121,8 +122,8 @@ func TestNotificationConfigurationRead(t *testing.T) {
 	})
 
 	t.Run("when the notification configuration does not exist", func(t *testing.T) {
-		_, err := client.NotificationConfigurations.Read(ctx, "nonexisting")
-		assert.Equal(t, err, ErrResourceNotFound)
+		_, err := client.NotificationConfigurations.Read(ctx, "nc-nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the notification configuration ID is invalid", func(t *testing.T) {
@@ -156,8 +157,8 @@ func TestNotificationConfigurationUpdate(t *testing.T) {
 	})
 
 	t.Run("when the notification configuration does not exist", func(t *testing.T) {
-		_, err := client.NotificationConfigurations.Update(ctx, "nonexisting", NotificationConfigurationUpdateOptions{})
-		assert.Equal(t, err, ErrResourceNotFound)
+		_, err := client.NotificationConfigurations.Update(ctx, "nc-nonexisting", NotificationConfigurationUpdateOptions{})
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the notification configuration ID is invalid", func(t *testing.T) {
@@ -180,12 +181,12 @@ func TestNotificationConfigurationDelete(t *testing.T) {
 		require.NoError(t, err)
 
 		_, err = client.NotificationConfigurations.Read(ctx, ncTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the notification configuration does not exist", func(t *testing.T) {
-		err := client.NotificationConfigurations.Delete(ctx, "nonexisting")
-		assert.Equal(t, err, ErrResourceNotFound)
+		err := client.NotificationConfigurations.Delete(ctx, "nc-nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the notification configuration ID is invalid", func(t *testing.T) {
@@ -207,8 +208,8 @@ func TestNotificationConfigurationVerify(t *testing.T) {
 	})
 
 	t.Run("when the notification configuration does not exists", func(t *testing.T) {
-		_, err := client.NotificationConfigurations.Verify(ctx, "nonexisting")
-		assert.Equal(t, err, ErrResourceNotFound)
+		_, err := client.NotificationConfigurations.Verify(ctx, "nc-nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the notification configuration ID is invalid", func(t *testing.T) {
@@ -175,7 +175,7 @@ func (s *oAuthClients) Create(ctx context.Context, organization string, options
 
 // Read an OAuth client by its ID.
 func (s *oAuthClients) Read(ctx context.Context, oAuthClientID string) (*OAuthClient, error) {
-	if !validStringID(&oAuthClientID) {
+	if !validResourceID(&oAuthClientID, "oc-") {
 		return nil, errors.New("invalid value for OAuth client ID")
 	}
 
@@ -196,7 +196,7 @@ func (s *oAuthClients) Read(ctx context.Context, oAuthClientID string) (*OAuthCl
 
 // Delete an OAuth client by its ID.
 func (s *oAuthClients) Delete(ctx context.Context, oAuthClientID string) error {
-	if !validStringID(&oAuthClientID) {
+	if !validResourceID(&oAuthClientID, "oc-") {
 		return errors.New("invalid value for OAuth client ID")
 	}
 
@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
@@ -184,9 +185,9 @@ func TestOAuthClientsRead(t *testing.T) {
 	})
 
 	t.Run("when the OAuth client does not exist", func(t *testing.T) {
-		oc, err := client.OAuthClients.Read(ctx, "nonexisting")
+		oc, err := client.OAuthClients.Read(ctx, "oc-nonexisting")
 		assert.Nil(t, oc)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid OAuth client ID", func(t *testing.T) {
@@ -211,12 +212,12 @@ func TestOAuthClientsDelete(t *testing.T) {
 
 		// Try loading the OAuth client - it should fail.
 		_, err = client.OAuthClients.Read(ctx, ocTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the OAuth client does not exist", func(t *testing.T) {
 		err := client.OAuthClients.Delete(ctx, ocTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the OAuth client ID is invalid", func(t *testing.T) {
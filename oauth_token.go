@@ -82,7 +82,7 @@ func (s *oAuthTokens) List(ctx context.Context, organization string, options OAu
 
 // Read an OAuth token by its ID.
 func (s *oAuthTokens) Read(ctx context.Context, oAuthTokenID string) (*OAuthToken, error) {
-	if !validStringID(&oAuthTokenID) {
+	if !validResourceID(&oAuthTokenID, "ot-") {
 		return nil, errors.New("invalid value for OAuth token ID")
 	}
 
@@ -112,7 +112,7 @@ type OAuthTokenUpdateOptions struct {
 
 // Update an existing OAuth token.
 func (s *oAuthTokens) Update(ctx context.Context, oAuthTokenID string, options OAuthTokenUpdateOptions) (*OAuthToken, error) {
-	if !validStringID(&oAuthTokenID) {
+	if !validResourceID(&oAuthTokenID, "ot-") {
 		return nil, errors.New("invalid value for OAuth token ID")
 	}
 
@@ -136,7 +136,7 @@ func (s *oAuthTokens) Update(ctx context.Context, oAuthTokenID string, options O
 
 // Delete an OAuth token by its ID.
 func (s *oAuthTokens) Delete(ctx context.Context, oAuthTokenID string) error {
-	if !validStringID(&oAuthTokenID) {
+	if !validResourceID(&oAuthTokenID, "ot-") {
 		return errors.New("invalid value for OAuth token ID")
 	}
 
@@ -26,6 +26,9 @@ type Organizations interface {
 	// Read an organization by its name.
 	Read(ctx context.Context, organization string) (*Organization, error)
 
+	// Read an organization by its name with options.
+	ReadWithOptions(ctx context.Context, organization string, options OrganizationReadOptions) (*Organization, error)
+
 	// Update attributes of an existing organization.
 	Update(ctx context.Context, organization string, options OrganizationUpdateOptions) (*Organization, error)
 
@@ -56,6 +59,28 @@ const (
 	AuthPolicyTwoFactor AuthPolicyType = "two_factor_mandatory"
 )
 
+// ExecutionModeType represents the mode in which runs are executed.
+type ExecutionModeType string
+
+// List of available execution modes.
+const (
+	ExecutionModeAgent  ExecutionModeType = "agent"
+	ExecutionModeLocal  ExecutionModeType = "local"
+	ExecutionModeRemote ExecutionModeType = "remote"
+)
+
+// validExecutionMode checks if the given execution mode is known.
+func validExecutionMode(m ExecutionModeType) bool {
+	switch m {
+	case ExecutionModeAgent, ExecutionModeLocal, ExecutionModeRemote:
+		return true
+	}
+	return false
+}
+
+// Session settings are limited to two weeks (minutes).
+const maxSessionMinutes = 20160
+
 // EnterprisePlanType represents an enterprise plan type.
 type EnterprisePlanType string
 
@@ -75,19 +100,25 @@ type OrganizationList struct {
 
 // Organization represents a Terraform Enterprise organization.
 type Organization struct {
-	Name                   string                   `jsonapi:"primary,organizations"`
-	CollaboratorAuthPolicy AuthPolicyType           `jsonapi:"attr,collaborator-auth-policy"`
-	CostEstimationEnabled  bool                     `jsonapi:"attr,cost-estimation-enabled"`
-	CreatedAt              time.Time                `jsonapi:"attr,created-at,iso8601"`
-	Email                  string                   `jsonapi:"attr,email"`
-	EnterprisePlan         EnterprisePlanType       `jsonapi:"attr,enterprise-plan"`
-	OwnersTeamSAMLRoleID   string                   `jsonapi:"attr,owners-team-saml-role-id"`
-	Permissions            *OrganizationPermissions `jsonapi:"attr,permissions"`
-	SAMLEnabled            bool                     `jsonapi:"attr,saml-enabled"`
-	SessionRemember        int                      `jsonapi:"attr,session-remember"`
-	SessionTimeout         int                      `jsonapi:"attr,session-timeout"`
-	TrialExpiresAt         time.Time                `jsonapi:"attr,trial-expires-at,iso8601"`
-	TwoFactorConformant    bool                     `jsonapi:"attr,two-factor-conformant"`
+	Name                       string                   `jsonapi:"primary,organizations"`
+	AssessmentsEnforced        bool                     `jsonapi:"attr,assessments-enforced"`
+	CollaboratorAuthPolicy     AuthPolicyType           `jsonapi:"attr,collaborator-auth-policy"`
+	CostEstimationEnabled      bool                     `jsonapi:"attr,cost-estimation-enabled"`
+	CreatedAt                  time.Time                `jsonapi:"attr,created-at,iso8601"`
+	DefaultExecutionMode       ExecutionModeType        `jsonapi:"attr,default-execution-mode"`
+	Email                      string                   `jsonapi:"attr,email"`
+	EnterprisePlan             EnterprisePlanType       `jsonapi:"attr,enterprise-plan"`
+	OwnersTeamSAMLRoleID       string                   `jsonapi:"attr,owners-team-saml-role-id"`
+	Permissions                *OrganizationPermissions `jsonapi:"attr,permissions"`
+	SAMLEnabled                bool                     `jsonapi:"attr,saml-enabled"`
+	SendUnchangedNotifications bool                     `jsonapi:"attr,send-unchanged-notifications"`
+	SessionRemember            int                      `jsonapi:"attr,session-remember"`
+	SessionTimeout             int                      `jsonapi:"attr,session-timeout"`
+	TrialExpiresAt             time.Time                `jsonapi:"attr,trial-expires-at,iso8601"`
+	TwoFactorConformant        bool                     `jsonapi:"attr,two-factor-conformant"`
+
+	// Relations
+	Entitlements *Entitlements `jsonapi:"relation,entitlement-set"`
 }
 
 // Capacity represents the current run capacity of an organization.
@@ -100,8 +131,12 @@ type Capacity struct {
 // Entitlements represents the entitlements of an organization.
 type Entitlements struct {
 	ID                    string `jsonapi:"primary,entitlement-sets"`
+	Agents                bool   `jsonapi:"attr,agents"`
+	AuditLogging          bool   `jsonapi:"attr,audit-logging"`
+	CostEstimation        bool   `jsonapi:"attr,cost-estimation"`
 	Operations            bool   `jsonapi:"attr,operations"`
 	PrivateModuleRegistry bool   `jsonapi:"attr,private-module-registry"`
+	SSO                   bool   `jsonapi:"attr,sso"`
 	Sentinel              bool   `jsonapi:"attr,sentinel"`
 	StateStorage          bool   `jsonapi:"attr,state-storage"`
 	Teams                 bool   `jsonapi:"attr,teams"`
@@ -213,12 +248,32 @@ func (s *organizations) Create(ctx context.Context, options OrganizationCreateOp
 
 // Read an organization by its name.
 func (s *organizations) Read(ctx context.Context, organization string) (*Organization, error) {
+	return s.ReadWithOptions(ctx, organization, OrganizationReadOptions{})
+}
+
+// OrgIncludeOpt represents the available options for include query params.
+// https://www.terraform.io/docs/cloud/api/organizations.html#available-related-resources
+type OrgIncludeOpt string
+
+// List all available organization include options.
+const (
+	OrgEntitlements OrgIncludeOpt = "entitlement_set"
+)
+
+// OrganizationReadOptions represents the options for reading an organization.
+type OrganizationReadOptions struct {
+	// A list of relations to include.
+	Include []OrgIncludeOpt `url:"include,omitempty,comma"`
+}
+
+// ReadWithOptions reads an organization by its name using the given options.
+func (s *organizations) ReadWithOptions(ctx context.Context, organization string, options OrganizationReadOptions) (*Organization, error) {
 	if !validStringID(&organization) {
 		return nil, errors.New("invalid value for organization")
 	}
 
 	u := fmt.Sprintf("organizations/%s", url.QueryEscape(organization))
-	req, err := s.client.newRequest("GET", u, nil)
+	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
 		return nil, err
 	}
@@ -257,6 +312,41 @@ type OrganizationUpdateOptions struct {
 
 	// The name of the "owners" team
 	OwnersTeamSAMLRoleID *string `jsonapi:"attr,owners-team-saml-role-id,omitempty"`
+
+	// Send notifications for runs that did not change any resources.
+	SendUnchangedNotifications *bool `jsonapi:"attr,send-unchanged-notifications,omitempty"`
+
+	// Enforce health assessments for all workspaces.
+	AssessmentsEnforced *bool `jsonapi:"attr,assessments-enforced,omitempty"`
+
+	// The default execution mode of new workspaces.
+	DefaultExecutionMode *ExecutionModeType `jsonapi:"attr,default-execution-mode,omitempty"`
+}
+
+func (o OrganizationUpdateOptions) valid() error {
+	if o.Name != nil && !validStringID(o.Name) {
+		return errors.New("invalid value for name")
+	}
+	if o.Email != nil && !validString(o.Email) {
+		return errors.New("invalid value for email")
+	}
+	if o.SessionRemember != nil && (*o.SessionRemember < 1 || *o.SessionRemember > maxSessionMinutes) {
+		return fmt.Errorf("invalid value for session remember: must be between 1 and %d minutes", maxSessionMinutes)
+	}
+	if o.SessionTimeout != nil && (*o.SessionTimeout < 1 || *o.SessionTimeout > maxSessionMinutes) {
+		return fmt.Errorf("invalid value for session timeout: must be between 1 and %d minutes", maxSessionMinutes)
+	}
+	if o.CollaboratorAuthPolicy != nil {
+		switch *o.CollaboratorAuthPolicy {
+		case AuthPolicyPassword, AuthPolicyTwoFactor:
+		default:
+			return errors.New("invalid value for collaborator auth policy")
+		}
+	}
+	if o.DefaultExecutionMode != nil && !validExecutionMode(*o.DefaultExecutionMode) {
+		return errors.New("invalid value for default execution mode")
+	}
+	return nil
 }
 
 // Update attributes of an existing organization.
@@ -264,6 +354,9 @@ func (s *organizations) Update(ctx context.Context, organization string, options
 	if !validStringID(&organization) {
 		return nil, errors.New("invalid value for organization")
 	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
 
 	// Make sure we don't send a user provided ID.
 	options.ID = ""
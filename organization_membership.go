@@ -68,7 +68,7 @@ type OrganizationMembership struct {
 type OrganizationMembershipListOptions struct {
 	ListOptions
 
-	Include string `url:"include"`
+	Include string `url:"include,omitempty"`
 }
 
 // List all the organization memberships of the given organization.
@@ -141,12 +141,12 @@ func (s *organizationMemberships) Read(ctx context.Context, organizationMembersh
 
 // OrganizationMembershipReadOptions represents the options for reading organization memberships.
 type OrganizationMembershipReadOptions struct {
-	Include string `url:"include"`
+	Include string `url:"include,omitempty"`
 }
 
 // Read an organization membership by ID with options
 func (s *organizationMemberships) ReadWithOptions(ctx context.Context, organizationMembershipID string, options OrganizationMembershipReadOptions) (*OrganizationMembership, error) {
-	if !validStringID(&organizationMembershipID) {
+	if !validResourceID(&organizationMembershipID, "ou-") {
 		return nil, errors.New("invalid value for membership")
 	}
 
@@ -164,7 +164,7 @@ func (s *organizationMemberships) ReadWithOptions(ctx context.Context, organizat
 
 // Delete an organization membership by its ID.
 func (s *organizationMemberships) Delete(ctx context.Context, organizationMembershipID string) error {
-	if !validStringID(&organizationMembershipID) {
+	if !validResourceID(&organizationMembershipID, "ou-") {
 		return errors.New("invalid value for membership")
 	}
 
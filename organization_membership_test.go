@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -146,9 +147,9 @@ func TestOrganizationMembershipsRead(t *testing.T) {
 	})
 
 	t.Run("when the membership does not exist", func(t *testing.T) {
-		mem, err := client.OrganizationMemberships.Read(ctx, "nonexisting")
+		mem, err := client.OrganizationMemberships.Read(ctx, "ou-nonexisting")
 		assert.Nil(t, mem)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid membership id", func(t *testing.T) {
@@ -177,9 +178,9 @@ func TestOrganizationMembershipsReadWithOptions(t *testing.T) {
 	})
 
 	t.Run("when the membership does not exist", func(t *testing.T) {
-		mem, err := client.OrganizationMemberships.ReadWithOptions(ctx, "nonexisting", options)
+		mem, err := client.OrganizationMemberships.ReadWithOptions(ctx, "ou-nonexisting", options)
 		assert.Nil(t, mem)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid membership id", func(t *testing.T) {
@@ -2,6 +2,10 @@ package tfe
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -126,6 +130,83 @@ func TestOrganizationsRead(t *testing.T) {
 	})
 }
 
+func TestOrganizationsReadWithOptions(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	orgTest, orgTestCleanup := createOrganization(t, client)
+	defer orgTestCleanup()
+
+	options := OrganizationReadOptions{
+		Include: []OrgIncludeOpt{OrgEntitlements},
+	}
+
+	t.Run("when the org exists", func(t *testing.T) {
+		org, err := client.Organizations.ReadWithOptions(ctx, orgTest.Name, options)
+		require.NoError(t, err)
+		assert.Equal(t, orgTest.Name, org.Name)
+
+		t.Run("entitlements are included", func(t *testing.T) {
+			require.NotNil(t, org.Entitlements)
+			assert.NotEmpty(t, org.Entitlements.ID)
+			assert.True(t, org.Entitlements.Operations)
+		})
+	})
+
+	t.Run("with invalid name", func(t *testing.T) {
+		org, err := client.Organizations.ReadWithOptions(ctx, badIdentifier, options)
+		assert.Nil(t, org)
+		assert.EqualError(t, err, "invalid value for organization")
+	})
+
+	t.Run("when the org does not exist", func(t *testing.T) {
+		_, err := client.Organizations.ReadWithOptions(ctx, randomString(t), options)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+}
+
+func TestOrganizationsReadWithOptionsFixture(t *testing.T) {
+	organization, err := ioutil.ReadFile("test-fixtures/organization-entitlements/organization.json")
+	require.NoError(t, err)
+
+	var include string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/organizations/my-org":
+			include = r.URL.Query().Get("include")
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(organization)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with included entitlements", func(t *testing.T) {
+		org, err := client.Organizations.ReadWithOptions(ctx, "my-org", OrganizationReadOptions{
+			Include: []OrgIncludeOpt{OrgEntitlements},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "entitlement_set", include)
+		assert.Equal(t, "admin@example.com", org.Email)
+
+		require.NotNil(t, org.Entitlements)
+		assert.Equal(t, "org-Bzyc2JuegvVLAibn", org.Entitlements.ID)
+		assert.True(t, org.Entitlements.Operations)
+		assert.True(t, org.Entitlements.Sentinel)
+		assert.False(t, org.Entitlements.Teams)
+	})
+
+	t.Run("without options", func(t *testing.T) {
+		org, err := client.Organizations.Read(ctx, "my-org")
+		require.NoError(t, err)
+		assert.Empty(t, include)
+		assert.Equal(t, "my-org", org.Name)
+	})
+}
+
 func TestOrganizationsUpdate(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -182,6 +263,76 @@ func TestOrganizationsUpdate(t *testing.T) {
 	})
 }
 
+func TestOrganizationsUpdatePayload(t *testing.T) {
+	var body []byte
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": {"id": "my-org", "type": "organizations"}}`))
+	})
+	defer ts.Close()
+
+	attributes := func(t *testing.T) map[string]interface{} {
+		var payload struct {
+			Data struct {
+				Attributes map[string]interface{} `json:"attributes"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &payload))
+		return payload.Data.Attributes
+	}
+
+	ctx := context.Background()
+
+	t.Run("without any options", func(t *testing.T) {
+		_, err := client.Organizations.Update(ctx, "my-org", OrganizationUpdateOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, attributes(t))
+	})
+
+	t.Run("with a subset of the options", func(t *testing.T) {
+		_, err := client.Organizations.Update(ctx, "my-org", OrganizationUpdateOptions{
+			SessionTimeout:       Int(60),
+			AssessmentsEnforced:  Bool(false),
+			DefaultExecutionMode: ExecutionMode(ExecutionModeAgent),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"session-timeout":        float64(60),
+			"assessments-enforced":   false,
+			"default-execution-mode": "agent",
+		}, attributes(t))
+	})
+
+	t.Run("with all the options", func(t *testing.T) {
+		_, err := client.Organizations.Update(ctx, "my-org", OrganizationUpdateOptions{
+			Name:                       String("renamed"),
+			Email:                      String("admin@example.com"),
+			SessionRemember:            Int(20160),
+			SessionTimeout:             Int(60),
+			CollaboratorAuthPolicy:     AuthPolicy(AuthPolicyTwoFactor),
+			CostEstimationEnabled:      Bool(true),
+			OwnersTeamSAMLRoleID:       String("owners"),
+			SendUnchangedNotifications: Bool(true),
+			AssessmentsEnforced:        Bool(true),
+			DefaultExecutionMode:       ExecutionMode(ExecutionModeRemote),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"name":                         "renamed",
+			"email":                        "admin@example.com",
+			"session-remember":             float64(20160),
+			"session-timeout":              float64(60),
+			"collaborator-auth-policy":     "two_factor_mandatory",
+			"cost-estimation-enabled":      true,
+			"owners-team-saml-role-id":     "owners",
+			"send-unchanged-notifications": true,
+			"assessments-enforced":         true,
+			"default-execution-mode":       "remote",
+		}, attributes(t))
+	})
+}
+
 func TestOrganizationsDelete(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -194,7 +345,7 @@ func TestOrganizationsDelete(t *testing.T) {
 
 		// Try fetching the org again - it should error.
 		_, err = client.Organizations.Read(ctx, orgTest.Name)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid name", func(t *testing.T) {
@@ -249,6 +400,38 @@ func TestOrganizationsCapacity(t *testing.T) {
 	})
 }
 
+func TestOrganizationsCapacityFixture(t *testing.T) {
+	capacity, err := ioutil.ReadFile("test-fixtures/organization-capacity/capacity.json")
+	require.NoError(t, err)
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/organizations/my-org/capacity":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(capacity)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with a valid organization", func(t *testing.T) {
+		c, err := client.Organizations.Capacity(ctx, "my-org")
+		require.NoError(t, err)
+		assert.Equal(t, "my-org", c.Organization)
+		assert.Equal(t, 3, c.Pending)
+		assert.Equal(t, 2, c.Running)
+	})
+
+	t.Run("when the org does not exist", func(t *testing.T) {
+		c, err := client.Organizations.Capacity(ctx, "nonexisting")
+		assert.Nil(t, c)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+}
+
 func TestOrganizationsEntitlements(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -277,8 +460,43 @@ func TestOrganizationsEntitlements(t *testing.T) {
 
 	t.Run("when the org does not exist", func(t *testing.T) {
 		_, err := client.Organizations.Entitlements(ctx, randomString(t))
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+}
+
+func TestOrganizationsEntitlementsFixture(t *testing.T) {
+	entitlements, err := ioutil.ReadFile("test-fixtures/organization-entitlements/entitlement-set.json")
+	require.NoError(t, err)
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/organizations/my-org/entitlement-set":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(entitlements)
+		default:
+			w.WriteHeader(404)
+		}
 	})
+	defer ts.Close()
+
+	// The fixture contains an attribute that is unknown to the client,
+	// which should be ignored.
+	e, err := client.Organizations.Entitlements(context.Background(), "my-org")
+	require.NoError(t, err)
+
+	assert.Equal(t, &Entitlements{
+		ID:                    "org-Bzyc2JuegvVLAibn",
+		Agents:                false,
+		AuditLogging:          true,
+		CostEstimation:        true,
+		Operations:            true,
+		PrivateModuleRegistry: true,
+		SSO:                   true,
+		Sentinel:              false,
+		StateStorage:          true,
+		Teams:                 true,
+		VCSIntegrations:       true,
+	}, e)
 }
 
 func TestOrganizationsRunQueue(t *testing.T) {
@@ -368,3 +586,80 @@ func TestOrganizationsRunQueue(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestOrganizationsUpdateOptionsValid(t *testing.T) {
+	t.Run("without any options", func(t *testing.T) {
+		options := OrganizationUpdateOptions{}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with valid options", func(t *testing.T) {
+		options := OrganizationUpdateOptions{
+			Name:                   String("my-org"),
+			Email:                  String("admin@example.com"),
+			SessionRemember:        Int(20160),
+			SessionTimeout:         Int(1),
+			CollaboratorAuthPolicy: AuthPolicy(AuthPolicyPassword),
+			DefaultExecutionMode:   ExecutionMode(ExecutionModeLocal),
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with an invalid name", func(t *testing.T) {
+		options := OrganizationUpdateOptions{
+			Name: String(badIdentifier),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for name")
+	})
+
+	t.Run("with an empty email", func(t *testing.T) {
+		options := OrganizationUpdateOptions{
+			Email: String(""),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for email")
+	})
+
+	t.Run("with a session remember out of bounds", func(t *testing.T) {
+		options := OrganizationUpdateOptions{
+			SessionRemember: Int(20161),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for session remember: must be between 1 and 20160 minutes")
+	})
+
+	t.Run("with a session timeout out of bounds", func(t *testing.T) {
+		options := OrganizationUpdateOptions{
+			SessionTimeout: Int(0),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for session timeout: must be between 1 and 20160 minutes")
+	})
+
+	t.Run("with an unknown collaborator auth policy", func(t *testing.T) {
+		options := OrganizationUpdateOptions{
+			CollaboratorAuthPolicy: AuthPolicy("saml"),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for collaborator auth policy")
+	})
+
+	t.Run("with an unknown default execution mode", func(t *testing.T) {
+		options := OrganizationUpdateOptions{
+			DefaultExecutionMode: ExecutionMode("cloud"),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for default execution mode")
+	})
+}
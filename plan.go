@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -22,6 +24,10 @@ type Plans interface {
 
 	// Logs retrieves the logs of a plan.
 	Logs(ctx context.Context, planID string) (io.Reader, error)
+
+	// ReadJSONOutput returns the JSON representation of a plan, in the
+	// format of terraform show -json.
+	ReadJSONOutput(ctx context.Context, planID string) ([]byte, error)
 }
 
 // plans implements Plans.
@@ -53,6 +59,7 @@ type Plan struct {
 	ResourceAdditions    int                   `jsonapi:"attr,resource-additions"`
 	ResourceChanges      int                   `jsonapi:"attr,resource-changes"`
 	ResourceDestructions int                   `jsonapi:"attr,resource-destructions"`
+	ResourceImports      int                   `jsonapi:"attr,resource-imports"`
 	Status               PlanStatus            `jsonapi:"attr,status"`
 	StatusTimestamps     *PlanStatusTimestamps `jsonapi:"attr,status-timestamps"`
 
@@ -66,13 +73,14 @@ type PlanStatusTimestamps struct {
 	ErroredAt       time.Time `json:"errored-at"`
 	FinishedAt      time.Time `json:"finished-at"`
 	ForceCanceledAt time.Time `json:"force-canceled-at"`
+	PendingAt       time.Time `json:"pending-at"`
 	QueuedAt        time.Time `json:"queued-at"`
 	StartedAt       time.Time `json:"started-at"`
 }
 
 // Read a plan by its ID.
 func (s *plans) Read(ctx context.Context, planID string) (*Plan, error) {
-	if !validStringID(&planID) {
+	if !validResourceID(&planID, "plan-") {
 		return nil, errors.New("invalid value for plan ID")
 	}
 
@@ -91,9 +99,10 @@ func (s *plans) Read(ctx context.Context, planID string) (*Plan, error) {
 	return p, nil
 }
 
-// Logs retrieves the logs of a plan.
+// Logs retrieves the logs of a plan. If the plan has not started yet,
+// an empty reader is returned.
 func (s *plans) Logs(ctx context.Context, planID string) (io.Reader, error) {
-	if !validStringID(&planID) {
+	if !validResourceID(&planID, "plan-") {
 		return nil, errors.New("invalid value for plan ID")
 	}
 
@@ -103,9 +112,10 @@ func (s *plans) Logs(ctx context.Context, planID string) (io.Reader, error) {
 		return nil, err
 	}
 
-	// Return an error if the log URL is empty.
+	// The log URL is only set once the plan has started, until then
+	// there are no logs to read.
 	if p.LogReadURL == "" {
-		return nil, fmt.Errorf("plan %s does not have a log URL", planID)
+		return strings.NewReader(""), nil
 	}
 
 	u, err := url.Parse(p.LogReadURL)
@@ -134,3 +144,33 @@ func (s *plans) Logs(ctx context.Context, planID string) (io.Reader, error) {
 		logURL: u,
 	}, nil
 }
+
+// ReadJSONOutput returns the JSON representation of a plan, in the format of
+// terraform show -json. The output is returned as is, without decoding it.
+// If the plan has not finished yet, ErrPlanJSONOutputNotAvailable is
+// returned, so the caller can retry later.
+func (s *plans) ReadJSONOutput(ctx context.Context, planID string) ([]byte, error) {
+	if !validResourceID(&planID, "plan-") {
+		return nil, errors.New("invalid value for plan ID")
+	}
+
+	// The output is served by archivist, which the API redirects to.
+	u := fmt.Sprintf("plans/%s/json-output", url.QueryEscape(planID))
+	body, err := s.client.doDownload(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	output, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// There is no output until the plan has finished.
+	if len(output) == 0 {
+		return nil, ErrPlanJSONOutputNotAvailable
+	}
+
+	return output, nil
+}
@@ -1,10 +1,10 @@
 package tfe
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"time"
 )
@@ -119,7 +119,7 @@ func (s *planExports) Create(ctx context.Context, options PlanExportCreateOption
 
 // Read a plan export by its ID.
 func (s *planExports) Read(ctx context.Context, planExportID string) (*PlanExport, error) {
-	if !validStringID(&planExportID) {
+	if !validResourceID(&planExportID, "pe-") {
 		return nil, errors.New("invalid value for plan export ID")
 	}
 
@@ -140,7 +140,7 @@ func (s *planExports) Read(ctx context.Context, planExportID string) (*PlanExpor
 
 // Delete a plan export by ID.
 func (s *planExports) Delete(ctx context.Context, planExportID string) error {
-	if !validStringID(&planExportID) {
+	if !validResourceID(&planExportID, "pe-") {
 		return errors.New("invalid value for plan export ID")
 	}
 
@@ -153,23 +153,46 @@ func (s *planExports) Delete(ctx context.Context, planExportID string) error {
 	return s.client.do(ctx, req, nil)
 }
 
-// Download a plan export's data. Data is exported in a .tar.gz format.
+// Download a plan export's data. Data is exported in a .tar.gz format. If
+// the export has not finished yet, a *PlanExportNotReadyError is returned,
+// so the caller can retry later.
 func (s *planExports) Download(ctx context.Context, planExportID string) ([]byte, error) {
-	if !validStringID(&planExportID) {
+	if !validResourceID(&planExportID, "pe-") {
 		return nil, errors.New("invalid value for plan export ID")
 	}
 
+	// The data is served by archivist, which the API redirects to.
 	u := fmt.Sprintf("plan-exports/%s/download", url.QueryEscape(planExportID))
-	req, err := s.client.newRequest("GET", u, nil)
-	if err != nil {
-		return nil, err
+	body, err := s.client.doDownload(ctx, u)
+	if err == nil {
+		defer body.Close()
+
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > 0 {
+			return data, nil
+		}
 	}
 
-	var buf bytes.Buffer
-	err = s.client.do(ctx, req, &buf)
+	// The data is only available once the export has finished, so check
+	// the status of the export to tell if the caller should retry.
+	pe, rerr := s.Read(ctx, planExportID)
+	if rerr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, rerr
+	}
+
+	switch pe.Status {
+	case PlanExportPending, PlanExportQueued:
+		return nil, &PlanExportNotReadyError{Status: pe.Status}
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	return nil, fmt.Errorf("plan export %s has no data", planExportID)
 }
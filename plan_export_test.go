@@ -2,6 +2,10 @@ package tfe
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -88,7 +92,7 @@ func TestPlanExportsDelete(t *testing.T) {
 
 	t.Run("when the export does not exist", func(t *testing.T) {
 		err := client.Policies.Delete(ctx, "pe-doesntexist")
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid ID", func(t *testing.T) {
@@ -116,3 +120,75 @@ func TestPlanExportsDownload(t *testing.T) {
 		assert.EqualError(t, err, "invalid value for plan export ID")
 	})
 }
+
+func TestPlanExportsDownloadFixture(t *testing.T) {
+	const bundle = "\x1f\x8b\x08\x00sentinel-mock-bundle"
+
+	var archivistAuth string
+	archivist := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archivistAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte(bundle))
+	}))
+	defer archivist.Close()
+
+	status := PlanExportPending
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/plan-exports/pe-123":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": "pe-123", "type": "plan-exports", "attributes": {
+				"data-type": "sentinel-mock-bundle-v0",
+				"status": %q
+			}}}`, status)
+		case "/api/v2/plan-exports/pe-123/download":
+			if status != PlanExportFinished {
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				w.WriteHeader(409)
+				w.Write([]byte(`{"errors": [{"status": "409", "title": "conflict"}]}`))
+				return
+			}
+			http.Redirect(w, r, archivist.URL+"/object/pe-123", http.StatusTemporaryRedirect)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	for _, s := range []PlanExportStatus{PlanExportPending, PlanExportQueued} {
+		status = s
+
+		data, err := client.PlanExports.Download(ctx, "pe-123")
+		assert.Nil(t, data)
+		assert.True(t, errors.Is(err, ErrPlanExportNotReady), err)
+
+		var notReady *PlanExportNotReadyError
+		require.True(t, errors.As(err, &notReady))
+		assert.Equal(t, s, notReady.Status)
+	}
+
+	status = PlanExportFinished
+
+	data, err := client.PlanExports.Download(ctx, "pe-123")
+	require.NoError(t, err)
+	assert.Equal(t, bundle, string(data))
+	assert.Empty(t, archivistAuth)
+
+	t.Run("when the export has expired", func(t *testing.T) {
+		status = PlanExportExpired
+
+		data, err := client.PlanExports.Download(ctx, "pe-123")
+		assert.Nil(t, data)
+		assert.False(t, errors.Is(err, ErrPlanExportNotReady))
+		var errResp *ErrorResponse
+		assert.True(t, errors.As(err, &errResp), err)
+	})
+
+	t.Run("when the export does not exist", func(t *testing.T) {
+		data, err := client.PlanExports.Download(ctx, "pe-nonexisting")
+		assert.Nil(t, data)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+}
@@ -2,8 +2,14 @@ package tfe
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,9 +32,9 @@ func TestPlansRead(t *testing.T) {
 	})
 
 	t.Run("when the plan does not exist", func(t *testing.T) {
-		p, err := client.Plans.Read(ctx, "nonexisting")
+		p, err := client.Plans.Read(ctx, "plan-nonexisting")
 		assert.Nil(t, p)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid plan ID", func(t *testing.T) {
@@ -59,8 +65,157 @@ func TestPlansLogs(t *testing.T) {
 	})
 
 	t.Run("when the log does not exist", func(t *testing.T) {
-		logs, err := client.Plans.Logs(ctx, "nonexisting")
+		logs, err := client.Plans.Logs(ctx, "plan-nonexisting")
 		assert.Nil(t, logs)
 		assert.Error(t, err)
 	})
 }
+
+func TestPlansLogsFixture(t *testing.T) {
+	const logs = "\x02Terraform v0.12.24\nPlan: 1 to add, 0 to change, 0 to destroy.\x03"
+
+	var logURL, logAuth string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/plans/plan-pending":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write([]byte(`{"data": {"id": "plan-pending", "type": "plans", "attributes": {"status": "pending"}}}`))
+		case "/api/v2/plans/plan-finished":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": "plan-finished", "type": "plans", "attributes": {
+				"status": "finished",
+				"log-read-url": %q
+			}}}`, logURL)
+		case "/logs/plan-finished":
+			logAuth = r.Header.Get("Authorization")
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if offset > len(logs) {
+				offset = len(logs)
+			}
+			end := offset + limit
+			if end > len(logs) {
+				end = len(logs)
+			}
+			w.Write([]byte(logs[offset:end]))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+	logURL = ts.URL + "/logs/plan-finished"
+
+	ctx := context.Background()
+
+	t.Run("when the plan has finished", func(t *testing.T) {
+		logReader, err := client.Plans.Logs(ctx, "plan-finished")
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(logReader)
+		require.NoError(t, err)
+		assert.Equal(t, "Terraform v0.12.24\nPlan: 1 to add, 0 to change, 0 to destroy.", string(body))
+		assert.Empty(t, logAuth)
+	})
+
+	t.Run("when the plan has not started", func(t *testing.T) {
+		logReader, err := client.Plans.Logs(ctx, "plan-pending")
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(logReader)
+		require.NoError(t, err)
+		assert.Empty(t, body)
+	})
+
+	t.Run("when the plan does not exist", func(t *testing.T) {
+		logReader, err := client.Plans.Logs(ctx, "plan-nonexisting")
+		assert.Nil(t, logReader)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+}
+
+func TestPlansReadJSONOutputFixture(t *testing.T) {
+	output, err := ioutil.ReadFile("test-fixtures/plan-json/plan.json")
+	require.NoError(t, err)
+
+	var archivistAuth string
+	archivist := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archivistAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(output)
+	}))
+	defer archivist.Close()
+
+	var apiAuth string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/plans/plan-finished/json-output":
+			apiAuth = r.Header.Get("Authorization")
+			http.Redirect(w, r, archivist.URL+"/object/plan-finished", http.StatusTemporaryRedirect)
+		case "/api/v2/plans/plan-running/json-output":
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when the plan has finished", func(t *testing.T) {
+		body, err := client.Plans.ReadJSONOutput(ctx, "plan-finished")
+		require.NoError(t, err)
+		assert.Equal(t, output, body)
+		assert.Equal(t, "Bearer dummy-token", apiAuth)
+		assert.Empty(t, archivistAuth)
+	})
+
+	t.Run("when the plan is still running", func(t *testing.T) {
+		body, err := client.Plans.ReadJSONOutput(ctx, "plan-running")
+		assert.Nil(t, body)
+		assert.True(t, errors.Is(err, ErrPlanJSONOutputNotAvailable), err)
+	})
+
+	t.Run("when the plan does not exist", func(t *testing.T) {
+		body, err := client.Plans.ReadJSONOutput(ctx, "plan-nonexisting")
+		assert.Nil(t, body)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with invalid plan ID", func(t *testing.T) {
+		body, err := client.Plans.ReadJSONOutput(ctx, badIdentifier)
+		assert.Nil(t, body)
+		assert.EqualError(t, err, "invalid value for plan ID")
+	})
+}
+
+func TestPlansReadFixture(t *testing.T) {
+	plan, err := ioutil.ReadFile("test-fixtures/plan/read.json")
+	require.NoError(t, err)
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(plan)
+	})
+	defer ts.Close()
+
+	p, err := client.Plans.Read(context.Background(), "plan-8F5JFydVYAmtTjET")
+	require.NoError(t, err)
+
+	assert.Equal(t, PlanFinished, p.Status)
+	assert.True(t, p.HasChanges)
+	assert.Equal(t, 2, p.ResourceAdditions)
+	assert.Equal(t, 1, p.ResourceChanges)
+	assert.Equal(t, 0, p.ResourceDestructions)
+	assert.Equal(t, 3, p.ResourceImports)
+
+	require.NotNil(t, p.StatusTimestamps)
+	timestamps := p.StatusTimestamps
+	assert.True(t, timestamps.QueuedAt.Equal(time.Date(2020, 3, 16, 23, 15, 59, 0, time.UTC)))
+	assert.True(t, timestamps.PendingAt.Equal(time.Date(2020, 3, 16, 23, 15, 59, 0, time.UTC)))
+	assert.True(t, timestamps.StartedAt.Equal(time.Date(2020, 3, 16, 23, 16, 3, 0, time.UTC)))
+	assert.True(t, timestamps.FinishedAt.Equal(time.Date(2020, 3, 16, 23, 16, 13, 575000000, time.UTC)))
+
+	// Statuses the plan did not go through are left at the zero time.
+	assert.True(t, timestamps.ErroredAt.IsZero())
+	assert.True(t, timestamps.CanceledAt.IsZero())
+}
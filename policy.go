@@ -179,7 +179,7 @@ func (s *policies) Create(ctx context.Context, organization string, options Poli
 
 // Read a policy by its ID.
 func (s *policies) Read(ctx context.Context, policyID string) (*Policy, error) {
-	if !validStringID(&policyID) {
+	if !validResourceID(&policyID, "pol-") {
 		return nil, errors.New("invalid value for policy ID")
 	}
 
@@ -212,7 +212,7 @@ type PolicyUpdateOptions struct {
 
 // Update an existing policy.
 func (s *policies) Update(ctx context.Context, policyID string, options PolicyUpdateOptions) (*Policy, error) {
-	if !validStringID(&policyID) {
+	if !validResourceID(&policyID, "pol-") {
 		return nil, errors.New("invalid value for policy ID")
 	}
 
@@ -236,7 +236,7 @@ func (s *policies) Update(ctx context.Context, policyID string, options PolicyUp
 
 // Delete a policy by its ID.
 func (s *policies) Delete(ctx context.Context, policyID string) error {
-	if !validStringID(&policyID) {
+	if !validResourceID(&policyID, "pol-") {
 		return errors.New("invalid value for policy ID")
 	}
 
@@ -251,7 +251,7 @@ func (s *policies) Delete(ctx context.Context, policyID string) error {
 
 // Upload the policy content of the policy.
 func (s *policies) Upload(ctx context.Context, policyID string, content []byte) error {
-	if !validStringID(&policyID) {
+	if !validResourceID(&policyID, "pol-") {
 		return errors.New("invalid value for policy ID")
 	}
 
@@ -266,7 +266,7 @@ func (s *policies) Upload(ctx context.Context, policyID string, content []byte)
 
 // Download the policy content of the policy.
 func (s *policies) Download(ctx context.Context, policyID string) ([]byte, error) {
-	if !validStringID(&policyID) {
+	if !validResourceID(&policyID, "pol-") {
 		return nil, errors.New("invalid value for policy ID")
 	}
 
@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"testing"
 
@@ -102,9 +103,9 @@ func TestPolicyChecksRead(t *testing.T) {
 	})
 
 	t.Run("when the policy check does not exist", func(t *testing.T) {
-		pc, err := client.PolicyChecks.Read(ctx, "nonexisting")
+		pc, err := client.PolicyChecks.Read(ctx, "polchk-nonexisting")
 		assert.Nil(t, pc)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid policy check ID", func(t *testing.T) {
@@ -193,7 +194,7 @@ func TestPolicyChecksLogs(t *testing.T) {
 	})
 
 	t.Run("when the log does not exist", func(t *testing.T) {
-		logs, err := client.PolicyChecks.Logs(ctx, "nonexisting")
+		logs, err := client.PolicyChecks.Logs(ctx, "polchk-nonexisting")
 		assert.Nil(t, logs)
 		assert.Error(t, err)
 	})
@@ -177,7 +177,7 @@ func (s *policySets) Create(ctx context.Context, organization string, options Po
 
 // Read a policy set by its ID.
 func (s *policySets) Read(ctx context.Context, policySetID string) (*PolicySet, error) {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return nil, errors.New("invalid value for policy set ID")
 	}
 
@@ -220,7 +220,7 @@ func (o PolicySetUpdateOptions) valid() error {
 
 // Update an existing policy set.
 func (s *policySets) Update(ctx context.Context, policySetID string, options PolicySetUpdateOptions) (*PolicySet, error) {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return nil, errors.New("invalid value for policy set ID")
 	}
 	if err := options.valid(); err != nil {
@@ -264,7 +264,7 @@ func (o PolicySetAddPoliciesOptions) valid() error {
 
 // Add policies to a policy set
 func (s *policySets) AddPolicies(ctx context.Context, policySetID string, options PolicySetAddPoliciesOptions) error {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return errors.New("invalid value for policy set ID")
 	}
 	if err := options.valid(); err != nil {
@@ -272,12 +272,13 @@ func (s *policySets) AddPolicies(ctx context.Context, policySetID string, option
 	}
 
 	u := fmt.Sprintf("policy-sets/%s/relationships/policies", url.QueryEscape(policySetID))
-	req, err := s.client.newRequest("POST", u, options.Policies)
+	req, err := s.client.newRelationshipRequest("POST", u, options.Policies)
 	if err != nil {
 		return err
 	}
 
-	return s.client.do(ctx, req, nil)
+	// Adding policies that are already attached has no effect.
+	return s.client.do(ctx, withRetryable(req), nil)
 }
 
 // PolicySetRemovePoliciesOptions represents the options for removing
@@ -299,7 +300,7 @@ func (o PolicySetRemovePoliciesOptions) valid() error {
 
 // Remove policies from a policy set
 func (s *policySets) RemovePolicies(ctx context.Context, policySetID string, options PolicySetRemovePoliciesOptions) error {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return errors.New("invalid value for policy set ID")
 	}
 	if err := options.valid(); err != nil {
@@ -307,7 +308,7 @@ func (s *policySets) RemovePolicies(ctx context.Context, policySetID string, opt
 	}
 
 	u := fmt.Sprintf("policy-sets/%s/relationships/policies", url.QueryEscape(policySetID))
-	req, err := s.client.newRequest("DELETE", u, options.Policies)
+	req, err := s.client.newRelationshipRequest("DELETE", u, options.Policies)
 	if err != nil {
 		return err
 	}
@@ -334,7 +335,7 @@ func (o PolicySetAddWorkspacesOptions) valid() error {
 
 // Add workspaces to a policy set.
 func (s *policySets) AddWorkspaces(ctx context.Context, policySetID string, options PolicySetAddWorkspacesOptions) error {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return errors.New("invalid value for policy set ID")
 	}
 	if err := options.valid(); err != nil {
@@ -342,12 +343,13 @@ func (s *policySets) AddWorkspaces(ctx context.Context, policySetID string, opti
 	}
 
 	u := fmt.Sprintf("policy-sets/%s/relationships/workspaces", url.QueryEscape(policySetID))
-	req, err := s.client.newRequest("POST", u, options.Workspaces)
+	req, err := s.client.newRelationshipRequest("POST", u, options.Workspaces)
 	if err != nil {
 		return err
 	}
 
-	return s.client.do(ctx, req, nil)
+	// Adding workspaces that are already attached has no effect.
+	return s.client.do(ctx, withRetryable(req), nil)
 }
 
 // PolicySetRemoveWorkspacesOptions represents the options for removing
@@ -369,7 +371,7 @@ func (o PolicySetRemoveWorkspacesOptions) valid() error {
 
 // Remove workspaces from a policy set.
 func (s *policySets) RemoveWorkspaces(ctx context.Context, policySetID string, options PolicySetRemoveWorkspacesOptions) error {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return errors.New("invalid value for policy set ID")
 	}
 	if err := options.valid(); err != nil {
@@ -377,7 +379,7 @@ func (s *policySets) RemoveWorkspaces(ctx context.Context, policySetID string, o
 	}
 
 	u := fmt.Sprintf("policy-sets/%s/relationships/workspaces", url.QueryEscape(policySetID))
-	req, err := s.client.newRequest("DELETE", u, options.Workspaces)
+	req, err := s.client.newRelationshipRequest("DELETE", u, options.Workspaces)
 	if err != nil {
 		return err
 	}
@@ -387,7 +389,7 @@ func (s *policySets) RemoveWorkspaces(ctx context.Context, policySetID string, o
 
 // Delete a policy set by its ID.
 func (s *policySets) Delete(ctx context.Context, policySetID string) error {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return errors.New("invalid value for policy set ID")
 	}
 
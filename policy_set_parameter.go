@@ -65,7 +65,7 @@ func (o PolicySetParameterListOptions) valid() error {
 
 // List all the parameters associated with the given policy-set.
 func (s *policySetParameters) List(ctx context.Context, policySetID string, options PolicySetParameterListOptions) (*PolicySetParameterList, error) {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return nil, errors.New("invalid value for policy set ID")
 	}
 	if err := options.valid(); err != nil {
@@ -120,7 +120,7 @@ func (o PolicySetParameterCreateOptions) valid() error {
 
 // Create is used to create a new parameter.
 func (s *policySetParameters) Create(ctx context.Context, policySetID string, options PolicySetParameterCreateOptions) (*PolicySetParameter, error) {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return nil, errors.New("invalid value for policy set ID")
 	}
 	if err := options.valid(); err != nil {
@@ -147,10 +147,10 @@ func (s *policySetParameters) Create(ctx context.Context, policySetID string, op
 
 // Read a parameter by its ID.
 func (s *policySetParameters) Read(ctx context.Context, policySetID string, parameterID string) (*PolicySetParameter, error) {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return nil, errors.New("invalid value for policy set ID")
 	}
-	if !validStringID(&parameterID) {
+	if !validResourceID(&parameterID, "var-") {
 		return nil, errors.New("invalid value for parameter ID")
 	}
 
@@ -184,14 +184,24 @@ type PolicySetParameterUpdateOptions struct {
 	Sensitive *bool `jsonapi:"attr,sensitive,omitempty"`
 }
 
+func (o PolicySetParameterUpdateOptions) valid() error {
+	if o.Key != nil && !validString(o.Key) {
+		return errors.New("invalid value for key")
+	}
+	return nil
+}
+
 // Update values of an existing parameter.
 func (s *policySetParameters) Update(ctx context.Context, policySetID string, parameterID string, options PolicySetParameterUpdateOptions) (*PolicySetParameter, error) {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return nil, errors.New("invalid value for policy set ID")
 	}
-	if !validStringID(&parameterID) {
+	if !validResourceID(&parameterID, "var-") {
 		return nil, errors.New("invalid value for parameter ID")
 	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
 
 	// Make sure we don't send a user provided ID.
 	options.ID = parameterID
@@ -213,10 +223,10 @@ func (s *policySetParameters) Update(ctx context.Context, policySetID string, pa
 
 // Delete a parameter by its ID.
 func (s *policySetParameters) Delete(ctx context.Context, policySetID string, parameterID string) error {
-	if !validStringID(&policySetID) {
+	if !validResourceID(&policySetID, "polset-") {
 		return errors.New("invalid value for policy set ID")
 	}
-	if !validStringID(&parameterID) {
+	if !validResourceID(&parameterID, "var-") {
 		return errors.New("invalid value for parameter ID")
 	}
 
@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -174,9 +175,9 @@ func TestPolicySetParametersRead(t *testing.T) {
 	})
 
 	t.Run("when the parameter does not exist", func(t *testing.T) {
-		p, err := client.PolicySetParameters.Read(ctx, pTest.PolicySet.ID, "nonexisting")
+		p, err := client.PolicySetParameters.Read(ctx, pTest.PolicySet.ID, "var-nonexisting")
 		assert.Nil(t, p)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid policy set ID", func(t *testing.T) {
@@ -271,8 +272,8 @@ func TestParametersDelete(t *testing.T) {
 	})
 
 	t.Run("with non existing parameter ID", func(t *testing.T) {
-		err := client.PolicySetParameters.Delete(ctx, psTest.ID, "nonexisting")
-		assert.Equal(t, err, ErrResourceNotFound)
+		err := client.PolicySetParameters.Delete(ctx, psTest.ID, "var-nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid policy set ID", func(t *testing.T) {
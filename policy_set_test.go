@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
@@ -474,12 +475,12 @@ func TestPolicySetsDelete(t *testing.T) {
 
 		// Try loading the policy - it should fail.
 		_, err = client.PolicySets.Read(ctx, psTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the policy does not exist", func(t *testing.T) {
 		err := client.PolicySets.Delete(ctx, psTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the policy ID is invalid", func(t *testing.T) {
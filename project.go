@@ -0,0 +1,10 @@
+package tfe
+
+// Project represents a Terraform Enterprise project, which groups the
+// workspaces of an organization. A workspace is moved to a project by
+// referencing the project by its ID in the workspace create or update
+// options.
+type Project struct {
+	ID   string `jsonapi:"primary,projects"`
+	Name string `jsonapi:"attr,name"`
+}
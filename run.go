@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,9 @@ type Runs interface {
 	// Read a run by its ID.
 	Read(ctx context.Context, runID string) (*Run, error)
 
+	// ReadWithOptions reads a run by its ID using the given options.
+	ReadWithOptions(ctx context.Context, runID string, options RunReadOptions) (*Run, error)
+
 	// Apply a run by its ID.
 	Apply(ctx context.Context, runID string, options RunApplyOptions) error
 
@@ -34,8 +38,14 @@ type Runs interface {
 	// Force-cancel a run by its ID.
 	ForceCancel(ctx context.Context, runID string, options RunForceCancelOptions) error
 
+	// ForceExecute a run by its ID, canceling the runs that are blocking it.
+	ForceExecute(ctx context.Context, runID string) error
+
 	// Discard a run by its ID.
 	Discard(ctx context.Context, runID string, options RunDiscardOptions) error
+
+	// ListVariables lists the variables that applied to the given run.
+	ListVariables(ctx context.Context, runID string, options RunEffectiveVariableListOptions) (*RunEffectiveVariableList, error)
 }
 
 // runs implements Runs.
@@ -46,7 +56,7 @@ type runs struct {
 // RunStatus represents a run state.
 type RunStatus string
 
-//List all available run statuses.
+// List all available run statuses.
 const (
 	RunApplied            RunStatus = "applied"
 	RunApplyQueued        RunStatus = "apply_queued"
@@ -78,31 +88,59 @@ const (
 	RunSourceUI                   RunSource = "tfe-ui"
 )
 
+// RunOperation represents the operation of a run.
+type RunOperation string
+
+// List all available run operations.
+const (
+	RunOperationDestroy      RunOperation = "destroy"
+	RunOperationPlanAndApply RunOperation = "plan_and_apply"
+	RunOperationPlanOnly     RunOperation = "plan_only"
+	RunOperationRefreshOnly  RunOperation = "refresh_only"
+	RunOperationSavePlan     RunOperation = "save_plan"
+)
+
 // RunList represents a list of runs.
 type RunList struct {
 	*Pagination
+	Meta  *RunListMeta
 	Items []*Run
 }
 
+// RunListMeta represents the meta details of a list of runs.
+type RunListMeta struct {
+	// The number of runs per run status, including the "total" count.
+	StatusCounts map[string]int `json:"status-counts"`
+}
+
 // Run represents a Terraform Enterprise run.
 type Run struct {
 	ID                     string               `jsonapi:"primary,runs"`
 	Actions                *RunActions          `jsonapi:"attr,actions"`
+	AllowEmptyApply        bool                 `jsonapi:"attr,allow-empty-apply"`
+	AutoApply              bool                 `jsonapi:"attr,auto-apply"`
 	CreatedAt              time.Time            `jsonapi:"attr,created-at,iso8601"`
 	ForceCancelAvailableAt time.Time            `jsonapi:"attr,force-cancel-available-at,iso8601"`
 	HasChanges             bool                 `jsonapi:"attr,has-changes"`
 	IsDestroy              bool                 `jsonapi:"attr,is-destroy"`
 	Message                string               `jsonapi:"attr,message"`
 	Permissions            *RunPermissions      `jsonapi:"attr,permissions"`
+	PlanOnly               bool                 `jsonapi:"attr,plan-only"`
 	PositionInQueue        int                  `jsonapi:"attr,position-in-queue"`
+	Refresh                bool                 `jsonapi:"attr,refresh"`
+	RefreshOnly            bool                 `jsonapi:"attr,refresh-only"`
+	ReplaceAddrs           []string             `jsonapi:"attr,replace-addrs"`
+	SavePlan               bool                 `jsonapi:"attr,save-plan"`
 	Source                 RunSource            `jsonapi:"attr,source"`
 	Status                 RunStatus            `jsonapi:"attr,status"`
 	StatusTimestamps       *RunStatusTimestamps `jsonapi:"attr,status-timestamps"`
+	TargetAddrs            []string             `jsonapi:"attr,target-addrs"`
 
 	// Relations
 	Apply                *Apply                `jsonapi:"relation,apply"`
 	ConfigurationVersion *ConfigurationVersion `jsonapi:"relation,configuration-version"`
 	CostEstimate         *CostEstimate         `jsonapi:"relation,cost-estimate"`
+	CreatedBy            *User                 `jsonapi:"relation,created-by"`
 	Plan                 *Plan                 `jsonapi:"relation,plan"`
 	PolicyChecks         []*PolicyCheck        `jsonapi:"relation,policy-checks"`
 	Workspace            *Workspace            `jsonapi:"relation,workspace"`
@@ -139,14 +177,49 @@ type RunStatusTimestamps struct {
 	PlanQueuabledAt      time.Time `json:"plan-queueable-at"`
 }
 
+// RunIncludeOpt represents the available options for include query params.
+// https://www.terraform.io/docs/enterprise/api/run.html#available-related-resources
+type RunIncludeOpt string
+
+// List all available run include options.
+const (
+	RunPlan         RunIncludeOpt = "plan"
+	RunApply        RunIncludeOpt = "apply"
+	RunCostEstimate RunIncludeOpt = "cost_estimate"
+	RunConfigVer    RunIncludeOpt = "configuration_version"
+	RunCreatedBy    RunIncludeOpt = "created_by"
+	RunWorkspace    RunIncludeOpt = "workspace"
+)
+
 // RunListOptions represents the options for listing runs.
 type RunListOptions struct {
 	ListOptions
+
+	// A list of statuses to filter the runs by.
+	Status []RunStatus `url:"filter[status],omitempty,comma"`
+
+	// A list of operations to filter the runs by.
+	Operation []RunOperation `url:"filter[operation],omitempty,comma"`
+
+	// A list of sources to filter the runs by.
+	Source []RunSource `url:"filter[source],omitempty,comma"`
+
+	// A search query on the username of the user that created the runs.
+	User string `url:"search[user],omitempty"`
+
+	// A search query on the commit SHA that triggered the runs.
+	Commit string `url:"search[commit],omitempty"`
+
+	// A search query on the run ID, message, commit SHA and username.
+	Search string `url:"search[basic],omitempty"`
+
+	// A list of relations to include.
+	Include []RunIncludeOpt `url:"include,omitempty,comma"`
 }
 
 // List all the runs of the given workspace.
 func (s *runs) List(ctx context.Context, workspaceID string, options RunListOptions) (*RunList, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -170,6 +243,14 @@ type RunCreateOptions struct {
 	// For internal use only!
 	ID string `jsonapi:"primary,runs"`
 
+	// Whether to allow the run to be applied even if the plan has no
+	// changes, for example to update the outputs.
+	AllowEmptyApply *bool `jsonapi:"attr,allow-empty-apply,omitempty"`
+
+	// Whether to automatically apply the run when the plan is successful.
+	// If omitted, the auto-apply setting of the workspace is used.
+	AutoApply *bool `jsonapi:"attr,auto-apply,omitempty"`
+
 	// Specifies if this plan is a destroy plan, which will destroy all
 	// provisioned resources.
 	IsDestroy *bool `jsonapi:"attr,is-destroy,omitempty"`
@@ -177,19 +258,119 @@ type RunCreateOptions struct {
 	// Specifies the message to be associated with this run.
 	Message *string `jsonapi:"attr,message,omitempty"`
 
+	// Whether to create a speculative plan, which cannot be applied.
+	PlanOnly *bool `jsonapi:"attr,plan-only,omitempty"`
+
+	// Whether to refresh the state before planning. If omitted, the state
+	// is refreshed.
+	Refresh *bool `jsonapi:"attr,refresh,omitempty"`
+
+	// Whether to only refresh the state, without planning any changes. It
+	// cannot be combined with IsDestroy.
+	RefreshOnly *bool `jsonapi:"attr,refresh-only,omitempty"`
+
+	// A list of resource addresses to replace, just like the -replace flag
+	// of terraform plan.
+	ReplaceAddrs []string `jsonapi:"attr,replace-addrs,omitempty"`
+
+	// Whether to save the plan, so it can be applied later. The run stops
+	// after planning, and applying it using Apply applies the exact saved
+	// plan, even if the configuration or state has changed since.
+	SavePlan *bool `jsonapi:"attr,save-plan,omitempty"`
+
+	// A list of resource addresses to target, just like the -target flag
+	// of terraform plan.
+	TargetAddrs []string `jsonapi:"attr,target-addrs,omitempty"`
+
+	// A list of variables that only apply to this run. They take precedence
+	// over the variables of the workspace.
+	Variables []*RunVariable `jsonapi:"attr,variables,omitempty"`
+
 	// Specifies the configuration version to use for this run. If the
 	// configuration version object is omitted, the run will be created using the
 	// workspace's latest configuration version.
-	ConfigurationVersion *ConfigurationVersion `jsonapi:"relation,configuration-version"`
+	ConfigurationVersion *ConfigurationVersion `jsonapi:"relation,configuration-version,omitempty"`
 
 	// Specifies the workspace where the run will be executed.
 	Workspace *Workspace `jsonapi:"relation,workspace"`
+
+	// Optional timeout for creating the run. If the given context has an
+	// earlier deadline, that deadline is used instead.
+	Timeout time.Duration
+}
+
+// RunVariable represents a variable that only applies to a single run. The
+// value is parsed as HCL, so a string must be quoted, for example using
+// HCLString. Other values, like numbers, lists and maps, are written in
+// their HCL syntax, e.g. "42" or HCLList("a", "b").
+type RunVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// HCLString returns v as a quoted HCL string, which can be used as the value
+// of a run variable. Quotes, backslashes and control characters are escaped,
+// as are template sequences, so the value is used literally.
+func HCLString(v string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			// Escape the start of an interpolation or directive.
+			if i+1 < len(v) && v[i+1] == '{' {
+				b.WriteByte(c)
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// HCLList returns the values as an HCL list of strings, which can be used as
+// the value of a run variable.
+func HCLList(values ...string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = HCLString(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
 }
 
 func (o RunCreateOptions) valid() error {
 	if o.Workspace == nil {
 		return errors.New("workspace is required")
 	}
+	if !validWorkspaceID(&o.Workspace.ID) {
+		return errors.New("invalid value for workspace ID")
+	}
+	if o.ConfigurationVersion != nil && !validResourceID(&o.ConfigurationVersion.ID, "cv-") {
+		return errors.New("invalid value for configuration version ID")
+	}
+	if o.RefreshOnly != nil && *o.RefreshOnly && o.IsDestroy != nil && *o.IsDestroy {
+		return errors.New("only one of refresh only or is destroy can be set")
+	}
+	for _, v := range o.Variables {
+		if v == nil {
+			return errors.New("variables cannot contain nil values")
+		}
+		if !validString(&v.Key) {
+			return errors.New("variable key is required")
+		}
+	}
 	return nil
 }
 
@@ -206,6 +387,7 @@ func (s *runs) Create(ctx context.Context, options RunCreateOptions) (*Run, erro
 	if err != nil {
 		return nil, err
 	}
+	req = withTimeout(req, options.Timeout)
 
 	r := &Run{}
 	err = s.client.do(ctx, req, r)
@@ -218,12 +400,23 @@ func (s *runs) Create(ctx context.Context, options RunCreateOptions) (*Run, erro
 
 // Read a run by its ID.
 func (s *runs) Read(ctx context.Context, runID string) (*Run, error) {
-	if !validStringID(&runID) {
+	return s.ReadWithOptions(ctx, runID, RunReadOptions{})
+}
+
+// RunReadOptions represents the options for reading a run.
+type RunReadOptions struct {
+	// A list of relations to include.
+	Include []RunIncludeOpt `url:"include,omitempty,comma"`
+}
+
+// ReadWithOptions reads a run by its ID using the given options.
+func (s *runs) ReadWithOptions(ctx context.Context, runID string, options RunReadOptions) (*Run, error) {
+	if !validResourceID(&runID, "run-") {
 		return nil, errors.New("invalid value for run ID")
 	}
 
 	u := fmt.Sprintf("runs/%s", url.QueryEscape(runID))
-	req, err := s.client.newRequest("GET", u, nil)
+	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
 		return nil, err
 	}
@@ -245,7 +438,7 @@ type RunApplyOptions struct {
 
 // Apply a run by its ID.
 func (s *runs) Apply(ctx context.Context, runID string, options RunApplyOptions) error {
-	if !validStringID(&runID) {
+	if !validResourceID(&runID, "run-") {
 		return errors.New("invalid value for run ID")
 	}
 
@@ -266,7 +459,7 @@ type RunCancelOptions struct {
 
 // Cancel a run by its ID.
 func (s *runs) Cancel(ctx context.Context, runID string, options RunCancelOptions) error {
-	if !validStringID(&runID) {
+	if !validResourceID(&runID, "run-") {
 		return errors.New("invalid value for run ID")
 	}
 
@@ -285,9 +478,13 @@ type RunForceCancelOptions struct {
 	Comment *string `json:"comment,omitempty"`
 }
 
-// ForceCancel is used to forcefully cancel a run by its ID.
+// ForceCancel is used to forcefully cancel a run by its ID. A run can only
+// be force-canceled after it has been canceled and its cool-off period has
+// passed. If it is too early, the run is read to return a
+// *RunNotForceCancelableError with the time after which the run can be
+// force-canceled, so ForceCancel may make two requests.
 func (s *runs) ForceCancel(ctx context.Context, runID string, options RunForceCancelOptions) error {
-	if !validStringID(&runID) {
+	if !validResourceID(&runID, "run-") {
 		return errors.New("invalid value for run ID")
 	}
 
@@ -297,6 +494,38 @@ func (s *runs) ForceCancel(ctx context.Context, runID string, options RunForceCa
 		return err
 	}
 
+	err = s.client.do(ctx, req, nil)
+	if !errors.Is(err, ErrRunNotForceCancelable) {
+		return err
+	}
+
+	// Read the run to tell when it can be force-canceled.
+	r, rerr := s.Read(ctx, runID)
+	if rerr != nil {
+		return fmt.Errorf("%w (reading run: %v)", err, rerr)
+	}
+	if !r.ForceCancelAvailableAt.IsZero() && !r.ForceCancelAvailableAt.After(time.Now()) {
+		return err
+	}
+
+	return &RunNotForceCancelableError{AvailableAt: r.ForceCancelAvailableAt}
+}
+
+// ForceExecute is used to execute a pending run immediately, by canceling
+// the planned runs and discarding the pending runs ahead of it in the queue
+// of the workspace. This requires admin access to the workspace, otherwise
+// an error wrapping ErrForbidden is returned.
+func (s *runs) ForceExecute(ctx context.Context, runID string) error {
+	if !validResourceID(&runID, "run-") {
+		return errors.New("invalid value for run ID")
+	}
+
+	u := fmt.Sprintf("runs/%s/actions/force-execute", url.QueryEscape(runID))
+	req, err := s.client.newRequest("POST", u, nil)
+	if err != nil {
+		return err
+	}
+
 	return s.client.do(ctx, req, nil)
 }
 
@@ -308,7 +537,7 @@ type RunDiscardOptions struct {
 
 // Discard a run by its ID.
 func (s *runs) Discard(ctx context.Context, runID string, options RunDiscardOptions) error {
-	if !validStringID(&runID) {
+	if !validResourceID(&runID, "run-") {
 		return errors.New("invalid value for run ID")
 	}
 
@@ -320,3 +549,69 @@ func (s *runs) Discard(ctx context.Context, runID string, options RunDiscardOpti
 
 	return s.client.do(ctx, req, nil)
 }
+
+// RunVariableSource represents where a variable that applied to a run was
+// set.
+type RunVariableSource string
+
+// List all available run variable sources.
+const (
+	RunVariableSourceRun         RunVariableSource = "run"
+	RunVariableSourceVariableSet RunVariableSource = "varset"
+	RunVariableSourceWorkspace   RunVariableSource = "workspace"
+)
+
+// RunEffectiveVariableList represents a list of the variables that applied
+// to a run.
+type RunEffectiveVariableList struct {
+	*Pagination
+	Items []*RunEffectiveVariable
+}
+
+// RunEffectiveVariable represents a variable that applied to a run. A key
+// can be set by more than one source, in which case only the variable with
+// the highest precedence is used and the others are marked as overridden.
+// Run variables take precedence over workspace variables, which take
+// precedence over the variables of variable sets.
+//
+// The value of a sensitive variable is redacted, so Value is always empty
+// when Sensitive is true.
+type RunEffectiveVariable struct {
+	ID         string            `jsonapi:"primary,run-variables"`
+	Key        string            `jsonapi:"attr,key"`
+	Value      string            `jsonapi:"attr,value"`
+	Category   CategoryType      `jsonapi:"attr,category"`
+	HCL        bool              `jsonapi:"attr,hcl"`
+	Overridden bool              `jsonapi:"attr,overridden"`
+	Sensitive  bool              `jsonapi:"attr,sensitive"`
+	Source     RunVariableSource `jsonapi:"attr,source"`
+}
+
+// RunEffectiveVariableListOptions represents the options for listing the
+// variables that applied to a run.
+type RunEffectiveVariableListOptions struct {
+	ListOptions
+}
+
+// ListVariables lists the variables that applied to the given run,
+// including the variables of the run itself, of its workspace and of the
+// variable sets that apply to the workspace.
+func (s *runs) ListVariables(ctx context.Context, runID string, options RunEffectiveVariableListOptions) (*RunEffectiveVariableList, error) {
+	if !validResourceID(&runID, "run-") {
+		return nil, errors.New("invalid value for run ID")
+	}
+
+	u := fmt.Sprintf("runs/%s/run-variables", url.QueryEscape(runID))
+	req, err := s.client.newRequest("GET", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	vl := &RunEffectiveVariableList{}
+	err = s.client.do(ctx, req, vl)
+	if err != nil {
+		return nil, err
+	}
+
+	return vl, nil
+}
@@ -0,0 +1,123 @@
+package tfe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Compile-time proof of interface implementation.
+var _ RunEvents = (*runEvents)(nil)
+
+// RunEvents describes all the run events that the Terraform Enterprise
+// API supports.
+//
+// TFE API docs: https://www.terraform.io/docs/cloud/api/run-events.html
+type RunEvents interface {
+	// List all the run events of the given run.
+	List(ctx context.Context, runID string, options RunEventListOptions) (*RunEventList, error)
+
+	// Read a run event by its ID.
+	Read(ctx context.Context, runEventID string) (*RunEvent, error)
+
+	// ReadWithOptions reads a run event by its ID using the given options.
+	ReadWithOptions(ctx context.Context, runEventID string, options RunEventReadOptions) (*RunEvent, error)
+}
+
+// runEvents implements RunEvents.
+type runEvents struct {
+	client *Client
+}
+
+// RunEventList represents a list of run events.
+type RunEventList struct {
+	*Pagination
+	Items []*RunEvent
+}
+
+// RunEvent represents a Terraform Enterprise run event, which records a step
+// in the timeline of a run. The action is a string like "queued", "planned"
+// or "applied", and new actions may be added by the server at any time.
+type RunEvent struct {
+	ID          string    `jsonapi:"primary,run-events"`
+	Action      string    `jsonapi:"attr,action"`
+	CreatedAt   time.Time `jsonapi:"attr,created-at,iso8601"`
+	Description string    `jsonapi:"attr,description"`
+
+	// Relations
+	Actor   *User    `jsonapi:"relation,actor"`
+	Comment *Comment `jsonapi:"relation,comment"`
+}
+
+// RunEventIncludeOpt represents the available options for include query
+// params.
+type RunEventIncludeOpt string
+
+// List all available run event include options.
+const (
+	RunEventActor   RunEventIncludeOpt = "actor"
+	RunEventComment RunEventIncludeOpt = "comment"
+)
+
+// RunEventListOptions represents the options for listing run events.
+type RunEventListOptions struct {
+	ListOptions
+
+	// A list of relations to include.
+	Include []RunEventIncludeOpt `url:"include,omitempty,comma"`
+}
+
+// List all the run events of the given run.
+func (s *runEvents) List(ctx context.Context, runID string, options RunEventListOptions) (*RunEventList, error) {
+	if !validResourceID(&runID, "run-") {
+		return nil, errors.New("invalid value for run ID")
+	}
+
+	u := fmt.Sprintf("runs/%s/run-events", url.QueryEscape(runID))
+	req, err := s.client.newRequest("GET", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	rl := &RunEventList{}
+	err = s.client.do(ctx, req, rl)
+	if err != nil {
+		return nil, err
+	}
+
+	return rl, nil
+}
+
+// Read a run event by its ID.
+func (s *runEvents) Read(ctx context.Context, runEventID string) (*RunEvent, error) {
+	return s.ReadWithOptions(ctx, runEventID, RunEventReadOptions{})
+}
+
+// RunEventReadOptions represents the options for reading a run event.
+type RunEventReadOptions struct {
+	// A list of relations to include.
+	Include []RunEventIncludeOpt `url:"include,omitempty,comma"`
+}
+
+// ReadWithOptions reads a run event by its ID using the given options.
+func (s *runEvents) ReadWithOptions(ctx context.Context, runEventID string, options RunEventReadOptions) (*RunEvent, error) {
+	if !validStringID(&runEventID) {
+		return nil, errors.New("invalid value for run event ID")
+	}
+
+	u := fmt.Sprintf("run-events/%s", url.QueryEscape(runEventID))
+	req, err := s.client.newRequest("GET", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RunEvent{}
+	err = s.client.do(ctx, req, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,117 @@
+package tfe
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunEventsList(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	rTest, rTestCleanup := createPlannedRun(t, client, nil)
+	defer rTestCleanup()
+
+	t.Run("without list options", func(t *testing.T) {
+		rl, err := client.RunEvents.List(ctx, rTest.ID, RunEventListOptions{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, rl.Items)
+	})
+
+	t.Run("with includes", func(t *testing.T) {
+		rl, err := client.RunEvents.List(ctx, rTest.ID, RunEventListOptions{
+			Include: []RunEventIncludeOpt{RunEventActor, RunEventComment},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, rl.Items)
+		assert.NotEmpty(t, rl.Items[0].Action)
+	})
+
+	t.Run("with invalid run ID", func(t *testing.T) {
+		rl, err := client.RunEvents.List(ctx, badIdentifier, RunEventListOptions{})
+		assert.Nil(t, rl)
+		assert.EqualError(t, err, "invalid value for run ID")
+	})
+}
+
+func TestRunEventsRead(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	rTest, rTestCleanup := createPlannedRun(t, client, nil)
+	defer rTestCleanup()
+
+	rl, err := client.RunEvents.List(ctx, rTest.ID, RunEventListOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, rl.Items)
+
+	t.Run("when the run event exists", func(t *testing.T) {
+		re, err := client.RunEvents.Read(ctx, rl.Items[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, rl.Items[0].Action, re.Action)
+	})
+
+	t.Run("when the run event does not exist", func(t *testing.T) {
+		re, err := client.RunEvents.Read(ctx, "nonexisting")
+		assert.Nil(t, re)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with invalid run event ID", func(t *testing.T) {
+		re, err := client.RunEvents.Read(ctx, badIdentifier)
+		assert.Nil(t, re)
+		assert.EqualError(t, err, "invalid value for run event ID")
+	})
+}
+
+func TestRunEventsListFixture(t *testing.T) {
+	list, err := ioutil.ReadFile("test-fixtures/run-events/list.json")
+	require.NoError(t, err)
+
+	var query string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(list)
+	})
+	defer ts.Close()
+
+	rl, err := client.RunEvents.List(context.Background(), "run-123", RunEventListOptions{
+		ListOptions: ListOptions{PageSize: 3},
+		Include:     []RunEventIncludeOpt{RunEventActor, RunEventComment},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "include=actor%2Ccomment&page%5Bsize%5D=3", query)
+
+	assert.Equal(t, 1, rl.CurrentPage)
+	assert.Equal(t, 2, rl.NextPage)
+	assert.Equal(t, 2, rl.TotalPages)
+	assert.Equal(t, 5, rl.TotalCount)
+
+	require.Len(t, rl.Items, 3)
+
+	queued := rl.Items[0]
+	assert.Equal(t, "queued", queued.Action)
+	assert.Empty(t, queued.Description)
+	assert.Equal(t, 2021, queued.CreatedAt.Year())
+	require.NotNil(t, queued.Actor)
+	assert.Equal(t, "admin", queued.Actor.Username)
+	assert.Nil(t, queued.Comment)
+
+	applied := rl.Items[1]
+	assert.Equal(t, "applied", applied.Action)
+	assert.Equal(t, "Approved after review", applied.Description)
+	require.NotNil(t, applied.Comment)
+	assert.Equal(t, "Looks good to me", applied.Comment.Body)
+
+	// Unknown actions are decoded as is.
+	unknown := rl.Items[2]
+	assert.Equal(t, "teleported", unknown.Action)
+	assert.Nil(t, unknown.Actor)
+}
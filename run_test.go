@@ -2,6 +2,10 @@ package tfe
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
 	"testing"
 	"time"
 
@@ -59,6 +63,82 @@ func TestRunsList(t *testing.T) {
 	})
 }
 
+func TestRunsListFilters(t *testing.T) {
+	var query string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{
+			"data": [{"id": "run-123", "type": "runs", "attributes": {"status": "errored"}}],
+			"meta": {
+				"pagination": {"current-page": 1, "total-pages": 1, "total-count": 1},
+				"status-counts": {"applied": 12, "errored": 3, "total": 15}
+			}
+		}`))
+	})
+	defer ts.Close()
+
+	cases := map[string]struct {
+		options RunListOptions
+		query   string
+	}{
+		"without filters": {
+			options: RunListOptions{},
+			query:   "",
+		},
+		"with multiple statuses": {
+			options: RunListOptions{Status: []RunStatus{RunErrored, RunApplied}},
+			query:   "filter%5Bstatus%5D=errored%2Capplied",
+		},
+		"with operations and sources": {
+			options: RunListOptions{
+				Operation: []RunOperation{RunOperationPlanOnly, RunOperationDestroy},
+				Source:    []RunSource{RunSourceAPI},
+			},
+			query: "filter%5Boperation%5D=plan_only%2Cdestroy&filter%5Bsource%5D=tfe-api",
+		},
+		"with saved plans": {
+			options: RunListOptions{
+				Status:    []RunStatus{RunPlannedAndFinished},
+				Operation: []RunOperation{RunOperationSavePlan},
+			},
+			query: "filter%5Boperation%5D=save_plan&filter%5Bstatus%5D=planned_and_finished",
+		},
+		"with searches": {
+			options: RunListOptions{
+				User:   "admin",
+				Commit: "abc123",
+				Search: "fix",
+			},
+			query: "search%5Bbasic%5D=fix&search%5Bcommit%5D=abc123&search%5Buser%5D=admin",
+		},
+		"with all filters": {
+			options: RunListOptions{
+				ListOptions: ListOptions{PageNumber: 2, PageSize: 10},
+				Status:      []RunStatus{RunPlanned},
+				Operation:   []RunOperation{RunOperationPlanAndApply},
+				Source:      []RunSource{RunSourceUI, RunSourceConfigurationVersion},
+				User:        "admin",
+				Include:     []RunIncludeOpt{RunPlan},
+			},
+			query: "filter%5Boperation%5D=plan_and_apply&filter%5Bsource%5D=tfe-ui%2Ctfe-configuration-version" +
+				"&filter%5Bstatus%5D=planned&include=plan&page%5Bnumber%5D=2&page%5Bsize%5D=10&search%5Buser%5D=admin",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rl, err := client.Runs.List(context.Background(), "ws-123", tc.options)
+			require.NoError(t, err)
+			assert.Equal(t, tc.query, query)
+
+			require.Len(t, rl.Items, 1)
+			require.NotNil(t, rl.Meta)
+			assert.Equal(t, map[string]int{"applied": 12, "errored": 3, "total": 15}, rl.Meta.StatusCounts)
+		})
+	}
+}
+
 func TestRunsCreate(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -106,6 +186,178 @@ func TestRunsCreate(t *testing.T) {
 	})
 }
 
+func TestRunsCreatePayload(t *testing.T) {
+	var body map[string]interface{}
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(201)
+		w.Write([]byte(`{"data": {
+			"id": "run-123",
+			"type": "runs",
+			"attributes": {
+				"auto-apply": true,
+				"is-destroy": true,
+				"message": "Destroy everything",
+				"source": "tfe-api",
+				"status": "pending",
+				"status-timestamps": {}
+			},
+			"relationships": {
+				"configuration-version": {"data": {"id": "cv-123", "type": "configuration-versions"}},
+				"workspace": {"data": {"id": "ws-123", "type": "workspaces"}}
+			}
+		}}`))
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	relationship := func(name string) map[string]interface{} {
+		data := body["data"].(map[string]interface{})
+		relationships, _ := data["relationships"].(map[string]interface{})
+		rel, _ := relationships[name].(map[string]interface{})
+		return rel
+	}
+
+	t.Run("with a configuration version", func(t *testing.T) {
+		r, err := client.Runs.Create(ctx, RunCreateOptions{
+			AutoApply:            Bool(true),
+			IsDestroy:            Bool(true),
+			Message:              String("Destroy everything"),
+			ConfigurationVersion: &ConfigurationVersion{ID: "cv-123"},
+			Workspace:            &Workspace{ID: "ws-123"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "run-123", r.ID)
+		assert.True(t, r.AutoApply)
+		assert.True(t, r.IsDestroy)
+		assert.Equal(t, RunSourceAPI, r.Source)
+		assert.Equal(t, "cv-123", r.ConfigurationVersion.ID)
+		assert.Equal(t, "ws-123", r.Workspace.ID)
+
+		data := body["data"].(map[string]interface{})
+		assert.Equal(t, "runs", data["type"])
+		assert.Equal(t, map[string]interface{}{
+			"auto-apply": true,
+			"is-destroy": true,
+			"message":    "Destroy everything",
+		}, data["attributes"])
+
+		cv := relationship("configuration-version")["data"].(map[string]interface{})
+		assert.Equal(t, "configuration-versions", cv["type"])
+		assert.Equal(t, "cv-123", cv["id"])
+
+		ws := relationship("workspace")["data"].(map[string]interface{})
+		assert.Equal(t, "workspaces", ws["type"])
+		assert.Equal(t, "ws-123", ws["id"])
+	})
+
+	t.Run("without a configuration version", func(t *testing.T) {
+		_, err := client.Runs.Create(ctx, RunCreateOptions{
+			Workspace: &Workspace{ID: "ws-123"},
+		})
+		require.NoError(t, err)
+
+		data := body["data"].(map[string]interface{})
+		assert.NotContains(t, data["relationships"], "configuration-version")
+		assert.Contains(t, data["relationships"], "workspace")
+	})
+
+	cases := map[string]struct {
+		options    RunCreateOptions
+		attributes map[string]interface{}
+	}{
+		"without plan options": {
+			options:    RunCreateOptions{},
+			attributes: nil,
+		},
+		"with target and replace addresses": {
+			options: RunCreateOptions{
+				TargetAddrs:  []string{"module.network", "aws_instance.web"},
+				ReplaceAddrs: []string{"aws_instance.web"},
+			},
+			attributes: map[string]interface{}{
+				"target-addrs":  []interface{}{"module.network", "aws_instance.web"},
+				"replace-addrs": []interface{}{"aws_instance.web"},
+			},
+		},
+		"without a refresh": {
+			options: RunCreateOptions{Refresh: Bool(false)},
+			attributes: map[string]interface{}{
+				"refresh": false,
+			},
+		},
+		"with a refresh only": {
+			options: RunCreateOptions{RefreshOnly: Bool(true)},
+			attributes: map[string]interface{}{
+				"refresh-only": true,
+			},
+		},
+		"with a plan only": {
+			options: RunCreateOptions{PlanOnly: Bool(true)},
+			attributes: map[string]interface{}{
+				"plan-only": true,
+			},
+		},
+		"with a saved plan": {
+			options: RunCreateOptions{
+				Message:  String("Plan for the release"),
+				SavePlan: Bool(true),
+			},
+			attributes: map[string]interface{}{
+				"message":   "Plan for the release",
+				"save-plan": true,
+			},
+		},
+		"with variables": {
+			options: RunCreateOptions{
+				Variables: []*RunVariable{
+					{Key: "region", Value: HCLString("eu-west-1")},
+					{Key: "zones", Value: HCLList("eu-west-1a", "eu-west-1b")},
+					{Key: "count", Value: "3"},
+				},
+			},
+			attributes: map[string]interface{}{
+				"variables": []interface{}{
+					map[string]interface{}{"key": "region", "value": `"eu-west-1"`},
+					map[string]interface{}{"key": "zones", "value": `["eu-west-1a", "eu-west-1b"]`},
+					map[string]interface{}{"key": "count", "value": "3"},
+				},
+			},
+		},
+		"with an empty apply": {
+			options: RunCreateOptions{
+				AllowEmptyApply: Bool(true),
+				AutoApply:       Bool(true),
+			},
+			attributes: map[string]interface{}{
+				"allow-empty-apply": true,
+				"auto-apply":        true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.options.Workspace = &Workspace{ID: "ws-123"}
+
+			_, err := client.Runs.Create(ctx, tc.options)
+			require.NoError(t, err)
+
+			data := body["data"].(map[string]interface{})
+			attributes, _ := data["attributes"].(map[string]interface{})
+			if tc.attributes == nil {
+				assert.Empty(t, attributes)
+				return
+			}
+			assert.Equal(t, tc.attributes, attributes)
+		})
+	}
+}
+
 func TestRunsRead(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -120,9 +372,9 @@ func TestRunsRead(t *testing.T) {
 	})
 
 	t.Run("when the run does not exist", func(t *testing.T) {
-		r, err := client.Runs.Read(ctx, "nonexisting")
+		r, err := client.Runs.Read(ctx, "run-nonexisting")
 		assert.Nil(t, r)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid run ID", func(t *testing.T) {
@@ -132,6 +384,72 @@ func TestRunsRead(t *testing.T) {
 	})
 }
 
+func TestRunsReadWithOptionsFixture(t *testing.T) {
+	run, err := ioutil.ReadFile("test-fixtures/run/read-with-includes.json")
+	require.NoError(t, err)
+
+	var include string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		include = r.URL.Query().Get("include")
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(run)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with includes", func(t *testing.T) {
+		r, err := client.Runs.ReadWithOptions(ctx, "run-CZcmD7eagjhyX0vN", RunReadOptions{
+			Include: []RunIncludeOpt{
+				RunPlan,
+				RunApply,
+				RunCostEstimate,
+				RunCreatedBy,
+				RunConfigVer,
+				RunWorkspace,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "plan,apply,cost_estimate,created_by,configuration_version,workspace", include)
+
+		assert.Equal(t, RunApplied, r.Status)
+		assert.Equal(t, "Add the network module", r.Message)
+
+		require.NotNil(t, r.Plan)
+		assert.Equal(t, 3, r.Plan.ResourceAdditions)
+		assert.Equal(t, 1, r.Plan.ResourceChanges)
+		assert.Equal(t, 2, r.Plan.ResourceDestructions)
+
+		require.NotNil(t, r.Apply)
+		assert.Equal(t, ApplyFinished, r.Apply.Status)
+
+		require.NotNil(t, r.CostEstimate)
+		assert.Equal(t, "12.48", r.CostEstimate.DeltaMonthlyCost)
+
+		require.NotNil(t, r.CreatedBy)
+		assert.Equal(t, "admin", r.CreatedBy.Username)
+		assert.Contains(t, r.CreatedBy.AvatarURL, "gravatar.com")
+
+		require.NotNil(t, r.ConfigurationVersion)
+		assert.Equal(t, ConfigurationUploaded, r.ConfigurationVersion.Status)
+
+		require.NotNil(t, r.Workspace)
+		assert.Equal(t, "my-workspace", r.Workspace.Name)
+	})
+
+	t.Run("without includes", func(t *testing.T) {
+		_, err := client.Runs.Read(ctx, "run-CZcmD7eagjhyX0vN")
+		require.NoError(t, err)
+		assert.Empty(t, include)
+	})
+
+	t.Run("with invalid run ID", func(t *testing.T) {
+		r, err := client.Runs.ReadWithOptions(ctx, badIdentifier, RunReadOptions{})
+		assert.Nil(t, r)
+		assert.EqualError(t, err, "invalid value for run ID")
+	})
+}
+
 func TestRunsApply(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -145,8 +463,8 @@ func TestRunsApply(t *testing.T) {
 	})
 
 	t.Run("when the run does not exist", func(t *testing.T) {
-		err := client.Runs.Apply(ctx, "nonexisting", RunApplyOptions{})
-		assert.Equal(t, err, ErrResourceNotFound)
+		err := client.Runs.Apply(ctx, "run-nonexisting", RunApplyOptions{})
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid run ID", func(t *testing.T) {
@@ -175,8 +493,8 @@ func TestRunsCancel(t *testing.T) {
 	})
 
 	t.Run("when the run does not exist", func(t *testing.T) {
-		err := client.Runs.Cancel(ctx, "nonexisting", RunCancelOptions{})
-		assert.Equal(t, err, ErrResourceNotFound)
+		err := client.Runs.Cancel(ctx, "run-nonexisting", RunCancelOptions{})
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid run ID", func(t *testing.T) {
@@ -243,8 +561,8 @@ func TestRunsForceCancel(t *testing.T) {
 	})
 
 	t.Run("when the run does not exist", func(t *testing.T) {
-		err := client.Runs.ForceCancel(ctx, "nonexisting", RunForceCancelOptions{})
-		assert.Equal(t, err, ErrResourceNotFound)
+		err := client.Runs.ForceCancel(ctx, "run-nonexisting", RunForceCancelOptions{})
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid run ID", func(t *testing.T) {
@@ -253,6 +571,109 @@ func TestRunsForceCancel(t *testing.T) {
 	})
 }
 
+func TestRunsForceCancelConflicts(t *testing.T) {
+	var availableAt string
+	var readForbidden bool
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(409)
+			w.Write([]byte(`{"errors": [{"status": "409", "title": "conflict"}]}`))
+		case readForbidden:
+			w.WriteHeader(403)
+		default:
+			attributes := `{"status": "planning"}`
+			if availableAt != "" {
+				attributes = `{"status": "planning", "force-cancel-available-at": "` + availableAt + `"}`
+			}
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write([]byte(`{"data": {"id": "run-123", "type": "runs", "attributes": ` + attributes + `}}`))
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("before a normal cancel", func(t *testing.T) {
+		availableAt = ""
+
+		err := client.Runs.ForceCancel(ctx, "run-123", RunForceCancelOptions{})
+		assert.True(t, errors.Is(err, ErrRunNotForceCancelable))
+		assert.EqualError(t, err, "run is not force-cancelable: the run must be canceled first")
+	})
+
+	t.Run("before the cool-off period has passed", func(t *testing.T) {
+		at := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		availableAt = at.Format(time.RFC3339)
+
+		err := client.Runs.ForceCancel(ctx, "run-123", RunForceCancelOptions{})
+		var notCancelable *RunNotForceCancelableError
+		require.True(t, errors.As(err, &notCancelable))
+		assert.True(t, at.Equal(notCancelable.AvailableAt))
+		assert.EqualError(t, err, "run is not force-cancelable until "+availableAt)
+	})
+
+	t.Run("after the cool-off period has passed", func(t *testing.T) {
+		availableAt = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+		err := client.Runs.ForceCancel(ctx, "run-123", RunForceCancelOptions{})
+		assert.True(t, errors.Is(err, ErrRunNotForceCancelable), err)
+	})
+
+	t.Run("when reading the run fails", func(t *testing.T) {
+		readForbidden = true
+		defer func() { readForbidden = false }()
+
+		err := client.Runs.ForceCancel(ctx, "run-123", RunForceCancelOptions{})
+		assert.True(t, errors.Is(err, ErrRunNotForceCancelable), err)
+		assert.False(t, errors.Is(err, ErrForbidden), err)
+		assert.Contains(t, err.Error(), "(reading run: forbidden)")
+	})
+}
+
+func TestRunsForceExecuteConflicts(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+
+		switch r.URL.Path {
+		case "/api/v2/runs/run-pending/actions/force-execute":
+			w.WriteHeader(202)
+		case "/api/v2/runs/run-forbidden/actions/force-execute":
+			w.WriteHeader(403)
+			w.Write([]byte(`{"errors": [{"status": "403", "title": "forbidden"}]}`))
+		case "/api/v2/runs/run-planned/actions/force-execute":
+			w.WriteHeader(409)
+			w.Write([]byte(`{"errors": [{"status": "409", "title": "conflict", "detail": "Run is not pending"}]}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when the run is pending", func(t *testing.T) {
+		err := client.Runs.ForceExecute(ctx, "run-pending")
+		assert.NoError(t, err)
+	})
+
+	t.Run("without admin access", func(t *testing.T) {
+		err := client.Runs.ForceExecute(ctx, "run-forbidden")
+		assert.True(t, errors.Is(err, ErrForbidden), err)
+	})
+
+	t.Run("when the run is not pending", func(t *testing.T) {
+		err := client.Runs.ForceExecute(ctx, "run-planned")
+		assert.True(t, errors.Is(err, ErrRunNotForceExecutable), err)
+	})
+
+	t.Run("with invalid run ID", func(t *testing.T) {
+		err := client.Runs.ForceExecute(ctx, badIdentifier)
+		assert.EqualError(t, err, "invalid value for run ID")
+	})
+}
+
 func TestRunsDiscard(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -266,8 +687,8 @@ func TestRunsDiscard(t *testing.T) {
 	})
 
 	t.Run("when the run does not exist", func(t *testing.T) {
-		err := client.Runs.Discard(ctx, "nonexisting", RunDiscardOptions{})
-		assert.Equal(t, err, ErrResourceNotFound)
+		err := client.Runs.Discard(ctx, "run-nonexisting", RunDiscardOptions{})
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid run ID", func(t *testing.T) {
@@ -275,3 +696,207 @@ func TestRunsDiscard(t *testing.T) {
 		assert.EqualError(t, err, "invalid value for run ID")
 	})
 }
+
+func TestRunsInvalidRunID(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	calls := map[string]func(runID string) error{
+		"Read": func(runID string) error {
+			_, err := client.Runs.Read(ctx, runID)
+			return err
+		},
+		"Apply": func(runID string) error {
+			return client.Runs.Apply(ctx, runID, RunApplyOptions{})
+		},
+		"Cancel": func(runID string) error {
+			return client.Runs.Cancel(ctx, runID, RunCancelOptions{})
+		},
+		"ForceCancel": func(runID string) error {
+			return client.Runs.ForceCancel(ctx, runID, RunForceCancelOptions{})
+		},
+		"ForceExecute": func(runID string) error {
+			return client.Runs.ForceExecute(ctx, runID)
+		},
+		"Discard": func(runID string) error {
+			return client.Runs.Discard(ctx, runID, RunDiscardOptions{})
+		},
+		"ListVariables": func(runID string) error {
+			_, err := client.Runs.ListVariables(ctx, runID, RunEffectiveVariableListOptions{})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		for _, runID := range []string{"", "ws-abc", badIdentifier} {
+			t.Run(name+" with "+runID, func(t *testing.T) {
+				assert.EqualError(t, call(runID), "invalid value for run ID")
+			})
+		}
+	}
+}
+
+func TestRunsHCLValues(t *testing.T) {
+	values := map[string]string{
+		"eu-west-1":            `"eu-west-1"`,
+		"":                     `""`,
+		`say "hi"`:             `"say \"hi\""`,
+		`C:\temp`:              `"C:\\temp"`,
+		"line\nbreak\t":        `"line\nbreak\t"`,
+		"${var.region}":        `"$${var.region}"`,
+		"%{ if true }%{ end }": `"%%{ if true }%%{ end }"`,
+		"costs $5 or 5%":       `"costs $5 or 5%"`,
+	}
+	for v, expected := range values {
+		assert.Equal(t, expected, HCLString(v), "value %q", v)
+	}
+
+	assert.Equal(t, `[]`, HCLList())
+	assert.Equal(t, `["a"]`, HCLList("a"))
+	assert.Equal(t, `["a", "b \"c\""]`, HCLList("a", `b "c"`))
+}
+
+func TestRunsCreateOptionsValid(t *testing.T) {
+	t.Run("with a workspace", func(t *testing.T) {
+		options := RunCreateOptions{
+			Workspace: &Workspace{ID: "ws-123"},
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("without a workspace", func(t *testing.T) {
+		options := RunCreateOptions{}
+
+		err := options.valid()
+		assert.EqualError(t, err, "workspace is required")
+	})
+
+	t.Run("with a workspace without an ID", func(t *testing.T) {
+		options := RunCreateOptions{
+			Workspace: &Workspace{Name: "my-workspace"},
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for workspace ID")
+	})
+
+	t.Run("with a refresh only destroy", func(t *testing.T) {
+		options := RunCreateOptions{
+			IsDestroy:   Bool(true),
+			RefreshOnly: Bool(true),
+			Workspace:   &Workspace{ID: "ws-123"},
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "only one of refresh only or is destroy can be set")
+	})
+
+	t.Run("with a refresh only plan", func(t *testing.T) {
+		options := RunCreateOptions{
+			IsDestroy:   Bool(false),
+			RefreshOnly: Bool(true),
+			Workspace:   &Workspace{ID: "ws-123"},
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with a variable without a key", func(t *testing.T) {
+		options := RunCreateOptions{
+			Variables: []*RunVariable{{Value: HCLString("eu-west-1")}},
+			Workspace: &Workspace{ID: "ws-123"},
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "variable key is required")
+	})
+
+	t.Run("with a nil variable", func(t *testing.T) {
+		options := RunCreateOptions{
+			Variables: []*RunVariable{nil},
+			Workspace: &Workspace{ID: "ws-123"},
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "variables cannot contain nil values")
+	})
+
+	t.Run("with a configuration version without an ID", func(t *testing.T) {
+		options := RunCreateOptions{
+			ConfigurationVersion: &ConfigurationVersion{},
+			Workspace:            &Workspace{ID: "ws-123"},
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for configuration version ID")
+	})
+}
+
+func TestRunsListVariablesFixture(t *testing.T) {
+	var query string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/runs/run-CZcmD7eagjhyX0vN/run-variables":
+			query = r.URL.RawQuery
+			data, err := ioutil.ReadFile("test-fixtures/run/variables.json")
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(data)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when listing the variables", func(t *testing.T) {
+		vl, err := client.Runs.ListVariables(ctx, "run-CZcmD7eagjhyX0vN", RunEffectiveVariableListOptions{
+			ListOptions: ListOptions{PageSize: 20},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "page%5Bsize%5D=20", query)
+		assert.Equal(t, 5, vl.TotalCount)
+		require.Len(t, vl.Items, 5)
+
+		// The run variable takes precedence over the same key set on the
+		// workspace and by a variable set.
+		assert.Equal(t, &RunEffectiveVariable{
+			ID:       "runvar-E8Xjv4VnTE3oRCqL",
+			Key:      "region",
+			Value:    "us-west-2",
+			Category: CategoryTerraform,
+			Source:   RunVariableSourceRun,
+		}, vl.Items[0])
+		assert.Equal(t, RunVariableSourceWorkspace, vl.Items[1].Source)
+		assert.True(t, vl.Items[1].Overridden)
+		assert.Equal(t, RunVariableSourceVariableSet, vl.Items[2].Source)
+		assert.True(t, vl.Items[2].Overridden)
+
+		// The value of a sensitive variable is redacted.
+		assert.Equal(t, CategoryEnv, vl.Items[3].Category)
+		assert.True(t, vl.Items[3].Sensitive)
+		assert.Empty(t, vl.Items[3].Value)
+
+		assert.True(t, vl.Items[4].HCL)
+		assert.Equal(t, `{ team = "networking" }`, vl.Items[4].Value)
+	})
+
+	t.Run("when the run does not exist", func(t *testing.T) {
+		vl, err := client.Runs.ListVariables(ctx, "run-nonexisting", RunEffectiveVariableListOptions{})
+		assert.Nil(t, vl)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with an invalid run ID", func(t *testing.T) {
+		vl, err := client.Runs.ListVariables(ctx, badIdentifier, RunEffectiveVariableListOptions{})
+		assert.Nil(t, vl)
+		assert.EqualError(t, err, "invalid value for run ID")
+	})
+}
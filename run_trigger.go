@@ -73,7 +73,7 @@ func (o RunTriggerListOptions) valid() error {
 
 // List all the run triggers associated with a workspace.
 func (s *runTriggers) List(ctx context.Context, workspaceID string, options RunTriggerListOptions) (*RunTriggerList, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -115,7 +115,7 @@ func (o RunTriggerCreateOptions) valid() error {
 
 // Creates a run trigger with the given options.
 func (s *runTriggers) Create(ctx context.Context, workspaceID string, options RunTriggerCreateOptions) (*RunTrigger, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 	if err := options.valid(); err != nil {
@@ -142,7 +142,7 @@ func (s *runTriggers) Create(ctx context.Context, workspaceID string, options Ru
 
 // Read a run trigger by its ID.
 func (s *runTriggers) Read(ctx context.Context, runTriggerID string) (*RunTrigger, error) {
-	if !validStringID(&runTriggerID) {
+	if !validResourceID(&runTriggerID, "rt-") {
 		return nil, errors.New("invalid value for run trigger ID")
 	}
 
@@ -163,7 +163,7 @@ func (s *runTriggers) Read(ctx context.Context, runTriggerID string) (*RunTrigge
 
 // Delete a run trigger by its ID.
 func (s *runTriggers) Delete(ctx context.Context, runTriggerID string) error {
-	if !validStringID(&runTriggerID) {
+	if !validResourceID(&runTriggerID, "rt-") {
 		return errors.New("invalid value for run trigger ID")
 	}
 
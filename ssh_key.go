@@ -127,7 +127,7 @@ func (s *sshKeys) Create(ctx context.Context, organization string, options SSHKe
 
 // Read an SSH key by its ID.
 func (s *sshKeys) Read(ctx context.Context, sshKeyID string) (*SSHKey, error) {
-	if !validStringID(&sshKeyID) {
+	if !validResourceID(&sshKeyID, "sshkey-") {
 		return nil, errors.New("invalid value for SSH key ID")
 	}
 
@@ -158,12 +158,26 @@ type SSHKeyUpdateOptions struct {
 	Value *string `jsonapi:"attr,value,omitempty"`
 }
 
+func (o SSHKeyUpdateOptions) valid() error {
+	if o.Name != nil && !validString(o.Name) {
+		return errors.New("invalid value for name")
+	}
+	if o.Value != nil && !validString(o.Value) {
+		return errors.New("invalid value for value")
+	}
+	return nil
+}
+
 // Update an SSH key by its ID.
 func (s *sshKeys) Update(ctx context.Context, sshKeyID string, options SSHKeyUpdateOptions) (*SSHKey, error) {
-	if !validStringID(&sshKeyID) {
+	if !validResourceID(&sshKeyID, "sshkey-") {
 		return nil, errors.New("invalid value for SSH key ID")
 	}
 
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
+
 	// Make sure we don't send a user provided ID.
 	options.ID = ""
 
@@ -184,7 +198,7 @@ func (s *sshKeys) Update(ctx context.Context, sshKeyID string, options SSHKeyUpd
 
 // Delete an SSH key by its ID.
 func (s *sshKeys) Delete(ctx context.Context, sshKeyID string) error {
-	if !validStringID(&sshKeyID) {
+	if !validResourceID(&sshKeyID, "sshkey-") {
 		return errors.New("invalid value for SSH key ID")
 	}
 
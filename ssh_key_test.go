@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -123,9 +124,9 @@ func TestSSHKeysRead(t *testing.T) {
 	})
 
 	t.Run("when the SSH key does not exist", func(t *testing.T) {
-		k, err := client.SSHKeys.Read(ctx, "nonexisting")
+		k, err := client.SSHKeys.Read(ctx, "sshkey-nonexisting")
 		assert.Nil(t, k)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid SSH key ID", func(t *testing.T) {
@@ -204,12 +205,12 @@ func TestSSHKeysDelete(t *testing.T) {
 
 		// Try loading the SSH key - it should fail.
 		_, err = client.SSHKeys.Read(ctx, kTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the SSH key does not exist", func(t *testing.T) {
 		err := client.SSHKeys.Delete(ctx, kTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the SSH key ID is invalid", func(t *testing.T) {
@@ -217,3 +218,31 @@ func TestSSHKeysDelete(t *testing.T) {
 		assert.EqualError(t, err, "invalid value for SSH key ID")
 	})
 }
+
+func TestSSHKeysUpdateOptionsValid(t *testing.T) {
+	t.Run("without any options", func(t *testing.T) {
+		options := SSHKeyUpdateOptions{}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with an empty name", func(t *testing.T) {
+		options := SSHKeyUpdateOptions{
+			Name: String(""),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for name")
+	})
+
+	t.Run("with an empty value", func(t *testing.T) {
+		options := SSHKeyUpdateOptions{
+			Name:  String("my-key"),
+			Value: String(""),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for value")
+	})
+}
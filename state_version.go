@@ -3,10 +3,18 @@ package tfe
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/url"
+	"strings"
 	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/svanharmelen/jsonapi"
 )
 
 // Compile-time proof of interface implementation.
@@ -27,11 +35,49 @@ type StateVersions interface {
 	// Read a state version by its ID.
 	Read(ctx context.Context, svID string) (*StateVersion, error)
 
+	// ReadWithOptions reads a state version by its ID using the given
+	// options.
+	ReadWithOptions(ctx context.Context, svID string, options StateVersionReadOptions) (*StateVersion, error)
+
+	// ListOutputs lists the outputs of a state version.
+	ListOutputs(ctx context.Context, svID string, options StateVersionOutputsListOptions) (*StateVersionOutputsList, error)
+
 	// Current reads the latest available state from the given workspace.
 	Current(ctx context.Context, workspaceID string) (*StateVersion, error)
 
+	// CurrentWithOptions reads the latest available state from the given
+	// workspace using the given options.
+	CurrentWithOptions(ctx context.Context, workspaceID string, options StateVersionCurrentOptions) (*StateVersion, error)
+
 	// Download retrieves the actual stored state of a state version
 	Download(ctx context.Context, url string) ([]byte, error)
+
+	// DownloadStream retrieves the actual stored state of a state version
+	// as a stream.
+	DownloadStream(ctx context.Context, url string) (io.ReadCloser, error)
+
+	// DownloadCurrent retrieves the actual stored state of the current
+	// state version of the given workspace.
+	DownloadCurrent(ctx context.Context, workspaceID string) ([]byte, error)
+
+	// Upload uploads the state of a state version to its upload URL.
+	Upload(ctx context.Context, uploadURL string, r io.Reader) error
+
+	// CreateAndUpload creates a new state version for the given workspace
+	// and uploads its state, waiting until the state version is finalized.
+	CreateAndUpload(ctx context.Context, workspaceID string, options StateVersionUploadOptions) (*StateVersion, error)
+
+	// SoftDeleteBackingData soft deletes the stored state of a state
+	// version, so it can still be restored.
+	SoftDeleteBackingData(ctx context.Context, svID string) error
+
+	// RestoreBackingData restores the soft deleted stored state of a state
+	// version.
+	RestoreBackingData(ctx context.Context, svID string) error
+
+	// PermanentlyDeleteBackingData permanently deletes the soft deleted
+	// stored state of a state version.
+	PermanentlyDeleteBackingData(ctx context.Context, svID string) error
 }
 
 // stateVersions implements StateVersions.
@@ -45,17 +91,35 @@ type StateVersionList struct {
 	Items []*StateVersion
 }
 
+// StateVersionStatus represents a state version status.
+type StateVersionStatus string
+
+// List all available state version statuses.
+const (
+	StateVersionDiscarded StateVersionStatus = "discarded"
+	StateVersionFinalized StateVersionStatus = "finalized"
+	StateVersionPending   StateVersionStatus = "pending"
+)
+
 // StateVersion represents a Terraform Enterprise state version.
 type StateVersion struct {
-	ID           string    `jsonapi:"primary,state-versions"`
-	CreatedAt    time.Time `jsonapi:"attr,created-at,iso8601"`
-	DownloadURL  string    `jsonapi:"attr,hosted-state-download-url"`
-	Serial       int64     `jsonapi:"attr,serial"`
-	VCSCommitSHA string    `jsonapi:"attr,vcs-commit-sha"`
-	VCSCommitURL string    `jsonapi:"attr,vcs-commit-url"`
+	ID                     string             `jsonapi:"primary,state-versions"`
+	BackingDataSoftDeleted bool               `jsonapi:"attr,backing-data-soft-deleted"`
+	BillableRUMCount       int                `jsonapi:"attr,billable-rum-count"`
+	CreatedAt              time.Time          `jsonapi:"attr,created-at,iso8601"`
+	DownloadURL            string             `jsonapi:"attr,hosted-state-download-url"`
+	JSONDownloadURL        string             `jsonapi:"attr,hosted-json-state-download-url"`
+	JSONUploadURL          string             `jsonapi:"attr,hosted-json-state-upload-url"`
+	ResourcesProcessed     bool               `jsonapi:"attr,resources-processed"`
+	Serial                 int64              `jsonapi:"attr,serial"`
+	Status                 StateVersionStatus `jsonapi:"attr,status"`
+	UploadURL              string             `jsonapi:"attr,hosted-state-upload-url"`
+	VCSCommitSHA           string             `jsonapi:"attr,vcs-commit-sha"`
+	VCSCommitURL           string             `jsonapi:"attr,vcs-commit-url"`
 
 	// Relations
-	Run *Run `jsonapi:"relation,run"`
+	Outputs []*StateVersionOutput `jsonapi:"relation,outputs"`
+	Run     *Run                  `jsonapi:"relation,run"`
 }
 
 // StateVersionListOptions represents the options for listing state versions.
@@ -104,38 +168,67 @@ type StateVersionCreateOptions struct {
 	Lineage *string `jsonapi:"attr,lineage,omitempty"`
 
 	// The MD5 hash of the state version.
-	MD5 *string `jsonapi:"attr,md5"`
+	MD5 *string `jsonapi:"attr,md5,omitempty"`
 
 	// The serial of the state.
-	Serial *int64 `jsonapi:"attr,serial"`
+	Serial *int64 `jsonapi:"attr,serial,omitempty"`
 
-	// The base64 encoded state.
-	State *string `jsonapi:"attr,state"`
+	// The base64 encoded state. When omitted, servers that support it
+	// return upload URLs to upload the state to separately.
+	State *string `jsonapi:"attr,state,omitempty"`
+
+	// The base64 encoded JSON state, in the format of terraform show -json.
+	// It is optional, but without it the state version has no JSON state.
+	JSONState *string `jsonapi:"attr,json-state,omitempty"`
 
 	// Force can be set to skip certain validations. Wrong use
 	// of this flag can cause data loss, so USE WITH CAUTION!
-	Force *bool `jsonapi:"attr,force"`
+	Force *bool `jsonapi:"attr,force,omitempty"`
 
 	// Specifies the run to associate the state with.
 	Run *Run `jsonapi:"relation,run,omitempty"`
+
+	// Specifies a prior state version to roll back to. The new state
+	// version is a copy of the given state version, so the state, the MD5
+	// hash, the serial and the lineage must not be set.
+	RollbackStateVersion *StateVersion `jsonapi:"relation,rollback-state-version,omitempty"`
 }
 
 func (o StateVersionCreateOptions) valid() error {
+	if o.RollbackStateVersion != nil {
+		if !validResourceID(&o.RollbackStateVersion.ID, "sv-") {
+			return errors.New("invalid value for rollback state version ID")
+		}
+		if o.State != nil || o.JSONState != nil {
+			return errors.New("only one of rollback state version or state can be set")
+		}
+		if o.MD5 != nil || o.Serial != nil || o.Lineage != nil {
+			return errors.New("MD5, serial and lineage cannot be set when rolling back")
+		}
+		return nil
+	}
 	if !validString(o.MD5) {
 		return errors.New("MD5 is required")
 	}
 	if o.Serial == nil {
 		return errors.New("serial is required")
 	}
-	if !validString(o.State) {
-		return errors.New("state is required")
+	if o.State != nil && !validString(o.State) {
+		return errors.New("invalid value for state")
 	}
 	return nil
 }
 
-// Create a new state version for the given workspace.
+// Create a new state version for the given workspace. The workspace must be
+// locked by the caller, otherwise an error wrapping ErrWorkspaceNotLocked
+// is returned. To roll back to a prior state version, set only
+// RollbackStateVersion.
+//
+// When the state is omitted, servers that support it return a pending state
+// version with upload URLs, to which the state is uploaded using Upload.
+// Older servers require the state to be included.
 func (s *stateVersions) Create(ctx context.Context, workspaceID string, options StateVersionCreateOptions) (*StateVersion, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 	if err := options.valid(); err != nil {
@@ -162,40 +255,84 @@ func (s *stateVersions) Create(ctx context.Context, workspaceID string, options
 
 // Read a state version by its ID.
 func (s *stateVersions) Read(ctx context.Context, svID string) (*StateVersion, error) {
-	if !validStringID(&svID) {
+	return s.ReadWithOptions(ctx, svID, StateVersionReadOptions{})
+}
+
+// StateVersionReadOptions represents the options for reading a state
+// version.
+type StateVersionReadOptions struct {
+	// A list of relations to include.
+	Include []StateVersionIncludeOpt `url:"include,omitempty,comma"`
+}
+
+// ReadWithOptions reads a state version by its ID using the given options.
+func (s *stateVersions) ReadWithOptions(ctx context.Context, svID string, options StateVersionReadOptions) (*StateVersion, error) {
+	if !validResourceID(&svID, "sv-") {
 		return nil, errors.New("invalid value for state version ID")
 	}
 
 	u := fmt.Sprintf("state-versions/%s", url.QueryEscape(svID))
-	req, err := s.client.newRequest("GET", u, nil)
+	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
 		return nil, err
 	}
 
-	sv := &StateVersion{}
-	err = s.client.do(ctx, req, sv)
-	if err != nil {
-		return nil, err
-	}
-
-	return sv, nil
+	return s.doStateVersion(ctx, req)
 }
 
-// Current reads the latest available state from the given workspace.
+// Current reads the latest available state from the given workspace. If
+// the workspace has never stored any state, an error wrapping
+// ErrResourceNotFound is returned.
 func (s *stateVersions) Current(ctx context.Context, workspaceID string) (*StateVersion, error) {
-	if !validStringID(&workspaceID) {
+	return s.CurrentWithOptions(ctx, workspaceID, StateVersionCurrentOptions{})
+}
+
+// StateVersionIncludeOpt represents the available options for include query
+// params.
+type StateVersionIncludeOpt string
+
+// List all available state version include options.
+const (
+	SVOutputs StateVersionIncludeOpt = "outputs"
+)
+
+// StateVersionCurrentOptions represents the options for reading the current
+// state version of a workspace.
+type StateVersionCurrentOptions struct {
+	// A list of relations to include.
+	Include []StateVersionIncludeOpt `url:"include,omitempty,comma"`
+}
+
+// CurrentWithOptions reads the latest available state from the given
+// workspace using the given options. If the workspace has never stored any
+// state, an error wrapping ErrResourceNotFound is returned.
+func (s *stateVersions) CurrentWithOptions(ctx context.Context, workspaceID string, options StateVersionCurrentOptions) (*StateVersion, error) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
 	u := fmt.Sprintf("workspaces/%s/current-state-version", url.QueryEscape(workspaceID))
-	req, err := s.client.newRequest("GET", u, nil)
+	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.doStateVersion(ctx, req)
+}
+
+// doStateVersion sends the request and decodes the returned state version,
+// including the values of any included outputs.
+func (s *stateVersions) doStateVersion(ctx context.Context, req *retryablehttp.Request) (*StateVersion, error) {
+	body := &bytes.Buffer{}
+	if err := s.client.do(ctx, req, body); err != nil {
+		return nil, err
+	}
+
 	sv := &StateVersion{}
-	err = s.client.do(ctx, req, sv)
-	if err != nil {
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(body.Bytes()), sv); err != nil {
+		return nil, err
+	}
+	if err := decodeOutputValues(body.Bytes(), sv.Outputs); err != nil {
 		return nil, err
 	}
 
@@ -204,17 +341,249 @@ func (s *stateVersions) Current(ctx context.Context, workspaceID string) (*State
 
 // Download retrieves the actual stored state of a state version
 func (s *stateVersions) Download(ctx context.Context, url string) ([]byte, error) {
-	req, err := s.client.newRequest("GET", url, nil)
+	body, err := s.client.doDownload(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return ioutil.ReadAll(body)
+}
+
+// DownloadStream retrieves the actual stored state of a state version as a
+// stream, without reading it into memory first. The state is returned as
+// is, without parsing it. The caller is responsible for closing the
+// returned reader.
+func (s *stateVersions) DownloadStream(ctx context.Context, url string) (io.ReadCloser, error) {
+	if url == "" {
+		return nil, errors.New("invalid value for download URL")
+	}
+	return s.client.doDownload(ctx, url)
+}
+
+// DownloadCurrent retrieves the actual stored state of the current state
+// version of the given workspace. If the workspace has never stored any
+// state, an error wrapping ErrResourceNotFound is returned.
+func (s *stateVersions) DownloadCurrent(ctx context.Context, workspaceID string) ([]byte, error) {
+	sv, err := s.Current(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Download(ctx, sv.DownloadURL)
+}
+
+// Upload uploads the state of a state version to its upload URL, which is
+// either the UploadURL or, for the JSON state, the JSONUploadURL of the
+// state version. The state is uploaded as is. The API token is never sent
+// to the upload URL.
+func (s *stateVersions) Upload(ctx context.Context, uploadURL string, r io.Reader) error {
+	if uploadURL == "" {
+		return errors.New("invalid value for upload URL")
+	}
+	if r == nil {
+		return errors.New("state is required")
+	}
+	return s.client.doUpload(ctx, uploadURL, r)
+}
+
+// StateVersionUploadOptions represents the options for creating a state
+// version and uploading its state.
+type StateVersionUploadOptions struct {
+	// The options used to create the state version. The state and the JSON
+	// state must not be set, and the MD5 hash is calculated from RawState
+	// if it is not set.
+	StateVersionCreateOptions
+
+	// The raw state.
+	RawState []byte
+
+	// The raw JSON state, in the format of terraform show -json. It is
+	// optional, but without it the state version has no JSON state.
+	RawJSONState []byte
+}
+
+func (o StateVersionUploadOptions) valid() error {
+	if len(o.RawState) == 0 {
+		return errors.New("raw state is required")
+	}
+	if o.State != nil || o.JSONState != nil {
+		return errors.New("state and JSON state cannot be set, use raw state and raw JSON state instead")
+	}
+	if o.RollbackStateVersion != nil {
+		return errors.New("rollback state version cannot be set when uploading state")
+	}
+	if o.Serial == nil {
+		return errors.New("serial is required")
+	}
+	return nil
+}
+
+// CreateAndUpload creates a new state version for the given workspace and
+// uploads its state to the upload URLs returned by the server, waiting
+// until the state version is finalized. If the state version is discarded or
+// not finalized within a few minutes, a *StateVersionNotFinalizedError is
+// returned. If the server does not support
+// uploading the state separately, which is detected by the server rejecting
+// a state version because the state is missing, the state is included when
+// creating the state version instead. If RawJSONState is set but the server
+// does not return a JSON state upload URL, an error is returned before any
+// state is uploaded. Like Create, the workspace must be locked.
+func (s *stateVersions) CreateAndUpload(ctx context.Context, workspaceID string, options StateVersionUploadOptions) (*StateVersion, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
+
+	createOptions := options.StateVersionCreateOptions
+	if createOptions.MD5 == nil {
+		createOptions.MD5 = String(fmt.Sprintf("%x", md5.Sum(options.RawState)))
+	}
+
+	sv, err := s.Create(ctx, workspaceID, createOptions)
 	if err != nil {
+		if !stateRequired(err) {
+			return nil, err
+		}
+
+		// The server requires the state to be included.
+		createOptions.State = String(base64.StdEncoding.EncodeToString(options.RawState))
+		if len(options.RawJSONState) > 0 {
+			createOptions.JSONState = String(base64.StdEncoding.EncodeToString(options.RawJSONState))
+		}
+		return s.Create(ctx, workspaceID, createOptions)
+	}
+
+	if sv.UploadURL == "" {
+		return nil, fmt.Errorf("state version %s was created without an upload URL", sv.ID)
+	}
+	if len(options.RawJSONState) > 0 && sv.JSONUploadURL == "" {
+		return nil, fmt.Errorf("state version %s was created without a JSON state upload URL", sv.ID)
+	}
+	if err := s.Upload(ctx, sv.UploadURL, bytes.NewReader(options.RawState)); err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
+	if len(options.RawJSONState) > 0 {
+		if err := s.Upload(ctx, sv.JSONUploadURL, bytes.NewReader(options.RawJSONState)); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.waitForFinalized(ctx, sv.ID)
+}
+
+// stateRequired reports if err is the 422 response of a server that requires
+// the state to be included when creating a state version.
+func stateRequired(err error) bool {
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.StatusCode != 422 {
+		return false
+	}
+
+	for _, e := range errResp.Errors {
+		if e.Source != nil && e.Source.Pointer == "/data/attributes/state" {
+			return true
+		}
+		if strings.Contains(strings.ToLower(e.Detail), "state can't be blank") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maxFinalizedPolls is the number of times waitForFinalized reads a state
+// version before giving up, which waits a little over four minutes.
+var maxFinalizedPolls = 128
+
+// waitForFinalized polls the state version until the uploaded state is
+// processed. A *StateVersionNotFinalizedError is returned if the state
+// version was discarded or is still not finalized after maxFinalizedPolls.
+func (s *stateVersions) waitForFinalized(ctx context.Context, svID string) (*StateVersion, error) {
+	for i := 0; ; i++ {
+		sv, err := s.Read(ctx, svID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case sv.Status == StateVersionFinalized:
+			return sv, nil
+		case sv.Status == StateVersionDiscarded, i+1 >= maxFinalizedPolls:
+			return nil, &StateVersionNotFinalizedError{ID: sv.ID, Status: sv.Status}
+		}
+
+		timer := time.NewTimer(backoff(500, 2000, i))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// StateVersionOutputsList represents a list of state version outputs.
+type StateVersionOutputsList struct {
+	*Pagination
+	Items []*StateVersionOutput
+}
 
-	var buf bytes.Buffer
-	err = s.client.do(ctx, req, &buf)
+// StateVersionOutputsListOptions represents the options for listing the
+// outputs of a state version.
+type StateVersionOutputsListOptions struct {
+	ListOptions
+}
+
+// ListOutputs lists the outputs of a state version. The values of sensitive
+// outputs are not included, so their Value is nil.
+func (s *stateVersions) ListOutputs(ctx context.Context, svID string, options StateVersionOutputsListOptions) (*StateVersionOutputsList, error) {
+	if !validResourceID(&svID, "sv-") {
+		return nil, errors.New("invalid value for state version ID")
+	}
+
+	u := fmt.Sprintf("state-versions/%s/outputs", url.QueryEscape(svID))
+	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	return s.client.doOutputsList(ctx, req)
+}
+
+// SoftDeleteBackingData soft deletes the stored state of a state version.
+// Soft deleted state can be restored until it is permanently deleted.
+func (s *stateVersions) SoftDeleteBackingData(ctx context.Context, svID string) error {
+	return s.backingDataAction(ctx, svID, "soft_delete_backing_data")
+}
+
+// RestoreBackingData restores the soft deleted stored state of a state
+// version.
+func (s *stateVersions) RestoreBackingData(ctx context.Context, svID string) error {
+	return s.backingDataAction(ctx, svID, "restore_backing_data")
+}
+
+// PermanentlyDeleteBackingData permanently deletes the soft deleted stored
+// state of a state version. This cannot be undone.
+func (s *stateVersions) PermanentlyDeleteBackingData(ctx context.Context, svID string) error {
+	return s.backingDataAction(ctx, svID, "permanently_delete_backing_data")
+}
+
+// backingDataAction sends the given backing data action for a state version.
+// Servers that do not support the actions respond with a 404, which cannot be
+// told apart from a missing state version, so the error returned for a 404
+// matches both ErrResourceNotFound and ErrStateVersionBackingDataNotSupported.
+func (s *stateVersions) backingDataAction(ctx context.Context, svID, action string) error {
+	if !validResourceID(&svID, "sv-") {
+		return errors.New("invalid value for state version ID")
+	}
+
+	u := fmt.Sprintf("state-versions/%s/actions/%s", url.QueryEscape(svID), action)
+	req, err := s.client.newRequest("POST", u, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.do(ctx, req, nil)
 }
@@ -0,0 +1,227 @@
+package tfe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/svanharmelen/jsonapi"
+)
+
+// Compile-time proof of interface implementation.
+var _ StateVersionOutputs = (*stateVersionOutputs)(nil)
+
+// StateVersionOutputs describes all the state version output related
+// methods that the Terraform Enterprise API supports.
+//
+// TFE API docs:
+// https://www.terraform.io/docs/enterprise/api/state-version-outputs.html
+type StateVersionOutputs interface {
+	// Read a state version output by its ID.
+	Read(ctx context.Context, outputID string) (*StateVersionOutput, error)
+
+	// ReadCurrent lists the outputs of the current state version of the
+	// given workspace. The values of sensitive outputs are not included.
+	ReadCurrent(ctx context.Context, workspaceID string, options StateVersionOutputsListOptions) (*StateVersionOutputsList, error)
+}
+
+// stateVersionOutputs implements StateVersionOutputs.
+type stateVersionOutputs struct {
+	client *Client
+}
+
+// StateVersionOutput represents an output of a Terraform Enterprise state
+// version. The value of a sensitive output is only included when the output
+// is read by its ID, by users that are allowed to read it. Outputs that are
+// listed, included or read as the current outputs of a workspace never
+// include it.
+type StateVersionOutput struct {
+	ID        string `jsonapi:"primary,state-version-outputs"`
+	Name      string `jsonapi:"attr,name"`
+	Sensitive bool   `jsonapi:"attr,sensitive"`
+	Type      string `jsonapi:"attr,type"`
+
+	// The value of the output, decoded as JSON. Objects are decoded as
+	// map[string]interface{}, lists and tuples as []interface{} and
+	// numbers as json.Number, so large and fractional numbers keep their
+	// exact value. The value of a sensitive output is nil when it is
+	// redacted, which is distinct from an empty string value. As the
+	// JSONAPI decoder does not support values of any type, it is decoded
+	// separately by decodeOutputValues. Use DecodeValue to decode it into
+	// a value of a specific type.
+	Value interface{}
+
+	// The detailed type of the output, as the JSON encoding of its
+	// Terraform type constraint, e.g. "string" or ["list", "number"].
+	DetailedType interface{}
+}
+
+// DecodeValue decodes the value of the output into the value pointed to by
+// v, using the same rules as json.Unmarshal. An error is returned if the
+// output is sensitive and its value is not included.
+func (o *StateVersionOutput) DecodeValue(v interface{}) error {
+	if o.Sensitive && o.Value == nil {
+		return fmt.Errorf("value of sensitive output %q is not included", o.Name)
+	}
+
+	data, err := json.Marshal(o.Value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Read a state version output by its ID. Unlike the outputs that are listed
+// or included with a state version, the value of a sensitive output is
+// included when it is read by its ID.
+func (s *stateVersionOutputs) Read(ctx context.Context, outputID string) (*StateVersionOutput, error) {
+	if !validResourceID(&outputID, "wsout-") {
+		return nil, errors.New("invalid value for state version output ID")
+	}
+
+	u := fmt.Sprintf("state-version-outputs/%s", url.QueryEscape(outputID))
+	req, err := s.client.newRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &bytes.Buffer{}
+	if err := s.client.do(ctx, req, body); err != nil {
+		return nil, err
+	}
+
+	o := &StateVersionOutput{}
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(body.Bytes()), o); err != nil {
+		return nil, err
+	}
+	if err := decodeOutputValues(body.Bytes(), []*StateVersionOutput{o}); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// ReadCurrent lists the outputs of the current state version of the given
+// workspace, without reading the state version itself. This is the cheapest
+// way to consume the outputs of another workspace. The values of sensitive
+// outputs are not included, so their Value is nil; use Read to read them
+// one by one. If the workspace does not exist or has never stored any
+// state, an error wrapping ErrResourceNotFound is returned, as the API does
+// not tell these apart.
+func (s *stateVersionOutputs) ReadCurrent(ctx context.Context, workspaceID string, options StateVersionOutputsListOptions) (*StateVersionOutputsList, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+
+	u := fmt.Sprintf("workspaces/%s/current-state-version-outputs", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("GET", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.doOutputsList(ctx, req)
+}
+
+// doOutputsList sends the request and decodes the listed outputs, including
+// their values.
+func (c *Client) doOutputsList(ctx context.Context, req *retryablehttp.Request) (*StateVersionOutputsList, error) {
+	body := &bytes.Buffer{}
+	if err := c.do(ctx, req, body); err != nil {
+		return nil, err
+	}
+
+	ol := &StateVersionOutputsList{}
+	if err := unmarshalResponse(bytes.NewReader(body.Bytes()), int64(body.Len()), ol); err != nil {
+		return nil, err
+	}
+	if err := decodeOutputValues(body.Bytes(), ol.Items); err != nil {
+		return nil, err
+	}
+
+	return ol, nil
+}
+
+// decodeOutputValues sets the values and detailed types of the outputs, using the state
+// version outputs in either the primary or the included data of the JSONAPI
+// document in body.
+func decodeOutputValues(body []byte, outputs []*StateVersionOutput) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	byID, err := decodeOutputResources(body, "state-version-outputs")
+	if err != nil {
+		return err
+	}
+
+	for _, o := range outputs {
+		if o == nil {
+			continue
+		}
+		if r, ok := byID[o.ID]; ok {
+			o.Value = r.Attributes.Value
+			o.DetailedType = r.Attributes.DetailedType
+		}
+	}
+
+	return nil
+}
+
+// outputResource holds the attributes of an output resource that the JSONAPI
+// decoder cannot decode.
+type outputResource struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Value        interface{} `json:"value"`
+		DetailedType interface{} `json:"detailed-type"`
+	} `json:"attributes"`
+}
+
+// decodeOutputResources returns the resources of the given output type in
+// either the primary or the included data of the JSONAPI document in body,
+// by their ID.
+func decodeOutputResources(body []byte, outputType string) (map[string]*outputResource, error) {
+	var doc struct {
+		Data     json.RawMessage   `json:"data"`
+		Included []*outputResource `json:"included"`
+	}
+	if err := unmarshalUseNumber(body, &doc); err != nil {
+		return nil, err
+	}
+
+	// The primary data is either a single resource or a list of them.
+	resources := doc.Included
+	var data []*outputResource
+	if err := unmarshalUseNumber(doc.Data, &data); err == nil {
+		resources = append(resources, data...)
+	} else {
+		r := &outputResource{}
+		if err := unmarshalUseNumber(doc.Data, r); err != nil {
+			return nil, err
+		}
+		resources = append(resources, r)
+	}
+
+	byID := make(map[string]*outputResource, len(resources))
+	for _, r := range resources {
+		if r != nil && r.Type == outputType {
+			byID[r.ID] = r
+		}
+	}
+
+	return byID, nil
+}
+
+// unmarshalUseNumber is like json.Unmarshal, but decodes numbers into an
+// interface{} as json.Number instead of float64.
+func unmarshalUseNumber(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
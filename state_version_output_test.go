@@ -0,0 +1,213 @@
+package tfe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateVersionOutputsReadFixture(t *testing.T) {
+	fixtures := map[string]string{
+		"wsout-J2zM24JPFbfc7bE5": "string.json",
+		"wsout-5ePXKJbsmwzPrT3h": "number.json",
+		"wsout-sZrh1xDR3Zw4Kp1v": "list.json",
+		"wsout-cWq5ewbST6BqR3eL": "map.json",
+		"wsout-Hb2mUEXpzWWa4pKR": "bool.json",
+		"wsout-Q8tv3vYKpG9m7xJa": "tuple.json",
+		"wsout-zrN3kJ6bXwdHcT2e": "object.json",
+		"wsout-v82BjkZnFEcscipg": "sensitive.json",
+	}
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := fixtures[strings.TrimPrefix(r.URL.Path, "/api/v2/state-version-outputs/")]
+		if !ok {
+			w.WriteHeader(404)
+			return
+		}
+
+		data, err := ioutil.ReadFile("test-fixtures/state-version-output/" + fixture)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(data)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with a string value", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-J2zM24JPFbfc7bE5")
+		require.NoError(t, err)
+		assert.Equal(t, "wsout-J2zM24JPFbfc7bE5", o.ID)
+		assert.Equal(t, "vpc_id", o.Name)
+		assert.Equal(t, "string", o.Type)
+		assert.Equal(t, "string", o.DetailedType)
+		assert.Equal(t, "vpc-0a1b2c3d", o.Value)
+	})
+
+	t.Run("with a number value", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-5ePXKJbsmwzPrT3h")
+		require.NoError(t, err)
+		assert.Equal(t, "number", o.Type)
+		assert.Equal(t, json.Number("3"), o.Value)
+
+		var count int
+		require.NoError(t, o.DecodeValue(&count))
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("with a bool value", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-Hb2mUEXpzWWa4pKR")
+		require.NoError(t, err)
+		assert.Equal(t, "boolean", o.Type)
+		assert.Equal(t, "bool", o.DetailedType)
+		assert.Equal(t, true, o.Value)
+	})
+
+	t.Run("with a list value", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-sZrh1xDR3Zw4Kp1v")
+		require.NoError(t, err)
+		assert.Equal(t, "array", o.Type)
+		assert.Equal(t, []interface{}{"list", "string"}, o.DetailedType)
+		assert.Equal(t, []interface{}{"subnet-1", "subnet-2"}, o.Value)
+	})
+
+	t.Run("with a tuple value", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-Q8tv3vYKpG9m7xJa")
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{
+			"tuple",
+			[]interface{}{"string", "number", "bool"},
+		}, o.DetailedType)
+		assert.Equal(t, []interface{}{"db.example.com", json.Number("5432"), false}, o.Value)
+	})
+
+	t.Run("with an object value", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-zrN3kJ6bXwdHcT2e")
+		require.NoError(t, err)
+		assert.Equal(t, "object", o.Type)
+		assert.Equal(t, map[string]interface{}{
+			"name":       "primary",
+			"account_id": json.Number("123456789012345678"),
+			"nodes": []interface{}{
+				map[string]interface{}{"name": "node-1", "zone": "us-east-1a", "weight": json.Number("0.5")},
+				map[string]interface{}{"name": "node-2", "zone": "us-east-1b", "weight": json.Number("1.5")},
+			},
+		}, o.Value)
+
+		var cluster struct {
+			Name      string `json:"name"`
+			AccountID int64  `json:"account_id"`
+			Nodes     []struct {
+				Name   string  `json:"name"`
+				Zone   string  `json:"zone"`
+				Weight float64 `json:"weight"`
+			} `json:"nodes"`
+		}
+		require.NoError(t, o.DecodeValue(&cluster))
+		assert.Equal(t, "primary", cluster.Name)
+		assert.Equal(t, int64(123456789012345678), cluster.AccountID)
+		require.Len(t, cluster.Nodes, 2)
+		assert.Equal(t, "us-east-1b", cluster.Nodes[1].Zone)
+		assert.Equal(t, 1.5, cluster.Nodes[1].Weight)
+	})
+
+	t.Run("with a map value", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-cWq5ewbST6BqR3eL")
+		require.NoError(t, err)
+		assert.Equal(t, "object", o.Type)
+		assert.Equal(t, []interface{}{"map", "string"}, o.DetailedType)
+		assert.Equal(t, map[string]interface{}{
+			"env":  "production",
+			"team": "networking",
+		}, o.Value)
+	})
+
+	t.Run("with a sensitive value", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-v82BjkZnFEcscipg")
+		require.NoError(t, err)
+		assert.True(t, o.Sensitive)
+		assert.Equal(t, "hunter2", o.Value)
+
+		var password string
+		require.NoError(t, o.DecodeValue(&password))
+		assert.Equal(t, "hunter2", password)
+	})
+
+	t.Run("when the output does not exist", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, "wsout-nonexisting")
+		assert.Nil(t, o)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with an invalid output ID", func(t *testing.T) {
+		o, err := client.StateVersionOutputs.Read(ctx, badIdentifier)
+		assert.Nil(t, o)
+		assert.EqualError(t, err, "invalid value for state version output ID")
+	})
+}
+
+func TestStateVersionOutputsReadCurrentFixture(t *testing.T) {
+	var query string
+	var requests int
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		var fixture string
+		switch r.URL.Path {
+		case "/api/v2/workspaces/ws-123/current-state-version-outputs":
+			query = r.URL.RawQuery
+			fixture = "state-version/outputs.json"
+		default:
+			w.WriteHeader(404)
+			return
+		}
+
+		data, err := ioutil.ReadFile("test-fixtures/" + fixture)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(data)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when reading the current outputs", func(t *testing.T) {
+		ol, err := client.StateVersionOutputs.ReadCurrent(ctx, "ws-123", StateVersionOutputsListOptions{
+			ListOptions: ListOptions{PageNumber: 2, PageSize: 5},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "page%5Bnumber%5D=2&page%5Bsize%5D=5", query)
+		assert.Equal(t, 3, ol.TotalCount)
+		require.Len(t, ol.Items, 3)
+		assert.Equal(t, "vpc_id", ol.Items[0].Name)
+		assert.Equal(t, "vpc-0a1b2c3d", ol.Items[0].Value)
+
+		// The values of sensitive outputs are not included.
+		assert.True(t, ol.Items[2].Sensitive)
+		assert.Nil(t, ol.Items[2].Value)
+
+		var password string
+		err = ol.Items[2].DecodeValue(&password)
+		assert.EqualError(t, err, `value of sensitive output "db_password" is not included`)
+	})
+
+	t.Run("when the workspace does not exist or has no state", func(t *testing.T) {
+		requests = 0
+		ol, err := client.StateVersionOutputs.ReadCurrent(ctx, "ws-nonexisting", StateVersionOutputsListOptions{})
+		assert.Nil(t, ol)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("with an invalid workspace ID", func(t *testing.T) {
+		ol, err := client.StateVersionOutputs.ReadCurrent(ctx, badIdentifier, StateVersionOutputsListOptions{})
+		assert.Nil(t, ol)
+		assert.EqualError(t, err, "invalid value for workspace ID")
+	})
+}
@@ -1,12 +1,20 @@
 package tfe
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -236,8 +244,16 @@ func TestStateVersionsCreate(t *testing.T) {
 			MD5:    String(fmt.Sprintf("%x", md5.Sum(state))),
 			Serial: Int64(0),
 		})
-		assert.Nil(t, sv)
-		assert.EqualError(t, err, "state is required")
+
+		// Servers that support uploading the state separately return
+		// the upload URL, older servers require the state.
+		if err != nil {
+			var errResp *ErrorResponse
+			assert.True(t, errors.As(err, &errResp), err)
+			assert.Nil(t, sv)
+			return
+		}
+		assert.NotEmpty(t, sv.UploadURL)
 	})
 
 	t.Run("with invalid workspace id", func(t *testing.T) {
@@ -267,9 +283,9 @@ func TestStateVersionsRead(t *testing.T) {
 	})
 
 	t.Run("when the state version does not exist", func(t *testing.T) {
-		sv, err := client.StateVersions.Read(ctx, "nonexisting")
+		sv, err := client.StateVersions.Read(ctx, "sv-nonexisting")
 		assert.Nil(t, sv)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid state version id", func(t *testing.T) {
@@ -307,7 +323,7 @@ func TestStateVersionsCurrent(t *testing.T) {
 	t.Run("when a state version does not exist", func(t *testing.T) {
 		sv, err := client.StateVersions.Current(ctx, wTest2.ID)
 		assert.Nil(t, sv)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid workspace id", func(t *testing.T) {
@@ -345,6 +361,779 @@ func TestStateVersionsDownload(t *testing.T) {
 	t.Run("with an invalid url", func(t *testing.T) {
 		state, err := client.StateVersions.Download(ctx, badIdentifier)
 		assert.Nil(t, state)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+}
+
+func TestStateVersionsCreatePayload(t *testing.T) {
+	state, err := ioutil.ReadFile("test-fixtures/state-version/terraform.tfstate")
+	require.NoError(t, err)
+
+	var body map[string]interface{}
+	var conflict string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		if conflict != "" {
+			w.WriteHeader(409)
+			fmt.Fprintf(w, `{"errors": [{"status": "409", "title": "conflict", "detail": %q}]}`, conflict)
+			return
+		}
+
+		body = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(201)
+		w.Write([]byte(`{"data": {
+			"id": "sv-123",
+			"type": "state-versions",
+			"attributes": {
+				"created-at": "2021-06-08T01:22:03.794Z",
+				"hosted-state-download-url": "https://archivist.terraform.io/v1/object/state",
+				"hosted-json-state-download-url": "https://archivist.terraform.io/v1/object/json-state",
+				"resources-processed": false,
+				"serial": 1,
+				"vcs-commit-sha": null,
+				"vcs-commit-url": null
+			}
+		}}`))
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	options := StateVersionCreateOptions{
+		Lineage:   String("741c4949-60b9-5bb1-5bf8-b14f4bb14af3"),
+		MD5:       String(fmt.Sprintf("%x", md5.Sum(state))),
+		Serial:    Int64(1),
+		State:     String(base64.StdEncoding.EncodeToString(state)),
+		JSONState: String(base64.StdEncoding.EncodeToString([]byte(`{"format_version":"0.1"}`))),
+	}
+
+	t.Run("when the workspace is locked", func(t *testing.T) {
+		sv, err := client.StateVersions.Create(ctx, "ws-123", options)
+		require.NoError(t, err)
+
+		data, ok := body["data"].(map[string]interface{})
+		require.True(t, ok)
+		attrs, ok := data["attributes"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, *options.JSONState, attrs["json-state"])
+		assert.Equal(t, *options.State, attrs["state"])
+		assert.Equal(t, *options.Lineage, attrs["lineage"])
+
+		assert.Equal(t, int64(1), sv.Serial)
+		assert.False(t, sv.ResourcesProcessed)
+		assert.Equal(t, "https://archivist.terraform.io/v1/object/json-state", sv.JSONDownloadURL)
+	})
+
+	t.Run("without JSON state", func(t *testing.T) {
+		options := options
+		options.JSONState = nil
+
+		_, err := client.StateVersions.Create(ctx, "ws-123", options)
+		require.NoError(t, err)
+
+		attrs := body["data"].(map[string]interface{})["attributes"].(map[string]interface{})
+		assert.NotContains(t, attrs, "json-state")
+	})
+
+	t.Run("when the workspace is not locked", func(t *testing.T) {
+		conflict = "Workspace must be locked"
+		defer func() { conflict = "" }()
+
+		sv, err := client.StateVersions.Create(ctx, "ws-123", options)
+		assert.Nil(t, sv)
+		assert.True(t, errors.Is(err, ErrWorkspaceNotLocked), err)
+	})
+
+	t.Run("with another conflict", func(t *testing.T) {
+		conflict = "Serial should be greater than the current serial"
+		defer func() { conflict = "" }()
+
+		sv, err := client.StateVersions.Create(ctx, "ws-123", options)
+		assert.Nil(t, sv)
+		assert.False(t, errors.Is(err, ErrWorkspaceNotLocked), err)
+
+		var errResp *ErrorResponse
+		require.True(t, errors.As(err, &errResp), err)
+		assert.Equal(t, 409, errResp.StatusCode)
+		assert.Equal(t, "Serial should be greater than the current serial", errResp.Errors[0].Detail)
+	})
+}
+
+func TestStateVersionsCurrentWithOptionsFixture(t *testing.T) {
+	current, err := ioutil.ReadFile("test-fixtures/state-version/current-with-outputs.json")
+	require.NoError(t, err)
+
+	var include string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/workspaces/ws-123/current-state-version":
+			include = r.URL.Query().Get("include")
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(current)
+		default:
+			// Like a workspace that never stored any state.
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with outputs included", func(t *testing.T) {
+		sv, err := client.StateVersions.CurrentWithOptions(ctx, "ws-123", StateVersionCurrentOptions{
+			Include: []StateVersionIncludeOpt{SVOutputs},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "outputs", include)
+
+		assert.Equal(t, int64(4), sv.Serial)
+		assert.True(t, sv.ResourcesProcessed)
+		assert.Equal(t, "run-YfmFLWpgTv31VZsP", sv.Run.ID)
+
+		require.Len(t, sv.Outputs, 3)
+		assert.Equal(t, "vpc_id", sv.Outputs[0].Name)
+		assert.Equal(t, "vpc-0a1b2c3d", sv.Outputs[0].Value)
+		assert.Equal(t, []interface{}{"subnet-1", "subnet-2"}, sv.Outputs[1].Value)
+		assert.True(t, sv.Outputs[2].Sensitive)
+		assert.Nil(t, sv.Outputs[2].Value)
+	})
+
+	t.Run("without options", func(t *testing.T) {
+		sv, err := client.StateVersions.Current(ctx, "ws-123")
+		require.NoError(t, err)
+		assert.Empty(t, include)
+		assert.Equal(t, "sv-SDboVZC8TCxXEneJ", sv.ID)
+	})
+
+	t.Run("when the workspace has no state", func(t *testing.T) {
+		sv, err := client.StateVersions.Current(ctx, "ws-nostate")
+		assert.Nil(t, sv)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+}
+
+func TestStateVersionsDownloadFixture(t *testing.T) {
+	const (
+		chunkSize = 1 << 20
+		chunks    = 4
+	)
+
+	// The first chunk is written right away, the others only once the
+	// client has read the first one. So reading all of the state before
+	// returning it would block until the timeout.
+	release := make(chan struct{})
+	var archivistAuth string
+	archivist := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archivistAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(bytes.Repeat([]byte("a"), chunkSize))
+		w.(http.Flusher).Flush()
+
+		if r.URL.Path == "/object/streamed" {
+			select {
+			case <-release:
+			case <-time.After(5 * time.Second):
+				t.Errorf("the first chunk was never read")
+				return
+			}
+		}
+
+		for i := 1; i < chunks; i++ {
+			w.Write(bytes.Repeat([]byte("a"), chunkSize))
+		}
+	}))
+	defer archivist.Close()
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/workspaces/ws-123/current-state-version":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": "sv-123", "type": "state-versions", "attributes": {
+				"hosted-state-download-url": %q
+			}}}`, "http://"+r.Host+"/api/state-versions/sv-123/hosted_state")
+		case "/api/state-versions/sv-123/hosted_state":
+			http.Redirect(w, r, archivist.URL+"/object/buffered", http.StatusTemporaryRedirect)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when streaming the state", func(t *testing.T) {
+		body, err := client.StateVersions.DownloadStream(ctx, archivist.URL+"/object/streamed")
+		require.NoError(t, err)
+		defer body.Close()
+
+		first := make([]byte, chunkSize)
+		_, err = io.ReadFull(body, first)
+		require.NoError(t, err)
+		close(release)
+
+		n, err := io.Copy(ioutil.Discard, body)
+		require.NoError(t, err)
+		assert.Equal(t, int64((chunks-1)*chunkSize), n)
+		assert.Empty(t, archivistAuth)
+	})
+
+	t.Run("when downloading the current state", func(t *testing.T) {
+		state, err := client.StateVersions.DownloadCurrent(ctx, "ws-123")
+		require.NoError(t, err)
+		assert.Len(t, state, chunks*chunkSize)
+		assert.Empty(t, archivistAuth)
+	})
+
+	t.Run("when the workspace has no state", func(t *testing.T) {
+		state, err := client.StateVersions.DownloadCurrent(ctx, "ws-nostate")
+		assert.Nil(t, state)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("without a download URL", func(t *testing.T) {
+		body, err := client.StateVersions.DownloadStream(ctx, "")
+		assert.Nil(t, body)
+		assert.EqualError(t, err, "invalid value for download URL")
+	})
+}
+
+func TestStateVersionsCreateRollbackPayload(t *testing.T) {
+	var body map[string]interface{}
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		if r.URL.Path == "/api/v2/workspaces/ws-other/state-versions" {
+			w.WriteHeader(422)
+			w.Write([]byte(`{"errors": [{
+				"status": "422",
+				"title": "invalid attribute",
+				"detail": "Rollback state version must belong to the same workspace",
+				"source": {"pointer": "/data/relationships/rollback-state-version"}
+			}]}`))
+			return
+		}
+
+		w.WriteHeader(201)
+		w.Write([]byte(`{"data": {
+			"id": "sv-456",
+			"type": "state-versions",
+			"attributes": {"serial": 5}
+		}}`))
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	options := StateVersionCreateOptions{
+		RollbackStateVersion: &StateVersion{ID: "sv-123"},
+	}
+
+	t.Run("with a state version of the workspace", func(t *testing.T) {
+		sv, err := client.StateVersions.Create(ctx, "ws-123", options)
+		require.NoError(t, err)
+		assert.Equal(t, "sv-456", sv.ID)
+
+		// Only the relationship is sent, without any state attributes.
+		data := body["data"].(map[string]interface{})
+		assert.Empty(t, data["attributes"])
+		assert.Equal(t, map[string]interface{}{
+			"rollback-state-version": map[string]interface{}{
+				"data": map[string]interface{}{
+					"type": "state-versions",
+					"id":   "sv-123",
+				},
+			},
+		}, data["relationships"])
+	})
+
+	t.Run("with a state version of another workspace", func(t *testing.T) {
+		sv, err := client.StateVersions.Create(ctx, "ws-other", options)
+		assert.Nil(t, sv)
+
+		var errResp *ErrorResponse
+		require.True(t, errors.As(err, &errResp), err)
+		assert.Equal(t, 422, errResp.StatusCode)
+		require.Len(t, errResp.Errors, 1)
+		assert.Equal(t, "Rollback state version must belong to the same workspace", errResp.Errors[0].Detail)
+		assert.Equal(t, "/data/relationships/rollback-state-version", errResp.Errors[0].Source.Pointer)
+	})
+}
+
+func TestStateVersionsOutputsFixture(t *testing.T) {
+	outputs, err := ioutil.ReadFile("test-fixtures/state-version/outputs.json")
+	require.NoError(t, err)
+	current, err := ioutil.ReadFile("test-fixtures/state-version/current-with-outputs.json")
+	require.NoError(t, err)
+
+	var include, page string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/state-versions/sv-SDboVZC8TCxXEneJ/outputs":
+			page = r.URL.Query().Get("page[number]")
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(outputs)
+		case "/api/v2/state-versions/sv-SDboVZC8TCxXEneJ":
+			include = r.URL.Query().Get("include")
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(current)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when listing the outputs", func(t *testing.T) {
+		ol, err := client.StateVersions.ListOutputs(ctx, "sv-SDboVZC8TCxXEneJ", StateVersionOutputsListOptions{
+			ListOptions: ListOptions{PageNumber: 1},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "1", page)
+		assert.Equal(t, 3, ol.TotalCount)
+		require.Len(t, ol.Items, 3)
+
+		assert.Equal(t, "vpc_id", ol.Items[0].Name)
+		assert.Equal(t, "string", ol.Items[0].Type)
+		assert.Equal(t, "vpc-0a1b2c3d", ol.Items[0].Value)
+
+		// An empty string value is distinct from a redacted value.
+		assert.Equal(t, "", ol.Items[1].Value)
+		assert.False(t, ol.Items[1].Sensitive)
+		assert.Nil(t, ol.Items[2].Value)
+		assert.True(t, ol.Items[2].Sensitive)
 	})
+
+	t.Run("when reading with outputs included", func(t *testing.T) {
+		sv, err := client.StateVersions.ReadWithOptions(ctx, "sv-SDboVZC8TCxXEneJ", StateVersionReadOptions{
+			Include: []StateVersionIncludeOpt{SVOutputs},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "outputs", include)
+		require.Len(t, sv.Outputs, 3)
+		assert.Equal(t, "vpc-0a1b2c3d", sv.Outputs[0].Value)
+	})
+
+	t.Run("when the state version does not exist", func(t *testing.T) {
+		ol, err := client.StateVersions.ListOutputs(ctx, "sv-nonexisting", StateVersionOutputsListOptions{})
+		assert.Nil(t, ol)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with an invalid state version ID", func(t *testing.T) {
+		ol, err := client.StateVersions.ListOutputs(ctx, badIdentifier, StateVersionOutputsListOptions{})
+		assert.Nil(t, ol)
+		assert.EqualError(t, err, "invalid value for state version ID")
+	})
+}
+
+func TestStateVersionsBackingDataFixture(t *testing.T) {
+	supported := true
+	var requests []string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !supported {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			if strings.Contains(r.URL.Path, "/actions/") {
+				w.WriteHeader(404)
+				return
+			}
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/state-versions/sv-g4rqST72reoHMM5a":
+			data, err := ioutil.ReadFile("test-fixtures/state-version/soft-deleted.json")
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(data)
+		case "/api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/soft_delete_backing_data",
+			"/api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/restore_backing_data",
+			"/api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/permanently_delete_backing_data":
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when reading a soft deleted state version", func(t *testing.T) {
+		sv, err := client.StateVersions.Read(ctx, "sv-g4rqST72reoHMM5a")
+		require.NoError(t, err)
+		assert.True(t, sv.BackingDataSoftDeleted)
+		assert.Equal(t, 12, sv.BillableRUMCount)
+		assert.Empty(t, sv.DownloadURL)
+	})
+
+	t.Run("when managing the backing data", func(t *testing.T) {
+		requests = nil
+
+		err := client.StateVersions.SoftDeleteBackingData(ctx, "sv-g4rqST72reoHMM5a")
+		require.NoError(t, err)
+		err = client.StateVersions.RestoreBackingData(ctx, "sv-g4rqST72reoHMM5a")
+		require.NoError(t, err)
+		err = client.StateVersions.PermanentlyDeleteBackingData(ctx, "sv-g4rqST72reoHMM5a")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"POST /api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/soft_delete_backing_data",
+			"POST /api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/restore_backing_data",
+			"POST /api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/permanently_delete_backing_data",
+		}, requests)
+	})
+
+	t.Run("when the state version does not exist", func(t *testing.T) {
+		err := client.StateVersions.SoftDeleteBackingData(ctx, "sv-nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+		assert.EqualError(t, err, "resource not found, or managing state version backing data is not supported by this TFE version")
+	})
+
+	t.Run("with an invalid state version ID", func(t *testing.T) {
+		err := client.StateVersions.RestoreBackingData(ctx, badIdentifier)
+		assert.EqualError(t, err, "invalid value for state version ID")
+	})
+
+	t.Run("with an older TFE version", func(t *testing.T) {
+		supported = false
+		defer func() { supported = true }()
+		requests = nil
+
+		err := client.StateVersions.SoftDeleteBackingData(ctx, "sv-g4rqST72reoHMM5a")
+		assert.True(t, errors.Is(err, ErrStateVersionBackingDataNotSupported), err)
+
+		err = client.StateVersions.RestoreBackingData(ctx, "sv-g4rqST72reoHMM5a")
+		assert.True(t, errors.Is(err, ErrStateVersionBackingDataNotSupported), err)
+
+		err = client.StateVersions.PermanentlyDeleteBackingData(ctx, "sv-g4rqST72reoHMM5a")
+		assert.True(t, errors.Is(err, ErrStateVersionBackingDataNotSupported), err)
+
+		// Only the action itself is requested.
+		assert.Equal(t, []string{
+			"POST /api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/soft_delete_backing_data",
+			"POST /api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/restore_backing_data",
+			"POST /api/v2/state-versions/sv-g4rqST72reoHMM5a/actions/permanently_delete_backing_data",
+		}, requests)
+	})
+}
+
+func TestStateVersionsCreateAndUploadFixture(t *testing.T) {
+	state, err := ioutil.ReadFile("test-fixtures/state-version/terraform.tfstate")
+	require.NoError(t, err)
+	jsonState := []byte(`{"format_version":"0.1","terraform_version":"0.12.24"}`)
+
+	var (
+		supportsUpload bool
+		invalidDetail  string
+		jsonUpload     bool
+		created        []map[string]interface{}
+		uploaded       map[string][]byte
+		status         StateVersionStatus
+		uploadedStatus StateVersionStatus
+		uploadAuth     string
+	)
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v2/workspaces/ws-123/state-versions":
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			attrs := body["data"].(map[string]interface{})["attributes"].(map[string]interface{})
+			created = append(created, attrs)
+
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			if _, ok := attrs["state"]; !ok && !supportsUpload {
+				w.WriteHeader(422)
+				fmt.Fprintf(w, `{"errors": [{"status": "422", "title": "invalid attribute", "detail": %q}]}`, invalidDetail)
+				return
+			}
+
+			status = StateVersionFinalized
+			if supportsUpload {
+				status = StateVersionPending
+			}
+			w.WriteHeader(201)
+			fmt.Fprintf(w, `{"data": {"id": "sv-123", "type": "state-versions", "attributes": {
+				"hosted-state-upload-url": %q,
+				"hosted-json-state-upload-url": %q,
+				"serial": 1,
+				"status": %q
+			}}}`, uploadURL(supportsUpload, "http://"+r.Host+"/object/state"), uploadURL(supportsUpload && jsonUpload, "http://"+r.Host+"/object/json-state"), status)
+		case "PUT /object/state", "PUT /object/json-state":
+			uploadAuth = r.Header.Get("Authorization")
+			uploaded[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+			if len(uploaded) == 2 {
+				status = uploadedStatus
+			}
+		case "GET /api/v2/state-versions/sv-123":
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			fmt.Fprintf(w, `{"data": {"id": "sv-123", "type": "state-versions", "attributes": {
+				"serial": 1,
+				"status": %q
+			}}}`, status)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	options := StateVersionUploadOptions{
+		StateVersionCreateOptions: StateVersionCreateOptions{
+			Lineage: String("741c4949-60b9-5bb1-5bf8-b14f4bb14af3"),
+			Serial:  Int64(1),
+		},
+		RawState:     state,
+		RawJSONState: jsonState,
+	}
+
+	t.Run("when the server supports uploading state", func(t *testing.T) {
+		supportsUpload = true
+		jsonUpload = true
+		uploadedStatus = StateVersionFinalized
+		created = nil
+		uploaded = make(map[string][]byte)
+
+		sv, err := client.StateVersions.CreateAndUpload(ctx, "ws-123", options)
+		require.NoError(t, err)
+		assert.Equal(t, StateVersionFinalized, sv.Status)
+
+		require.Len(t, created, 1)
+		assert.NotContains(t, created[0], "state")
+		assert.NotContains(t, created[0], "json-state")
+		assert.Equal(t, fmt.Sprintf("%x", md5.Sum(state)), created[0]["md5"])
+
+		assert.Equal(t, state, uploaded["/object/state"])
+		assert.Equal(t, jsonState, uploaded["/object/json-state"])
+		assert.Empty(t, uploadAuth)
+	})
+
+	t.Run("when the state version is discarded", func(t *testing.T) {
+		supportsUpload = true
+		jsonUpload = true
+		uploadedStatus = StateVersionDiscarded
+		uploaded = make(map[string][]byte)
+
+		sv, err := client.StateVersions.CreateAndUpload(ctx, "ws-123", options)
+		assert.Nil(t, sv)
+		assert.True(t, errors.Is(err, ErrStateVersionNotFinalized), err)
+
+		var notFinalized *StateVersionNotFinalizedError
+		require.True(t, errors.As(err, &notFinalized), err)
+		assert.Equal(t, "sv-123", notFinalized.ID)
+		assert.Equal(t, StateVersionDiscarded, notFinalized.Status)
+	})
+
+	t.Run("when the state version is not finalized in time", func(t *testing.T) {
+		defer func(polls int) { maxFinalizedPolls = polls }(maxFinalizedPolls)
+		maxFinalizedPolls = 2
+
+		supportsUpload = true
+		jsonUpload = true
+		uploadedStatus = StateVersionPending
+		uploaded = make(map[string][]byte)
+
+		sv, err := client.StateVersions.CreateAndUpload(ctx, "ws-123", options)
+		assert.Nil(t, sv)
+		assert.EqualError(t, err, "state version was not finalized: state version sv-123 has status pending")
+
+		var notFinalized *StateVersionNotFinalizedError
+		require.True(t, errors.As(err, &notFinalized), err)
+		assert.Equal(t, StateVersionPending, notFinalized.Status)
+	})
+
+	t.Run("without a JSON state upload URL", func(t *testing.T) {
+		supportsUpload = true
+		jsonUpload = false
+		created = nil
+		uploaded = make(map[string][]byte)
+
+		sv, err := client.StateVersions.CreateAndUpload(ctx, "ws-123", options)
+		assert.Nil(t, sv)
+		assert.EqualError(t, err, "state version sv-123 was created without a JSON state upload URL")
+		assert.Empty(t, uploaded)
+	})
+
+	t.Run("with another validation error", func(t *testing.T) {
+		supportsUpload = false
+		invalidDetail = "Lineage is invalid"
+		created = nil
+		uploaded = make(map[string][]byte)
+
+		sv, err := client.StateVersions.CreateAndUpload(ctx, "ws-123", options)
+		assert.Nil(t, sv)
+		assert.EqualError(t, err, "invalid attribute\n\nLineage is invalid")
+		assert.Len(t, created, 1)
+	})
+
+	t.Run("when the server requires inline state", func(t *testing.T) {
+		supportsUpload = false
+		invalidDetail = "State can't be blank"
+		created = nil
+		uploaded = make(map[string][]byte)
+
+		sv, err := client.StateVersions.CreateAndUpload(ctx, "ws-123", options)
+		require.NoError(t, err)
+		assert.Equal(t, StateVersionFinalized, sv.Status)
+
+		require.Len(t, created, 2)
+		assert.NotContains(t, created[0], "state")
+		assert.Equal(t, base64.StdEncoding.EncodeToString(state), created[1]["state"])
+		assert.Equal(t, base64.StdEncoding.EncodeToString(jsonState), created[1]["json-state"])
+		assert.Empty(t, uploaded)
+	})
+}
+
+// uploadURL returns the URL if uploading state is supported.
+func uploadURL(supported bool, url string) string {
+	if supported {
+		return url
+	}
+	return ""
+}
+
+func TestStateVersionsInvalidStateVersionID(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	calls := map[string]func(svID string) error{
+		"Read": func(svID string) error {
+			_, err := client.StateVersions.Read(ctx, svID)
+			return err
+		},
+		"ListOutputs": func(svID string) error {
+			_, err := client.StateVersions.ListOutputs(ctx, svID, StateVersionOutputsListOptions{})
+			return err
+		},
+		"SoftDeleteBackingData": func(svID string) error {
+			return client.StateVersions.SoftDeleteBackingData(ctx, svID)
+		},
+		"RestoreBackingData": func(svID string) error {
+			return client.StateVersions.RestoreBackingData(ctx, svID)
+		},
+		"PermanentlyDeleteBackingData": func(svID string) error {
+			return client.StateVersions.PermanentlyDeleteBackingData(ctx, svID)
+		},
+	}
+
+	for name, call := range calls {
+		for _, svID := range []string{"", "run-abc", badIdentifier} {
+			t.Run(name+" with "+svID, func(t *testing.T) {
+				assert.EqualError(t, call(svID), "invalid value for state version ID")
+			})
+		}
+	}
+}
+
+func TestStateVersionCreateOptionsValid(t *testing.T) {
+	state := String(base64.StdEncoding.EncodeToString([]byte("{}")))
+	rollback := &StateVersion{ID: "sv-123"}
+
+	cases := map[string]struct {
+		options StateVersionCreateOptions
+		err     string
+	}{
+		"with state": {
+			options: StateVersionCreateOptions{MD5: String("abc"), Serial: Int64(1), State: state},
+		},
+		"without state": {
+			options: StateVersionCreateOptions{MD5: String("abc"), Serial: Int64(1)},
+		},
+		"without MD5": {
+			options: StateVersionCreateOptions{Serial: Int64(1), State: state},
+			err:     "MD5 is required",
+		},
+		"with a rollback state version": {
+			options: StateVersionCreateOptions{RollbackStateVersion: rollback},
+		},
+		"with an invalid rollback state version ID": {
+			options: StateVersionCreateOptions{RollbackStateVersion: &StateVersion{ID: badIdentifier}},
+			err:     "invalid value for rollback state version ID",
+		},
+		"with a rollback state version and state": {
+			options: StateVersionCreateOptions{RollbackStateVersion: rollback, State: state},
+			err:     "only one of rollback state version or state can be set",
+		},
+		"with a rollback state version and JSON state": {
+			options: StateVersionCreateOptions{RollbackStateVersion: rollback, JSONState: state},
+			err:     "only one of rollback state version or state can be set",
+		},
+		"with a rollback state version and a serial": {
+			options: StateVersionCreateOptions{RollbackStateVersion: rollback, Serial: Int64(1)},
+			err:     "MD5, serial and lineage cannot be set when rolling back",
+		},
+		"with a rollback state version and a lineage": {
+			options: StateVersionCreateOptions{RollbackStateVersion: rollback, Lineage: String("lineage")},
+			err:     "MD5, serial and lineage cannot be set when rolling back",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := c.options.valid()
+			if c.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, c.err)
+		})
+	}
+}
+
+func TestStateVersionUploadOptionsValid(t *testing.T) {
+	raw := []byte("{}")
+
+	cases := map[string]struct {
+		options StateVersionUploadOptions
+		err     string
+	}{
+		"with raw state": {
+			options: StateVersionUploadOptions{
+				StateVersionCreateOptions: StateVersionCreateOptions{Serial: Int64(1)},
+				RawState:                  raw,
+			},
+		},
+		"without raw state": {
+			options: StateVersionUploadOptions{
+				StateVersionCreateOptions: StateVersionCreateOptions{Serial: Int64(1)},
+			},
+			err: "raw state is required",
+		},
+		"with state": {
+			options: StateVersionUploadOptions{
+				StateVersionCreateOptions: StateVersionCreateOptions{Serial: Int64(1), State: String("e30=")},
+				RawState:                  raw,
+			},
+			err: "state and JSON state cannot be set, use raw state and raw JSON state instead",
+		},
+		"with a rollback state version": {
+			options: StateVersionUploadOptions{
+				StateVersionCreateOptions: StateVersionCreateOptions{
+					Serial:               Int64(1),
+					RollbackStateVersion: &StateVersion{ID: "sv-123"},
+				},
+				RawState: raw,
+			},
+			err: "rollback state version cannot be set when uploading state",
+		},
+		"without serial": {
+			options: StateVersionUploadOptions{RawState: raw},
+			err:     "serial is required",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := c.options.valid()
+			if c.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, c.err)
+		})
+	}
 }
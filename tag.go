@@ -0,0 +1,47 @@
+package tfe
+
+// TagList represents a list of tags.
+type TagList struct {
+	*Pagination
+	Items []*Tag
+}
+
+// Tag represents a Terraform Enterprise tag. A tag is referenced either by
+// its ID, to attach an existing tag, or by its name, to create the tag if
+// it does not exist yet.
+type Tag struct {
+	ID            string `jsonapi:"primary,tags"`
+	Name          string `jsonapi:"attr,name,omitempty"`
+	InstanceCount int    `jsonapi:"attr,instance-count"`
+}
+
+// tagsPayload is the JSONAPI document that is sent to a tags relationship
+// endpoint. It is JSON encoded, as it has no JSONAPI annotations.
+type tagsPayload struct {
+	Data []*tagObject `json:"data"`
+}
+
+// tagObject references a tag by its ID, or by its name if it has no ID.
+type tagObject struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id,omitempty"`
+	Attributes *tagAttributes `json:"attributes,omitempty"`
+}
+
+// tagAttributes contains the name of a tag that is referenced by name.
+type tagAttributes struct {
+	Name string `json:"name"`
+}
+
+// newTagsPayload returns the payload that references the given tags.
+func newTagsPayload(tags []*Tag) *tagsPayload {
+	payload := &tagsPayload{Data: make([]*tagObject, 0, len(tags))}
+	for _, tag := range tags {
+		obj := &tagObject{Type: "tags", ID: tag.ID}
+		if tag.ID == "" {
+			obj.Attributes = &tagAttributes{Name: tag.Name}
+		}
+		payload.Data = append(payload.Data, obj)
+	}
+	return payload
+}
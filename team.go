@@ -153,7 +153,7 @@ func (s *teams) Create(ctx context.Context, organization string, options TeamCre
 
 // Read a single team by its ID.
 func (s *teams) Read(ctx context.Context, teamID string) (*Team, error) {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return nil, errors.New("invalid value for team ID")
 	}
 
@@ -187,11 +187,21 @@ type TeamUpdateOptions struct {
 	Visibility *string `jsonapi:"attr,visibility,omitempty"`
 }
 
+func (o TeamUpdateOptions) valid() error {
+	if o.Name != nil && !validString(o.Name) {
+		return errors.New("invalid value for name")
+	}
+	return nil
+}
+
 // Update a team by its ID.
 func (s *teams) Update(ctx context.Context, teamID string, options TeamUpdateOptions) (*Team, error) {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return nil, errors.New("invalid value for team ID")
 	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
 
 	// Make sure we don't send a user provided ID.
 	options.ID = ""
@@ -213,7 +223,7 @@ func (s *teams) Update(ctx context.Context, teamID string, options TeamUpdateOpt
 
 // Delete a team by its ID.
 func (s *teams) Delete(ctx context.Context, teamID string) error {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return errors.New("invalid value for team ID")
 	}
 
@@ -150,7 +150,7 @@ func (s *teamAccesses) Add(ctx context.Context, options TeamAccessAddOptions) (*
 
 // Read a team access by its ID.
 func (s *teamAccesses) Read(ctx context.Context, teamAccessID string) (*TeamAccess, error) {
-	if !validStringID(&teamAccessID) {
+	if !validResourceID(&teamAccessID, "tws-") {
 		return nil, errors.New("invalid value for team access ID")
 	}
 
@@ -171,7 +171,7 @@ func (s *teamAccesses) Read(ctx context.Context, teamAccessID string) (*TeamAcce
 
 // Remove team access from a workspace.
 func (s *teamAccesses) Remove(ctx context.Context, teamAccessID string) error {
-	if !validStringID(&teamAccessID) {
+	if !validResourceID(&teamAccessID, "tws-") {
 		return errors.New("invalid value for team access ID")
 	}
 
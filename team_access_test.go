@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -169,9 +170,9 @@ func TestTeamAccessesRead(t *testing.T) {
 	})
 
 	t.Run("when the team access does not exist", func(t *testing.T) {
-		ta, err := client.TeamAccess.Read(ctx, "nonexisting")
+		ta, err := client.TeamAccess.Read(ctx, "tws-nonexisting")
 		assert.Nil(t, ta)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid team access ID", func(t *testing.T) {
@@ -199,12 +200,12 @@ func TestTeamAccessesRemove(t *testing.T) {
 
 		// Try loading the workspace - it should fail.
 		_, err = client.TeamAccess.Read(ctx, taTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the team access does not exist", func(t *testing.T) {
 		err := client.TeamAccess.Remove(ctx, taTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when the team access ID is invalid", func(t *testing.T) {
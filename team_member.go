@@ -56,7 +56,7 @@ func (s *teamMembers) List(ctx context.Context, teamID string) ([]*User, error)
 
 // ListUsers returns the Users of this team.
 func (s *teamMembers) ListUsers(ctx context.Context, teamID string) ([]*User, error) {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return nil, errors.New("invalid value for team ID")
 	}
 
@@ -83,7 +83,7 @@ func (s *teamMembers) ListUsers(ctx context.Context, teamID string) ([]*User, er
 
 // ListOrganizationMemberships returns the OrganizationMemberships of this team.
 func (s *teamMembers) ListOrganizationMemberships(ctx context.Context, teamID string) ([]*OrganizationMembership, error) {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return nil, errors.New("invalid value for team ID")
 	}
 
@@ -142,7 +142,7 @@ func (o *TeamMemberAddOptions) kind() string {
 
 // Add multiple users to a team.
 func (s *teamMembers) Add(ctx context.Context, teamID string, options TeamMemberAddOptions) error {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return errors.New("invalid value for team ID")
 	}
 	if err := options.valid(); err != nil {
@@ -176,7 +176,8 @@ func (s *teamMembers) Add(ctx context.Context, teamID string, options TeamMember
 		}
 	}
 
-	return s.client.do(ctx, req, nil)
+	// Adding members that are already part of the team has no effect.
+	return s.client.do(ctx, withRetryable(req), nil)
 }
 
 // TeamMemberRemoveOptions represents the options for
@@ -213,7 +214,7 @@ func (o *TeamMemberRemoveOptions) kind() string {
 
 // Remove multiple users from a team.
 func (s *teamMembers) Remove(ctx context.Context, teamID string, options TeamMemberRemoveOptions) error {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return errors.New("invalid value for team ID")
 	}
 	if err := options.valid(); err != nil {
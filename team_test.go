@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -128,9 +129,9 @@ func TestTeamsRead(t *testing.T) {
 	})
 
 	t.Run("when the team does not exist", func(t *testing.T) {
-		tm, err := client.Teams.Read(ctx, "nonexisting")
+		tm, err := client.Teams.Read(ctx, "team-nonexisting")
 		assert.Nil(t, tm)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid team ID", func(t *testing.T) {
@@ -186,11 +187,11 @@ func TestTeamsUpdate(t *testing.T) {
 	})
 
 	t.Run("when the team does not exist", func(t *testing.T) {
-		tm, err := client.Teams.Update(ctx, "nonexisting", TeamUpdateOptions{
+		tm, err := client.Teams.Update(ctx, "team-nonexisting", TeamUpdateOptions{
 			Name: String("foo bar"),
 		})
 		assert.Nil(t, tm)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid team ID", func(t *testing.T) {
@@ -215,7 +216,7 @@ func TestTeamsDelete(t *testing.T) {
 
 		// Try loading the workspace - it should fail.
 		_, err = client.Teams.Read(ctx, tmTest.ID)
-		assert.Equal(t, err, ErrResourceNotFound)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without valid team ID", func(t *testing.T) {
@@ -43,7 +43,7 @@ type TeamToken struct {
 
 // Generate a new team token, replacing any existing token.
 func (s *teamTokens) Generate(ctx context.Context, teamID string) (*TeamToken, error) {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return nil, errors.New("invalid value for team ID")
 	}
 
@@ -64,7 +64,7 @@ func (s *teamTokens) Generate(ctx context.Context, teamID string) (*TeamToken, e
 
 // Read a team token by its ID.
 func (s *teamTokens) Read(ctx context.Context, teamID string) (*TeamToken, error) {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return nil, errors.New("invalid value for team ID")
 	}
 
@@ -85,7 +85,7 @@ func (s *teamTokens) Read(ctx context.Context, teamID string) (*TeamToken, error
 
 // Delete a team token by its ID.
 func (s *teamTokens) Delete(ctx context.Context, teamID string) error {
-	if !validStringID(&teamID) {
+	if !validResourceID(&teamID, "team-") {
 		return errors.New("invalid value for team ID")
 	}
 
@@ -1,11 +1,26 @@
 package tfe
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -48,6 +63,38 @@ func TestClient_newClient(t *testing.T) {
 		}
 	})
 
+	t.Run("prefers explicit values over env vars", func(t *testing.T) {
+		defer setupEnvVars("abcd1234", "https://mytfe.local")()
+
+		client, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "efgh5678",
+			HTTPClient: ts.Client(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client.token != "efgh5678" {
+			t.Fatalf("unexpected token: %q", client.token)
+		}
+		if client.baseURL.String() != ts.URL+DefaultBasePath {
+			t.Fatalf("unexpected address: %q", client.baseURL.String())
+		}
+	})
+
+	t.Run("fails if token is malformed", func(t *testing.T) {
+		defer setupEnvVars("", "")()
+
+		_, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "abcd 1234",
+			HTTPClient: ts.Client(),
+		})
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid API token") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
 	t.Run("makes a new client with good settings", func(t *testing.T) {
 		config := &Config{
 			Address:    ts.URL,
@@ -72,6 +119,81 @@ func TestClient_newClient(t *testing.T) {
 	})
 }
 
+func TestClient_baseURL(t *testing.T) {
+	ts := newTestServer(nil)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		address string
+		path    string
+		result  string
+	}{
+		"host": {
+			address: ts.URL,
+			path:    "organizations",
+			result:  ts.URL + "/api/v2/organizations",
+		},
+		"host-with-slash": {
+			address: ts.URL + "/",
+			path:    "organizations",
+			result:  ts.URL + "/api/v2/organizations",
+		},
+		"host-with-prefix": {
+			address: ts.URL + "/tfe",
+			path:    "organizations",
+			result:  ts.URL + "/tfe/api/v2/organizations",
+		},
+		"host-with-prefix-and-slash": {
+			address: ts.URL + "/tfe/",
+			path:    "organizations",
+			result:  ts.URL + "/tfe/api/v2/organizations",
+		},
+		"host-with-leading-slash-path": {
+			address: ts.URL,
+			path:    "/organizations",
+			result:  ts.URL + "/api/v2/organizations",
+		},
+		"host-with-prefix-and-leading-slash-path": {
+			address: ts.URL + "/tfe",
+			path:    "/organizations",
+			result:  ts.URL + "/tfe/api/v2/organizations",
+		},
+	}
+
+	for name, tc := range cases {
+		client, err := NewClient(&Config{
+			Address:    tc.address,
+			Token:      "dummy-token",
+			HTTPClient: ts.Client(),
+		})
+		if err != nil {
+			t.Fatalf("test %s unexpected error: %v", name, err)
+		}
+
+		req, err := client.newRequest("GET", tc.path, nil)
+		if err != nil {
+			t.Fatalf("test %s unexpected error: %v", name, err)
+		}
+
+		if req.URL.String() != tc.result {
+			t.Fatalf("test %s expected URL %q, got: %q", name, tc.result, req.URL.String())
+		}
+	}
+
+	t.Run("with an invalid address", func(t *testing.T) {
+		for _, address := range []string{"://nope", "ftp://app.terraform.io", "app.terraform.io"} {
+			_, err := NewClient(&Config{
+				Address:    address,
+				Token:      "dummy-token",
+				HTTPClient: ts.Client(),
+			})
+			if err == nil || !strings.HasPrefix(err.Error(), "invalid address") {
+				t.Fatalf("address %q expected an invalid address error, got: %v", address, err)
+			}
+		}
+	})
+}
+
 func TestClient_defaultConfig(t *testing.T) {
 	t.Run("with no environment variables", func(t *testing.T) {
 		defer setupEnvVars("", "")()
@@ -91,6 +213,28 @@ func TestClient_defaultConfig(t *testing.T) {
 
 	t.Run("with environment variables", func(t *testing.T) {
 		defer setupEnvVars("abcd1234", "https://mytfe.local")()
+
+		config := DefaultConfig()
+
+		if config.Address != "https://mytfe.local" {
+			t.Fatalf("expected %q, got %q", "https://mytfe.local", config.Address)
+		}
+		if config.Token != "abcd1234" {
+			t.Fatalf("expected %q, got %q", "abcd1234", config.Token)
+		}
+	})
+
+	t.Run("with environment variables containing whitespace", func(t *testing.T) {
+		defer setupEnvVars(" abcd1234\n", "https://mytfe.local\n")()
+
+		config := DefaultConfig()
+
+		if config.Address != "https://mytfe.local" {
+			t.Fatalf("expected %q, got %q", "https://mytfe.local", config.Address)
+		}
+		if config.Token != "abcd1234" {
+			t.Fatalf("expected %q, got %q", "abcd1234", config.Token)
+		}
 	})
 }
 
@@ -157,6 +301,63 @@ func TestClient_headers(t *testing.T) {
 	}
 }
 
+func TestClient_headerOverrides(t *testing.T) {
+	ts := newTestServer(nil)
+	defer ts.Close()
+
+	newClient := func(t *testing.T, headers http.Header) *Client {
+		client, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "dummy-token",
+			Headers:    headers,
+			HTTPClient: ts.Client(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+
+	t.Run("always uses the API token", func(t *testing.T) {
+		client := newClient(t, http.Header{"authorization": []string{"Bearer bad-token"}})
+
+		req, err := client.newRequest("GET", "foo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := req.Header["Authorization"]; len(got) != 1 || got[0] != "Bearer dummy-token" {
+			t.Fatalf("unexpected authorization header: %q", got)
+		}
+		if _, ok := req.Header["authorization"]; ok {
+			t.Fatal("expected the configured authorization header to be replaced")
+		}
+	})
+
+	t.Run("ignores an empty content type", func(t *testing.T) {
+		client := newClient(t, http.Header{"Content-Type": []string{""}})
+
+		req, err := client.newRequest("POST", "foo", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := req.Header.Get("Content-Type"); got != "application/vnd.api+json" {
+			t.Fatalf("unexpected content type header: %q", got)
+		}
+	})
+
+	t.Run("always uploads raw content as an octet stream", func(t *testing.T) {
+		client := newClient(t, http.Header{"Content-Type": []string{"application/json"}})
+
+		req, err := client.newRequest("PUT", "foo", bytes.NewReader([]byte("content")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := req.Header.Get("Content-Type"); got != "application/octet-stream" {
+			t.Fatalf("unexpected content type header: %q", got)
+		}
+	})
+}
+
 func TestClient_userAgent(t *testing.T) {
 	testedCalls := 0
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -204,6 +405,51 @@ func TestClient_userAgent(t *testing.T) {
 	}
 }
 
+func TestClient_headerPrecedence(t *testing.T) {
+	ts := newTestServer(nil)
+	defer ts.Close()
+
+	cfg := &Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		Headers:    make(http.Header),
+		HTTPClient: ts.Client(),
+	}
+
+	// Config level headers, of which only the custom ones should be used.
+	cfg.Headers.Set("Accept", "text/plain")
+	cfg.Headers.Set("Authorization", "Bearer bad-token")
+	cfg.Headers.Set("Content-Type", "text/plain")
+	cfg.Headers.Set("My-Custom-Header", "foobar")
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := client.newRequest("POST", "organizations", &OrganizationCreateOptions{
+		Name:  String("organization"),
+		Email: String("info@example.com"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Accept":           "application/vnd.api+json",
+		"Authorization":    "Bearer dummy-token",
+		"Content-Type":     "application/vnd.api+json",
+		"My-Custom-Header": "foobar",
+		"User-Agent":       userAgent,
+	}
+
+	for k, v := range expected {
+		if req.Header.Get(k) != v {
+			t.Fatalf("expected header %s to be %q, got: %q", k, v, req.Header.Get(k))
+		}
+	}
+}
+
 func TestClient_configureLimiter(t *testing.T) {
 	rateLimit := ""
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -220,9 +466,10 @@ func TestClient_configureLimiter(t *testing.T) {
 	}
 
 	cases := map[string]struct {
-		rate  string
-		limit rate.Limit
-		burst int
+		rate              string
+		requestsPerSecond float64
+		limit             rate.Limit
+		burst             int
 	}{
 		"no-value": {
 			rate:  "",
@@ -244,11 +491,36 @@ func TestClient_configureLimiter(t *testing.T) {
 			limit: rate.Limit(66),
 			burst: 33,
 		},
+		"requests-per-second-no-value": {
+			rate:              "",
+			requestsPerSecond: 10,
+			limit:             rate.Limit(10),
+			burst:             3,
+		},
+		"requests-per-second-below-limit": {
+			rate:              "30",
+			requestsPerSecond: 10,
+			limit:             rate.Limit(10),
+			burst:             3,
+		},
+		"requests-per-second-above-limit": {
+			rate:              "30",
+			requestsPerSecond: 50,
+			limit:             rate.Limit(19.8),
+			burst:             9,
+		},
+		"requests-per-second-1": {
+			rate:              "30",
+			requestsPerSecond: 1,
+			limit:             rate.Limit(1),
+			burst:             1,
+		},
 	}
 
 	for name, tc := range cases {
 		// First set the test rate limit.
 		rateLimit = tc.rate
+		cfg.RequestsPerSecond = tc.requestsPerSecond
 
 		client, err := NewClient(cfg)
 		if err != nil {
@@ -314,6 +586,20 @@ func TestClient_retryHTTPCheck(t *testing.T) {
 			checkOK:           true,
 			checkErr:          nil,
 		},
+		"500-post-with-server-errors": {
+			resp:              &http.Response{StatusCode: 500, Request: &http.Request{Method: "POST"}},
+			err:               nil,
+			retryServerErrors: true,
+			checkOK:           false,
+			checkErr:          nil,
+		},
+		"429-post-with-server-errors": {
+			resp:              &http.Response{StatusCode: 429, Request: &http.Request{Method: "POST"}},
+			err:               nil,
+			retryServerErrors: true,
+			checkOK:           true,
+			checkErr:          nil,
+		},
 		"err-no-server-errors": {
 			err:      connErr,
 			checkOK:  false,
@@ -347,6 +633,104 @@ func TestClient_retryHTTPCheck(t *testing.T) {
 	}
 }
 
+func TestClient_retryIdempotent(t *testing.T) {
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts[r.Method+" "+r.URL.Path]++
+		n := attempts[r.Method+" "+r.URL.Path]
+		mu.Unlock()
+
+		// Fail the first attempt of every request.
+		if n == 1 {
+			w.WriteHeader(500)
+			return
+		}
+		w.WriteHeader(204)
+	})
+	defer ts.Close()
+
+	client, err := NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		HTTPClient: ts.Client(),
+		RetryMax:   Int(2),
+
+		ServerErrorRetryWaitMin: time.Millisecond,
+		ServerErrorRetryWaitMax: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		method    string
+		retryable bool
+		attempts  int
+	}{
+		"get":            {method: "GET", attempts: 2},
+		"patch":          {method: "PATCH", attempts: 1},
+		"delete":         {method: "DELETE", attempts: 1},
+		"post":           {method: "POST", attempts: 1},
+		"post-retryable": {method: "POST", retryable: true, attempts: 2},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, err := client.newRequest(tc.method, name, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.retryable {
+				req = withRetryable(req)
+			}
+
+			err = client.do(context.Background(), req, nil)
+			if tc.attempts == 1 && err == nil {
+				t.Fatal("expected an error")
+			}
+			if tc.attempts > 1 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got := attempts[tc.method+" /api/v2/"+name]; got != tc.attempts {
+				t.Fatalf("expected %d attempts, got: %d", tc.attempts, got)
+			}
+		})
+	}
+
+	t.Run("connection errors", func(t *testing.T) {
+		dialErr := &url.Error{Op: "Patch", URL: ts.URL, Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+		readErr := &url.Error{Op: "Patch", URL: ts.URL, Err: &net.OpError{Op: "read", Err: errors.New("connection reset")}}
+
+		for _, tc := range []struct {
+			method string
+			err    error
+			retry  bool
+		}{
+			{method: "GET", err: readErr, retry: true},
+			{method: "PATCH", err: dialErr, retry: true},
+			{method: "PATCH", err: readErr, retry: false},
+			{method: "PUT", err: dialErr, retry: true},
+			{method: "DELETE", err: readErr, retry: false},
+			{method: "POST", err: dialErr, retry: false},
+		} {
+			ctx := context.WithValue(context.Background(), retryPolicyKey{}, retryPolicy{method: tc.method})
+			retry, err := client.retryHTTPCheck(ctx, nil, tc.err)
+			if retry != tc.retry {
+				t.Fatalf("expected retry %t for %s with %v, got: %t", tc.retry, tc.method, tc.err, retry)
+			}
+			if err != tc.err {
+				t.Fatalf("expected error %v, got: %v", tc.err, err)
+			}
+		}
+	})
+}
+
 func TestClient_retryHTTPBackoff(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/vnd.api+json")
@@ -384,6 +768,2974 @@ func TestClient_retryHTTPBackoff(t *testing.T) {
 	}
 }
 
+func TestClient_retryConfig(t *testing.T) {
+	ts := newTestServer(nil)
+	defer ts.Close()
+
+	t.Run("uses the default values", func(t *testing.T) {
+		client, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "dummy-token",
+			HTTPClient: ts.Client(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if client.http.RetryMax != DefaultRetryMax {
+			t.Fatalf("expected retry max %d, got: %d", DefaultRetryMax, client.http.RetryMax)
+		}
+		if client.http.RetryWaitMin != 100*time.Millisecond {
+			t.Fatalf("expected retry wait min 100ms, got: %s", client.http.RetryWaitMin)
+		}
+		if client.http.RetryWaitMax != 400*time.Millisecond {
+			t.Fatalf("expected retry wait max 400ms, got: %s", client.http.RetryWaitMax)
+		}
+		if client.serverErrorRetryWaitMin != DefaultServerErrorRetryWaitMin {
+			t.Fatalf("expected server error retry wait min %s, got: %s", DefaultServerErrorRetryWaitMin, client.serverErrorRetryWaitMin)
+		}
+		if client.serverErrorRetryWaitMax != DefaultServerErrorRetryWaitMax {
+			t.Fatalf("expected server error retry wait max %s, got: %s", DefaultServerErrorRetryWaitMax, client.serverErrorRetryWaitMax)
+		}
+		if !client.retryServerErrors {
+			t.Fatal("expected server errors to be retried")
+		}
+	})
+
+	t.Run("uses the configured values", func(t *testing.T) {
+		client, err := NewClient(&Config{
+			Address:      ts.URL,
+			Token:        "dummy-token",
+			HTTPClient:   ts.Client(),
+			RetryMax:     Int(3),
+			RetryWaitMin: time.Second,
+			RetryWaitMax: 2 * time.Second,
+
+			ServerErrorRetryWaitMin: time.Second,
+			ServerErrorRetryWaitMax: 2 * time.Second,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if client.http.RetryMax != 3 {
+			t.Fatalf("expected retry max 3, got: %d", client.http.RetryMax)
+		}
+		if client.http.RetryWaitMin != time.Second {
+			t.Fatalf("expected retry wait min 1s, got: %s", client.http.RetryWaitMin)
+		}
+		if client.http.RetryWaitMax != 2*time.Second {
+			t.Fatalf("expected retry wait max 2s, got: %s", client.http.RetryWaitMax)
+		}
+
+		// Server errors back off within the configured bounds.
+		resp := &http.Response{StatusCode: 503}
+		for i := 0; i < 5; i++ {
+			wait := client.retryHTTPBackoff(client.http.RetryWaitMin, client.http.RetryWaitMax, i, resp)
+			if wait < time.Second || wait > 2*time.Second {
+				t.Fatalf("attempt %d expected a wait between 1s and 2s, got: %s", i, wait)
+			}
+		}
+	})
+
+	t.Run("retries server errors by default", func(t *testing.T) {
+		attempts := 0
+		ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(503)
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write([]byte(`{"data": {"id": "foo", "type": "organizations"}}`))
+		})
+		defer ts.Close()
+
+		client, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "dummy-token",
+			HTTPClient: ts.Client(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		org, err := client.Organizations.Read(context.Background(), "foo")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if org.Name != "foo" {
+			t.Fatalf("expected organization %q, got %q", "foo", org.Name)
+		}
+		if attempts != 2 {
+			t.Fatalf("expected 2 attempts, got: %d", attempts)
+		}
+	})
+
+	t.Run("disables retries of server errors", func(t *testing.T) {
+		attempts := 0
+		ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(503)
+		})
+		defer ts.Close()
+
+		client, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "dummy-token",
+			HTTPClient: ts.Client(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.RetryServerErrors(false)
+
+		if _, err := client.Organizations.Read(context.Background(), "foo"); err == nil {
+			t.Fatal("expected an error")
+		}
+		if attempts != 1 {
+			t.Fatalf("expected 1 attempt, got: %d", attempts)
+		}
+	})
+
+	t.Run("disables retries", func(t *testing.T) {
+		attempts := 0
+		ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(429)
+		})
+		defer ts.Close()
+
+		client, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "dummy-token",
+			HTTPClient: ts.Client(),
+			RetryMax:   Int(0),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = client.Organizations.Read(context.Background(), "foo")
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected ErrRateLimited, got: %v", err)
+		}
+		if attempts != 1 {
+			t.Fatalf("expected 1 attempt, got: %d", attempts)
+		}
+	})
+}
+
+func TestClient_exponentialJitterBackoff(t *testing.T) {
+	min := 100 * time.Millisecond
+	max := time.Second
+
+	cases := map[int]struct {
+		lower time.Duration
+		upper time.Duration
+	}{
+		0:  {lower: min, upper: min},
+		1:  {lower: min, upper: 200 * time.Millisecond},
+		2:  {lower: 200 * time.Millisecond, upper: 400 * time.Millisecond},
+		3:  {lower: 400 * time.Millisecond, upper: 800 * time.Millisecond},
+		10: {lower: max / 2, upper: max},
+	}
+
+	for attemptNum, tc := range cases {
+		wait := exponentialJitterBackoff(min, max, attemptNum)
+		if wait < tc.lower || wait > tc.upper {
+			t.Fatalf("attempt %d expected a wait between %s and %s, got: %s", attemptNum, tc.lower, tc.upper, wait)
+		}
+	}
+}
+
+func TestClient_requestContext(t *testing.T) {
+	testedCalls := 0
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		testedCalls++
+
+		// Hang until the client gives up on the request.
+		<-r.Context().Done()
+	})
+	defer ts.Close()
+
+	t.Run("with a canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.Organizations.Read(ctx, "organization")
+		if err != context.Canceled {
+			t.Fatalf("expected %v, got: %v", context.Canceled, err)
+		}
+		if testedCalls != 0 {
+			t.Fatalf("expected no request to be issued, got: %d calls", testedCalls)
+		}
+	})
+
+	t.Run("with a timed out context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Organizations.Read(ctx, "organization")
+		if err != context.DeadlineExceeded {
+			t.Fatalf("expected %v, got: %v", context.DeadlineExceeded, err)
+		}
+	})
+}
+
+func TestClient_rateLimit(t *testing.T) {
+	testedCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testedCalls++
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		if testedCalls == 1 {
+			w.WriteHeader(204) // We query the configured ping URL which should return a 204.
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Remaining", "12")
+		w.Header().Set("X-RateLimit-Reset", "0.5")
+		w.WriteHeader(404)
+	}))
+	defer ts.Close()
+
+	cfg := &Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		HTTPClient: ts.Client(),
+	}
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rl := client.RateLimit(); rl.Limit != 30 || rl.Remaining != 0 {
+		t.Fatalf("unexpected initial rate limit: %+v", rl)
+	}
+
+	before := time.Now()
+
+	_, err = client.Organizations.Read(context.Background(), "organization")
+	if !errors.Is(err, ErrResourceNotFound) {
+		t.Fatalf("expected %v, got: %v", ErrResourceNotFound, err)
+	}
+
+	rl := client.RateLimit()
+	if rl.Limit != 30 {
+		t.Fatalf("expected limit 30, got: %d", rl.Limit)
+	}
+	if rl.Remaining != 12 {
+		t.Fatalf("expected 12 remaining, got: %d", rl.Remaining)
+	}
+	if rl.Reset.Before(before.Add(500*time.Millisecond)) || rl.Reset.After(time.Now().Add(500*time.Millisecond)) {
+		t.Fatalf("unexpected reset time: %s", rl.Reset)
+	}
+}
+
+func TestClient_parseRateLimit(t *testing.T) {
+	now := time.Now()
+
+	cases := map[string]struct {
+		limit     string
+		remaining string
+		reset     string
+		found     bool
+		result    RateLimit
+	}{
+		"missing": {
+			found:  false,
+			result: RateLimit{},
+		},
+		"malformed": {
+			limit:     "thirty",
+			remaining: "-1",
+			reset:     "soon",
+			found:     true,
+			result:    RateLimit{},
+		},
+		"partial": {
+			limit:  "30",
+			found:  true,
+			result: RateLimit{Limit: 30},
+		},
+		"complete": {
+			limit:     "30",
+			remaining: "29",
+			reset:     "0.25",
+			found:     true,
+			result: RateLimit{
+				Limit:     30,
+				Remaining: 29,
+				Reset:     now.Add(250 * time.Millisecond),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		h := make(http.Header)
+		if tc.limit != "" {
+			h.Set("X-RateLimit-Limit", tc.limit)
+		}
+		if tc.remaining != "" {
+			h.Set("X-RateLimit-Remaining", tc.remaining)
+		}
+		if tc.reset != "" {
+			h.Set("X-RateLimit-Reset", tc.reset)
+		}
+
+		result, found := parseRateLimit(h, now)
+		if found != tc.found {
+			t.Fatalf("test %s expected found %t, got: %t", name, tc.found, found)
+		}
+		if result != tc.result {
+			t.Fatalf("test %s expected %+v, got: %+v", name, tc.result, result)
+		}
+	}
+}
+
+func TestClient_checkResponseCode(t *testing.T) {
+	cases := map[string]struct {
+		status int
+		body   string
+		err    error
+	}{
+		"200": {
+			status: 200,
+			err:    nil,
+		},
+		"204": {
+			status: 204,
+			err:    nil,
+		},
+		"401": {
+			status: 401,
+			err:    ErrUnauthorized,
+		},
+		"403": {
+			status: 403,
+			err:    ErrForbidden,
+		},
+		"404": {
+			status: 404,
+			err:    ErrResourceNotFound,
+		},
+		"429": {
+			status: 429,
+			err:    ErrRateLimited,
+		},
+		"500-without-body": {
+			status: 500,
+			err:    ErrUnexpectedStatus,
+		},
+		"502-with-html-body": {
+			status: 502,
+			body:   "<html><body>Bad Gateway</body></html>",
+			err:    ErrUnexpectedStatus,
+		},
+	}
+
+	for name, tc := range cases {
+		resp := testResponse(t, tc.status, tc.body)
+
+		err := checkResponseCode(resp)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("test %s expected %v, got: %v", name, tc.err, err)
+		}
+	}
+}
+
+func TestClient_errorResponse(t *testing.T) {
+	t.Run("with error objects", func(t *testing.T) {
+		resp := testResponse(t, 422, `{
+			"errors": [
+				{
+					"status": "422",
+					"title": "invalid attribute",
+					"detail": "Name has already been taken",
+					"source": {"pointer": "/data/attributes/name"}
+				},
+				{
+					"status": "422",
+					"title": "invalid attribute"
+				}
+			]
+		}`)
+
+		err := checkResponseCode(resp)
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) {
+			t.Fatalf("expected an *ErrorResponse, got: %T", err)
+		}
+		if len(errResp.Errors) != 2 {
+			t.Fatalf("expected 2 errors, got: %d", len(errResp.Errors))
+		}
+		if errResp.Errors[0].Source == nil || errResp.Errors[0].Source.Pointer != "/data/attributes/name" {
+			t.Fatalf("unexpected error source: %+v", errResp.Errors[0].Source)
+		}
+		if errResp.Errors[1].Source != nil {
+			t.Fatalf("expected no error source, got: %+v", errResp.Errors[1].Source)
+		}
+
+		expected := "invalid attribute\n\nName has already been taken\ninvalid attribute"
+		if err.Error() != expected {
+			t.Fatalf("expected error %q, got: %q", expected, err.Error())
+		}
+	})
+
+	t.Run("with plain string errors", func(t *testing.T) {
+		resp := testResponse(t, 422, `{"errors": ["Ssh key is invalid"]}`)
+
+		err := checkResponseCode(resp)
+		if err == nil || err.Error() != "Ssh key is invalid" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("with an empty errors list", func(t *testing.T) {
+		resp := testResponse(t, 422, `{"errors": []}`)
+
+		err := checkResponseCode(resp)
+		if !errors.Is(err, ErrUnexpectedStatus) {
+			t.Fatalf("expected %v, got: %v", ErrUnexpectedStatus, err)
+		}
+	})
+
+	t.Run("with the request details and raw body", func(t *testing.T) {
+		resp := testResponse(t, 502, "<html>Bad Gateway</html>")
+		resp.Request.Method = "POST"
+
+		// The response details should be available even when wrapped.
+		err := fmt.Errorf("error creating run: %w", checkResponseCode(resp))
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) {
+			t.Fatalf("expected an *ErrorResponse, got: %T", err)
+		}
+		if errResp.StatusCode != 502 || errResp.Method != "POST" || errResp.Path != "/api/v2/ping" {
+			t.Fatalf("unexpected request details: %d %s %s", errResp.StatusCode, errResp.Method, errResp.Path)
+		}
+		if string(errResp.Body) != "<html>Bad Gateway</html>" {
+			t.Fatalf("unexpected body: %q", errResp.Body)
+		}
+	})
+
+	t.Run("with a large body", func(t *testing.T) {
+		resp := testResponse(t, 500, strings.Repeat("x", maxErrorBodySize+1))
+
+		var errResp *ErrorResponse
+		if !errors.As(checkResponseCode(resp), &errResp) {
+			t.Fatal("expected an *ErrorResponse")
+		}
+		if len(errResp.Body) != maxErrorBodySize {
+			t.Fatalf("expected the body to be limited to %d bytes, got: %d", maxErrorBodySize, len(errResp.Body))
+		}
+	})
+}
+
+func TestClient_listPagination(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if v := r.URL.Query().Get("page[number]"); v != "2" {
+			t.Fatalf("unexpected page number: %q", v)
+		}
+		if v := r.URL.Query().Get("page[size]"); v != "1" {
+			t.Fatalf("unexpected page size: %q", v)
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{
+			"data": [
+				{"id": "ws-2", "type": "workspaces", "attributes": {"name": "workspace-2"}}
+			],
+			"meta": {
+				"pagination": {
+					"current-page": 2,
+					"prev-page": 1,
+					"next-page": 3,
+					"total-pages": 3,
+					"total-count": 3
+				}
+			}
+		}`))
+	})
+	defer ts.Close()
+
+	wl, err := client.Workspaces.List(context.Background(), "organization", WorkspaceListOptions{
+		ListOptions: ListOptions{
+			PageNumber: 2,
+			PageSize:   1,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(wl.Items) != 1 || wl.Items[0].Name != "workspace-2" {
+		t.Fatalf("unexpected items: %+v", wl.Items)
+	}
+
+	expected := Pagination{
+		CurrentPage:  2,
+		PreviousPage: 1,
+		NextPage:     3,
+		TotalPages:   3,
+		TotalCount:   3,
+	}
+	if *wl.Pagination != expected {
+		t.Fatalf("expected pagination %+v, got: %+v", expected, *wl.Pagination)
+	}
+}
+
+func TestForEachPage(t *testing.T) {
+	t.Run("iterates all pages", func(t *testing.T) {
+		var pages []int
+		err := ForEachPage(ListOptions{PageSize: 50}, func(lo ListOptions) (*Pagination, error) {
+			if lo.PageSize != 50 {
+				t.Fatalf("expected page size 50, got: %d", lo.PageSize)
+			}
+			pages = append(pages, lo.PageNumber)
+
+			p := &Pagination{CurrentPage: lo.PageNumber, TotalPages: 3}
+			if lo.PageNumber < 3 {
+				p.NextPage = lo.PageNumber + 1
+			}
+			return p, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(pages) != "[1 2 3]" {
+			t.Fatalf("unexpected pages: %v", pages)
+		}
+	})
+
+	t.Run("stops when the callback fails", func(t *testing.T) {
+		calls := 0
+		fnErr := errors.New("list error")
+
+		err := ForEachPage(ListOptions{}, func(lo ListOptions) (*Pagination, error) {
+			calls++
+			if lo.PageNumber == 2 {
+				return nil, fnErr
+			}
+			return &Pagination{CurrentPage: lo.PageNumber, NextPage: lo.PageNumber + 1}, nil
+		})
+		if err != fnErr {
+			t.Fatalf("expected %v, got: %v", fnErr, err)
+		}
+		if calls != 2 {
+			t.Fatalf("expected 2 calls, got: %d", calls)
+		}
+	})
+
+	t.Run("stops when the next page does not advance", func(t *testing.T) {
+		calls := 0
+		err := ForEachPage(ListOptions{}, func(lo ListOptions) (*Pagination, error) {
+			calls++
+			return &Pagination{CurrentPage: 1, NextPage: 1}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got: %d", calls)
+		}
+	})
+
+	t.Run("stops after the maximum number of pages", func(t *testing.T) {
+		calls := 0
+		err := ForEachPage(ListOptions{}, func(lo ListOptions) (*Pagination, error) {
+			calls++
+			return &Pagination{CurrentPage: lo.PageNumber, NextPage: lo.PageNumber + 1}, nil
+		})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if calls != maxPages {
+			t.Fatalf("expected %d calls, got: %d", maxPages, calls)
+		}
+	})
+}
+
+func TestClient_include(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if v := r.URL.Query().Get("include"); v != "current_run,current_run.plan" {
+			t.Fatalf("unexpected include value: %q", v)
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{
+			"data": [
+				{
+					"id": "ws-1",
+					"type": "workspaces",
+					"attributes": {"name": "workspace-1"},
+					"relationships": {
+						"current-run": {"data": {"id": "run-1", "type": "runs"}}
+					}
+				}
+			],
+			"included": [
+				{
+					"id": "run-1",
+					"type": "runs",
+					"attributes": {"status": "planned"},
+					"relationships": {
+						"plan": {"data": {"id": "plan-1", "type": "plans"}}
+					}
+				},
+				{
+					"id": "plan-1",
+					"type": "plans",
+					"attributes": {"has-changes": true}
+				}
+			],
+			"meta": {"pagination": {"current-page": 1}}
+		}`))
+	})
+	defer ts.Close()
+
+	wl, err := client.Workspaces.List(context.Background(), "organization", WorkspaceListOptions{
+		Include: []WSIncludeOpt{WSCurrentRun, WSCurrentRunPlan},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(wl.Items) != 1 {
+		t.Fatalf("expected 1 workspace, got: %d", len(wl.Items))
+	}
+
+	r := wl.Items[0].CurrentRun
+	if r == nil || r.Status != RunPlanned {
+		t.Fatalf("expected the included current run, got: %+v", r)
+	}
+	if r.Plan == nil || !r.Plan.HasChanges {
+		t.Fatalf("expected the included plan, got: %+v", r.Plan)
+	}
+}
+
+func TestClient_fieldsets(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expected := "fields%5Bruns%5D=status&fields%5Bworkspaces%5D=name%2Ccurrent-run&include=current_run"
+		if r.URL.RawQuery != expected {
+			t.Fatalf("expected query %q, got: %q", expected, r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{
+			"data": [
+				{"id": "ws-1", "type": "workspaces", "attributes": {"name": "workspace-1"}}
+			],
+			"meta": {"pagination": {"current-page": 1}}
+		}`))
+	})
+	defer ts.Close()
+
+	wl, err := client.Workspaces.List(context.Background(), "organization", WorkspaceListOptions{
+		Include: []WSIncludeOpt{WSCurrentRun},
+		Fields: Fieldsets{
+			"workspaces": {"name", "current-run"},
+			"runs":       {"status"},
+			"plans":      {},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(wl.Items) != 1 || wl.Items[0].Name != "workspace-1" {
+		t.Fatalf("unexpected items: %+v", wl.Items)
+	}
+	if wl.Items[0].Permissions != nil || wl.Items[0].TerraformVersion != "" {
+		t.Fatalf("expected the omitted attributes to be empty, got: %+v", wl.Items[0])
+	}
+}
+
+func TestClient_responseBodyClosed(t *testing.T) {
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		switch r.URL.Path {
+		case "/api/v2/organizations/object":
+			w.Write([]byte(`{"data": {"id": "object", "type": "organizations"}}`))
+		case "/api/v2/organizations":
+			w.Write([]byte(`{"data": [{"id": "list", "type": "organizations"}], "meta": {}}`))
+		case "/api/v2/organizations/invalid":
+			w.Write([]byte(`{"data": "invalid"`))
+		case "/api/v2/organizations/errored":
+			w.WriteHeader(422)
+			w.Write([]byte(`{"errors": [{"title": "invalid attribute"}]}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	transport := &closeCountingTransport{transport: ts.Client().Transport}
+
+	client, err := NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		fn      func() error
+		success bool
+	}{
+		"object-decode": {
+			fn: func() error {
+				_, err := client.Organizations.Read(ctx, "object")
+				return err
+			},
+			success: true,
+		},
+		"list-decode": {
+			fn: func() error {
+				_, err := client.Organizations.List(ctx, OrganizationListOptions{})
+				return err
+			},
+			success: true,
+		},
+		"decode-failure": {
+			fn: func() error {
+				_, err := client.Organizations.Read(ctx, "invalid")
+				return err
+			},
+			success: false,
+		},
+		"error-response": {
+			fn: func() error {
+				_, err := client.Organizations.Read(ctx, "errored")
+				return err
+			},
+			success: false,
+		},
+		"not-found": {
+			fn: func() error {
+				_, err := client.Organizations.Read(ctx, "unknown")
+				return err
+			},
+			success: false,
+		},
+	}
+
+	for name, tc := range cases {
+		transport.reset()
+
+		err := tc.fn()
+		if tc.success && err != nil {
+			t.Fatalf("test %s unexpected error: %v", name, err)
+		}
+		if !tc.success && err == nil {
+			t.Fatalf("test %s expected an error", name)
+		}
+
+		if opened, closed := transport.counts(); opened != 1 || closed != 1 {
+			t.Fatalf("test %s expected 1 opened and 1 closed body, got: %d opened and %d closed", name, opened, closed)
+		}
+	}
+}
+
+func TestClient_doUpload(t *testing.T) {
+	uploads := 0
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Fatalf("expected method %q, got %q", "PUT", r.Method)
+		}
+		if v := r.Header.Get("Authorization"); v != "" {
+			t.Fatalf("expected no Authorization header, got %q", v)
+		}
+		if v := r.Header.Get("Content-Type"); v != "application/octet-stream" {
+			t.Fatalf("expected Content-Type %q, got %q", "application/octet-stream", v)
+		}
+		if v := r.Header.Get("X-Custom"); v != "custom" {
+			t.Fatalf("expected X-Custom %q, got %q", "custom", v)
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != "content" {
+			t.Fatalf("expected body %q, got %q", "content", body)
+		}
+		if r.ContentLength != int64(len("content")) {
+			t.Fatalf("expected content length %d, got %d", len("content"), r.ContentLength)
+		}
+
+		switch r.URL.Path {
+		case "/flaky":
+			uploads++
+			if uploads == 1 {
+				w.WriteHeader(503)
+				return
+			}
+			w.WriteHeader(200)
+		case "/denied":
+			w.WriteHeader(403)
+			w.Write([]byte("signature expired\n"))
+		default:
+			w.WriteHeader(200)
+		}
+	})
+	defer ts.Close()
+
+	headers := make(http.Header)
+	headers.Set("Authorization", "Bearer dummy-token")
+	headers.Set("X-Custom", "custom")
+
+	client, err := NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		Headers:    headers,
+		HTTPClient: ts.Client(),
+
+		ServerErrorRetryWaitMin: time.Millisecond,
+		ServerErrorRetryWaitMax: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	t.Run("without the auth header", func(t *testing.T) {
+		if err := client.doUpload(ctx, ts.URL+"/upload", bytes.NewBufferString("content")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("retries with a seekable body", func(t *testing.T) {
+		if err := client.doUpload(ctx, ts.URL+"/flaky", strings.NewReader("content")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if uploads != 2 {
+			t.Fatalf("expected 2 uploads, got %d", uploads)
+		}
+	})
+
+	t.Run("with a plain text error", func(t *testing.T) {
+		err := client.doUpload(ctx, ts.URL+"/denied", strings.NewReader("content"))
+		if !errors.Is(err, ErrUnexpectedStatus) {
+			t.Fatalf("expected ErrUnexpectedStatus, got %v", err)
+		}
+		if !strings.HasSuffix(err.Error(), "403 Forbidden: signature expired") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClient_doDownload(t *testing.T) {
+	archivist := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get("Authorization"); v != "" {
+			t.Fatalf("expected no Authorization header, got %q", v)
+		}
+		w.Write([]byte("archivist content"))
+	}))
+	defer archivist.Close()
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get("Authorization"); v != "Bearer dummy-token" {
+			t.Fatalf("expected Authorization header %q, got %q", "Bearer dummy-token", v)
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/content":
+			w.Write([]byte("api content"))
+		case "/api/v2/redirect":
+			http.Redirect(w, r, archivist.URL+"/object", http.StatusTemporaryRedirect)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	cases := map[string]struct {
+		url     string
+		content string
+		err     error
+	}{
+		"relative-url": {
+			url:     "content",
+			content: "api content",
+		},
+		"same-host": {
+			url:     ts.URL + "/api/v2/content",
+			content: "api content",
+		},
+		"cross-host": {
+			url:     archivist.URL + "/object",
+			content: "archivist content",
+		},
+		"cross-host-redirect": {
+			url:     "redirect",
+			content: "archivist content",
+		},
+		"not-found": {
+			url: "missing",
+			err: ErrResourceNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			body, err := client.doDownload(ctx, tc.url)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			defer body.Close()
+
+			content, err := ioutil.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tc.content {
+				t.Fatalf("expected content %q, got %q", tc.content, content)
+			}
+		})
+	}
+}
+
+func TestClient_hooks(t *testing.T) {
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		switch r.URL.Path {
+		case "/api/v2/organizations":
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil || len(body) == 0 {
+				t.Fatalf("expected a request body, got %q (%v)", body, err)
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"data": {"id": "hooked", "type": "organizations"}}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	var requests []*http.Request
+	var responses []*http.Response
+
+	client, err := NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		HTTPClient: ts.Client(),
+		RequestHook: func(req *http.Request) {
+			if req.Body != nil {
+				t.Fatal("expected the request body to be unavailable")
+			}
+			requests = append(requests, req)
+		},
+		ResponseHook: func(resp *http.Response, duration time.Duration) {
+			if duration <= 0 {
+				t.Fatalf("expected a positive duration, got %s", duration)
+			}
+			responses = append(responses, resp)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	org, err := client.Organizations.Create(context.Background(), OrganizationCreateOptions{
+		Name:  String("hooked"),
+		Email: String("hooked@example.com"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if org.Name != "hooked" {
+		t.Fatalf("expected the response body to be decoded, got %q", org.Name)
+	}
+
+	if len(requests) != 1 || len(responses) != 1 {
+		t.Fatalf("expected 1 request and response, got %d and %d", len(requests), len(responses))
+	}
+
+	req := requests[0]
+	if req.Method != "POST" || req.URL.Path != "/api/v2/organizations" {
+		t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+	}
+	if v := req.Header.Get("Authorization"); v != "REDACTED" {
+		t.Fatalf("expected a redacted Authorization header, got %q", v)
+	}
+
+	resp := responses[0]
+	if resp.StatusCode != 201 {
+		t.Fatalf("expected status code 201, got %d", resp.StatusCode)
+	}
+	if v := resp.Request.Header.Get("Authorization"); v != "REDACTED" {
+		t.Fatalf("expected a redacted Authorization header, got %q", v)
+	}
+	if body, _ := ioutil.ReadAll(resp.Body); len(body) != 0 {
+		t.Fatalf("expected the response body to be unavailable, got %q", body)
+	}
+}
+
+func TestClient_retryLogHook(t *testing.T) {
+	attempts := 0
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		// Make sure the body is rewound for every attempt.
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil || !strings.Contains(string(body), "flaky@example.com") {
+			t.Fatalf("expected the request body on attempt %d, got %q (%v)", attempts, body, err)
+		}
+
+		attempts++
+		if attempts <= 2 {
+			w.WriteHeader(503)
+			return
+		}
+		w.Write([]byte(`{"data": {"id": "flaky", "type": "organizations"}}`))
+	})
+	defer ts.Close()
+
+	// Use a custom transport to verify the HTTP client is wrapped.
+	transport := &closeCountingTransport{transport: ts.Client().Transport}
+
+	var retries []int
+	client, err := NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		HTTPClient: &http.Client{Transport: transport},
+		RetryLogHook: func(attemptNum int, resp *http.Response) {
+			if resp == nil || resp.StatusCode != 503 {
+				t.Fatalf("expected a 503 response, got %v", resp)
+			}
+			retries = append(retries, attemptNum)
+		},
+		ServerErrorRetryWaitMin: time.Millisecond,
+		ServerErrorRetryWaitMax: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport.reset()
+
+	// Updates are only retried on server errors when marked as retryable.
+	req, err := client.newRequest("PATCH", "organizations/flaky", &OrganizationUpdateOptions{
+		Email: String("flaky@example.com"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	org := &Organization{}
+	err = client.do(context.Background(), withRetryable(req), org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if org.Name != "flaky" {
+		t.Fatalf("expected organization %q, got %q", "flaky", org.Name)
+	}
+
+	if len(retries) != 2 || retries[0] != 0 || retries[1] != 1 {
+		t.Fatalf("expected the hook to observe 2 retries, got %v", retries)
+	}
+	if opened, _ := transport.counts(); opened != 3 {
+		t.Fatalf("expected 3 requests using the custom transport, got %d", opened)
+	}
+}
+
+func TestClient_requestTimeout(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		w.WriteHeader(201)
+		w.Write([]byte(`{"data": {"id": "run-slow", "type": "runs"}}`))
+	})
+	defer ts.Close()
+
+	cases := map[string]struct {
+		ctxTimeout time.Duration
+		timeout    time.Duration
+		err        error
+	}{
+		"without-timeout": {},
+		"with-long-timeout": {
+			timeout: time.Minute,
+		},
+		"with-short-timeout": {
+			timeout: 50 * time.Millisecond,
+			err:     context.DeadlineExceeded,
+		},
+		"with-earlier-context-deadline": {
+			ctxTimeout: 50 * time.Millisecond,
+			timeout:    time.Minute,
+			err:        context.DeadlineExceeded,
+		},
+		"with-later-context-deadline": {
+			ctxTimeout: time.Minute,
+			timeout:    50 * time.Millisecond,
+			err:        context.DeadlineExceeded,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.ctxTimeout)
+				defer cancel()
+			}
+
+			r, err := client.Runs.Create(ctx, RunCreateOptions{
+				Workspace: &Workspace{ID: "ws-slow"},
+				Timeout:   tc.timeout,
+			})
+			if err != tc.err {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if err == nil && r.ID != "run-slow" {
+				t.Fatalf("expected run %q, got %q", "run-slow", r.ID)
+			}
+		})
+	}
+
+	t.Run("only-affects-the-request-with-the-timeout", func(t *testing.T) {
+		ctx := context.Background()
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i, timeout := range []time.Duration{50 * time.Millisecond, 0} {
+			wg.Add(1)
+			go func(i int, timeout time.Duration) {
+				defer wg.Done()
+				_, errs[i] = client.Runs.Create(ctx, RunCreateOptions{
+					Workspace: &Workspace{ID: "ws-slow"},
+					Timeout:   timeout,
+				})
+			}(i, timeout)
+		}
+		wg.Wait()
+
+		if errs[0] != context.DeadlineExceeded {
+			t.Fatalf("expected error %v, got %v", context.DeadlineExceeded, errs[0])
+		}
+		if errs[1] != nil {
+			t.Fatalf("expected the request without a timeout to succeed, got %v", errs[1])
+		}
+	})
+}
+
+func TestClient_remoteAPIVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.Header().Set("TFP-API-Version", "2.3")
+			w.WriteHeader(204) // We query the configured ping URL which should return a 204.
+		case "/api/v2/organizations/cloud":
+			w.Header().Set("TFP-API-Version", "2.4")
+			w.Header().Set("TFP-AppName", "Terraform Cloud")
+			w.Write([]byte(`{"data": {"id": "cloud", "type": "organizations"}}`))
+		default:
+			w.Write([]byte(`{"data": {"id": "unversioned", "type": "organizations"}}`))
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		HTTPClient: ts.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if v := client.RemoteAPIVersion(); v != "2.3" {
+		t.Fatalf("expected version %q after the ping, got %q", "2.3", v)
+	}
+	if client.IsCloud() {
+		t.Fatal("expected the client not to talk to Terraform Cloud")
+	}
+
+	// Responses without a version do not reset the version.
+	if _, err := client.Organizations.Read(ctx, "unversioned"); err != nil {
+		t.Fatal(err)
+	}
+	if v := client.RemoteAPIVersion(); v != "2.3" {
+		t.Fatalf("expected version %q, got %q", "2.3", v)
+	}
+
+	if _, err := client.Organizations.Read(ctx, "cloud"); err != nil {
+		t.Fatal(err)
+	}
+	if v := client.RemoteAPIVersion(); v != "2.4" {
+		t.Fatalf("expected version %q, got %q", "2.4", v)
+	}
+	if !client.IsCloud() {
+		t.Fatal("expected the client to talk to Terraform Cloud")
+	}
+
+	// A fake version is not updated by responses.
+	client.SetFakeRemoteAPIVersion("2.0")
+	if _, err := client.Organizations.Read(ctx, "cloud"); err != nil {
+		t.Fatal(err)
+	}
+	if v := client.RemoteAPIVersion(); v != "2.0" {
+		t.Fatalf("expected fake version %q, got %q", "2.0", v)
+	}
+
+	t.Run("with the default address", func(t *testing.T) {
+		baseURL, err := url.Parse(DefaultAddress + DefaultBasePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &Client{baseURL: baseURL}
+		if !client.IsCloud() {
+			t.Fatal("expected the client to talk to Terraform Cloud")
+		}
+	})
+
+	t.Run("when comparing versions", func(t *testing.T) {
+		cases := []struct {
+			remote string
+			before bool
+		}{
+			{"2.5", true},
+			{"1.9", true},
+			{"2.6", false},
+			{"2.10", false},
+			{"3.0", false},
+			{"", false},
+			{"unknown", false},
+		}
+		for _, c := range cases {
+			client := &Client{remoteAPIVersion: c.remote}
+			if got := client.remoteAPIVersionBefore("2.6"); got != c.before {
+				t.Errorf("expected %q before 2.6 to be %v, got %v", c.remote, c.before, got)
+			}
+		}
+	})
+}
+
+func TestClient_ping(t *testing.T) {
+	var status int
+	var contentType, body string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		if status == 0 {
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.WriteHeader(204) // We query the configured ping URL which should return a 204.
+			return
+		}
+
+		if r.URL.Path != "/api/v2/ping" {
+			t.Fatalf("expected the ping endpoint, got %q", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		HTTPClient: ts.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		status      int
+		contentType string
+		body        string
+		err         error
+		notTFE      bool
+	}{
+		"no-content": {
+			status:      204,
+			contentType: "application/vnd.api+json",
+		},
+		"ok": {
+			status:      200,
+			contentType: "application/json; charset=utf-8",
+			body:        `{}`,
+		},
+		"unauthorized": {
+			status:      401,
+			contentType: "application/vnd.api+json",
+			body:        `{"errors": [{"status": "401", "title": "unauthorized"}]}`,
+			err:         ErrUnauthorized,
+		},
+		"html-page": {
+			status:      200,
+			contentType: "text/html; charset=utf-8",
+			body:        "<html><body>Welcome!</body></html>",
+			notTFE:      true,
+		},
+		"html-not-found": {
+			status:      404,
+			contentType: "text/html",
+			body:        "<html><body>Not Found</body></html>",
+			notTFE:      true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			status, contentType, body = tc.status, tc.contentType, tc.body
+
+			err := client.Ping(context.Background())
+			switch {
+			case tc.notTFE:
+				if err == nil || !strings.Contains(err.Error(), "does not appear to be a Terraform Enterprise API") {
+					t.Fatalf("expected a descriptive error, got %v", err)
+				}
+			case tc.err != nil:
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expected error %v, got %v", tc.err, err)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_emptyResponse(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		switch r.URL.Path {
+		case "/api/v2/no-content":
+			w.WriteHeader(204)
+		case "/api/v2/accepted":
+			w.WriteHeader(202)
+		case "/api/v2/accepted-chunked":
+			// Flush the headers so the length of the body is unknown.
+			w.WriteHeader(202)
+			w.(http.Flusher).Flush()
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	for _, path := range []string{"no-content", "accepted", "accepted-chunked"} {
+		t.Run(path, func(t *testing.T) {
+			req, err := client.newRequest("POST", path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			org := &Organization{Name: "untouched"}
+			if err := client.do(context.Background(), req, org); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if org.Name != "untouched" {
+				t.Fatalf("expected the output to be untouched, got %q", org.Name)
+			}
+
+			req, err = client.newRequest("GET", path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ol := &OrganizationList{}
+			if err := client.do(context.Background(), req, ol); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ol.Items != nil || ol.Pagination != nil {
+				t.Fatalf("expected the output to be untouched, got %+v", ol)
+			}
+		})
+	}
+}
+
+func TestClient_retryAfter(t *testing.T) {
+	var attempts []time.Time
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		switch r.URL.Path {
+		case "/api/v2/organizations/limited":
+			attempts = append(attempts, time.Now())
+			if len(attempts) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(429)
+				return
+			}
+			w.Write([]byte(`{"data": {"id": "limited", "type": "organizations"}}`))
+		default:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(429)
+		}
+	})
+	defer ts.Close()
+
+	t.Run("waits before retrying", func(t *testing.T) {
+		if _, err := client.Organizations.Read(context.Background(), "limited"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(attempts) != 2 {
+			t.Fatalf("expected 2 attempts, got %d", len(attempts))
+		}
+		if wait := attempts[1].Sub(attempts[0]); wait < time.Second {
+			t.Fatalf("expected to wait at least 1s before retrying, waited %s", wait)
+		}
+	})
+
+	t.Run("exposes the time to wait", func(t *testing.T) {
+		// Disable retries, so the rate limited response is returned.
+		client.http.RetryMax = 0
+
+		_, err := client.Organizations.Read(context.Background(), "exhausted")
+		if !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected ErrRateLimited, got %v", err)
+		}
+
+		var rlErr *RateLimitError
+		if !errors.As(err, &rlErr) || rlErr.RetryAfter != 2*time.Second {
+			t.Fatalf("expected a RateLimitError with RetryAfter 2s, got %#v", err)
+		}
+	})
+
+	t.Run("caps the time to wait", func(t *testing.T) {
+		client.retryAfterMax = 3 * time.Second
+
+		resp := &http.Response{StatusCode: 429, Header: make(http.Header)}
+		resp.Header.Set("Retry-After", "3600")
+
+		if wait := client.retryHTTPBackoff(time.Second, time.Second, 0, resp); wait != 3*time.Second {
+			t.Fatalf("expected to wait 3s, got %s", wait)
+		}
+	})
+}
+
+func TestClient_parseRetryAfter(t *testing.T) {
+	now := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		value string
+		wait  time.Duration
+		ok    bool
+	}{
+		"missing": {},
+		"seconds": {
+			value: "2",
+			wait:  2 * time.Second,
+			ok:    true,
+		},
+		"zero": {
+			value: "0",
+			ok:    true,
+		},
+		"negative": {
+			value: "-1",
+		},
+		"date": {
+			value: "Fri, 01 Mar 2019 12:00:30 GMT",
+			wait:  30 * time.Second,
+			ok:    true,
+		},
+		"past-date": {
+			value: "Fri, 01 Mar 2019 11:00:00 GMT",
+			ok:    true,
+		},
+		"malformed": {
+			value: "soon",
+		},
+	}
+
+	for name, tc := range cases {
+		h := make(http.Header)
+		if tc.value != "" {
+			h.Set("Retry-After", tc.value)
+		}
+
+		wait, ok := parseRetryAfter(h, now)
+		if wait != tc.wait || ok != tc.ok {
+			t.Fatalf("test %s expected %s (%t), got %s (%t)", name, tc.wait, tc.ok, wait, ok)
+		}
+	}
+}
+
+func TestClient_requestID(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+		w.Header().Set("X-Request-Id", "req-"+strings.TrimPrefix(r.URL.Path, "/api/v2/organizations/"))
+		w.Header().Set("Set-Cookie", "session=secret")
+
+		switch r.URL.Path {
+		case "/api/v2/organizations/ok":
+			w.Write([]byte(`{"data": {"id": "ok", "type": "organizations"}}`))
+		case "/api/v2/organizations/invalid":
+			w.WriteHeader(422)
+			w.Write([]byte(`{"errors": [{"status": "422", "title": "invalid attribute"}]}`))
+		default:
+			w.WriteHeader(500)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("on success", func(t *testing.T) {
+		if _, err := client.Organizations.Read(ctx, "ok"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v := client.LastRequestID(); v != "req-ok" {
+			t.Fatalf("expected request ID %q, got %q", "req-ok", v)
+		}
+	})
+
+	t.Run("with error details", func(t *testing.T) {
+		_, err := client.Organizations.Read(ctx, "invalid")
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) {
+			t.Fatalf("expected an ErrorResponse, got %v", err)
+		}
+		if errResp.StatusCode != 422 || errResp.RequestID != "req-invalid" {
+			t.Fatalf("unexpected status code %d or request ID %q", errResp.StatusCode, errResp.RequestID)
+		}
+		if err.Error() != "invalid attribute" {
+			t.Fatalf("unexpected error message: %q", err.Error())
+		}
+		if errors.Is(err, ErrUnexpectedStatus) {
+			t.Fatal("expected the error not to wrap ErrUnexpectedStatus")
+		}
+		if v := client.LastRequestID(); v != "req-invalid" {
+			t.Fatalf("expected request ID %q, got %q", "req-invalid", v)
+		}
+	})
+
+	t.Run("without error details", func(t *testing.T) {
+		_, err := client.Organizations.Read(ctx, "broken")
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) {
+			t.Fatalf("expected an ErrorResponse, got %v", err)
+		}
+		if !errors.Is(err, ErrUnexpectedStatus) {
+			t.Fatal("expected the error to wrap ErrUnexpectedStatus")
+		}
+
+		expected := "unexpected status code: 500 Internal Server Error (request ID: req-broken)"
+		if err.Error() != expected {
+			t.Fatalf("expected error %q, got %q", expected, err.Error())
+		}
+		if v := errResp.Header.Get("X-Request-Id"); v != "req-broken" {
+			t.Fatalf("expected the X-Request-Id header, got %q", v)
+		}
+		if v := errResp.Header.Get("Set-Cookie"); v != "" {
+			t.Fatalf("expected the Set-Cookie header to be omitted, got %q", v)
+		}
+	})
+}
+
+func TestClient_authErrors(t *testing.T) {
+	cases := map[string]struct {
+		status    int
+		body      string
+		requestID string
+		err       error
+		notErr    error
+		message   string
+	}{
+		"401-without-body": {
+			status:  401,
+			err:     ErrUnauthorized,
+			notErr:  ErrForbidden,
+			message: "unauthorized",
+		},
+		"401-with-title": {
+			status:  401,
+			body:    `{"errors": [{"status": "401", "title": "unauthorized"}]}`,
+			err:     ErrUnauthorized,
+			notErr:  ErrForbidden,
+			message: "unauthorized",
+		},
+		"401-with-detail": {
+			status:  401,
+			body:    `{"errors": [{"status": "401", "title": "unauthorized", "detail": "Token has expired"}]}`,
+			err:     ErrUnauthorized,
+			notErr:  ErrForbidden,
+			message: "unauthorized: Token has expired",
+		},
+		"403-with-detail": {
+			status:  403,
+			body:    `{"errors": [{"status": "403", "title": "forbidden", "detail": "Insufficient permissions"}]}`,
+			err:     ErrForbidden,
+			notErr:  ErrUnauthorized,
+			message: "forbidden: Insufficient permissions",
+		},
+		"403-with-plain-strings": {
+			status:  403,
+			body:    `{"errors": ["You are not allowed to manage teams"]}`,
+			err:     ErrForbidden,
+			notErr:  ErrUnexpectedStatus,
+			message: "forbidden: You are not allowed to manage teams",
+		},
+		"401-with-request-id": {
+			status:    401,
+			body:      `{"errors": [{"status": "401", "title": "unauthorized", "detail": "Token has expired"}]}`,
+			requestID: "req-123",
+			err:       ErrUnauthorized,
+			notErr:    ErrForbidden,
+			message:   "unauthorized: Token has expired (request ID: req-123)",
+		},
+		"404-without-body": {
+			status:  404,
+			err:     ErrResourceNotFound,
+			notErr:  ErrUnexpectedStatus,
+			message: "resource not found",
+		},
+		"404-with-request-id": {
+			status:    404,
+			body:      `{"errors": [{"status": "404", "title": "not found"}]}`,
+			requestID: "req-123",
+			err:       ErrResourceNotFound,
+			notErr:    ErrUnexpectedStatus,
+			message:   "resource not found (request ID: req-123)",
+		},
+	}
+
+	for name, tc := range cases {
+		resp := testResponse(t, tc.status, tc.body)
+		if tc.requestID != "" {
+			resp.Header.Set(headerRequestID, tc.requestID)
+		}
+
+		err := checkResponseCode(resp)
+		if !errors.Is(err, tc.err) {
+			t.Fatalf("test %s expected %v, got: %v", name, tc.err, err)
+		}
+		if errors.Is(err, tc.notErr) {
+			t.Fatalf("test %s expected the error not to be %v", name, tc.notErr)
+		}
+		if err.Error() != tc.message {
+			t.Fatalf("test %s expected message %q, got: %q", name, tc.message, err.Error())
+		}
+
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) || errResp.StatusCode != tc.status {
+			t.Fatalf("test %s expected an ErrorResponse with status %d, got: %#v", name, tc.status, err)
+		}
+		if errResp.RequestID != tc.requestID {
+			t.Fatalf("test %s expected request ID %q, got: %q", name, tc.requestID, errResp.RequestID)
+		}
+	}
+}
+
+func TestClient_queryEncoding(t *testing.T) {
+	baseURL, err := url.Parse(DefaultAddress + DefaultBasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{baseURL: baseURL, headers: make(http.Header)}
+
+	type testOptions struct {
+		ListOptions
+		Search    *string         `url:"search[name],omitempty"`
+		Workspace *string         `url:"filter[workspace][name],omitempty"`
+		Statuses  []string        `url:"filter[status],omitempty"`
+		Include   []RunIncludeOpt `url:"include,omitempty,comma"`
+	}
+
+	cases := map[string]struct {
+		options interface{}
+		query   string
+	}{
+		"zero-values": {
+			options: testOptions{},
+			query:   "",
+		},
+		"bracketed-params": {
+			options: testOptions{
+				ListOptions: ListOptions{PageNumber: 2, PageSize: 50},
+				Search:      String("my-ws"),
+				Workspace:   String("my-ws"),
+			},
+			query: "filter%5Bworkspace%5D%5Bname%5D=my-ws&page%5Bnumber%5D=2&page%5Bsize%5D=50&search%5Bname%5D=my-ws",
+		},
+		"repeated-slice": {
+			options: testOptions{Statuses: []string{"planned", "applied"}},
+			query:   "filter%5Bstatus%5D=planned&filter%5Bstatus%5D=applied",
+		},
+		"comma-joined-slice": {
+			options: testOptions{Include: []RunIncludeOpt{RunPlan, RunApply}},
+			query:   "include=plan%2Capply",
+		},
+		"empty-include": {
+			options: OrganizationMembershipListOptions{},
+			query:   "",
+		},
+	}
+
+	for name, tc := range cases {
+		req, err := client.newRequest("GET", "things", tc.options)
+		if err != nil {
+			t.Fatalf("test %s unexpected error: %v", name, err)
+		}
+		if req.URL.RawQuery != tc.query {
+			t.Fatalf("test %s expected query %q, got: %q", name, tc.query, req.URL.RawQuery)
+		}
+	}
+}
+
+func TestClient_checkRedirect(t *testing.T) {
+	hops := make(map[string]string)
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops["other"+r.URL.Path] = r.Header.Get("Authorization")
+		w.Write([]byte("other"))
+	}))
+	defer other.Close()
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		hops[r.URL.Path] = r.Header.Get("Authorization")
+
+		switch r.URL.Path {
+		case "/api/v2/same-host":
+			http.Redirect(w, r, "/api/v2/target", http.StatusTemporaryRedirect)
+		case "/api/v2/cross-host":
+			http.Redirect(w, r, other.URL+"/target", http.StatusTemporaryRedirect)
+		default:
+			w.Write([]byte("target"))
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("keeps the token on same-host redirects", func(t *testing.T) {
+		req, err := client.newRequest("GET", "same-host", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := client.do(ctx, req, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "target" {
+			t.Fatalf("expected to be redirected to the target, got %q", buf.String())
+		}
+		if hops["/api/v2/same-host"] != "Bearer dummy-token" || hops["/api/v2/target"] != "Bearer dummy-token" {
+			t.Fatalf("expected the token on both hops, got %v", hops)
+		}
+	})
+
+	t.Run("drops the token on cross-host redirects", func(t *testing.T) {
+		req, err := client.newRequest("GET", "cross-host", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := client.do(ctx, req, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "other" {
+			t.Fatalf("expected to be redirected to the other host, got %q", buf.String())
+		}
+		if hops["/api/v2/cross-host"] != "Bearer dummy-token" {
+			t.Fatalf("expected the token on hop one, got %q", hops["/api/v2/cross-host"])
+		}
+		if v, ok := hops["other/target"]; !ok || v != "" {
+			t.Fatalf("expected no token on hop two, got %q (reached: %t)", v, ok)
+		}
+	})
+
+	t.Run("applies the redirect policy of the HTTP client", func(t *testing.T) {
+		delete(hops, "/api/v2/target")
+
+		httpClient := ts.Client()
+		httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		client.http.HTTPClient = httpClient
+
+		req, err := client.newRequest("GET", "same-host", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = client.do(ctx, req, &bytes.Buffer{})
+		var errResp *ErrorResponse
+		if !errors.As(err, &errResp) || errResp.StatusCode != http.StatusTemporaryRedirect {
+			t.Fatalf("expected the redirect response, got %v", err)
+		}
+		if _, ok := hops["/api/v2/target"]; ok {
+			t.Fatal("expected the redirect not to be followed")
+		}
+	})
+}
+
+func TestClient_plainJSON(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/meta/ip-ranges":
+			if v := r.Header.Get("Accept"); v != "application/json" {
+				t.Fatalf("expected Accept header %q, got %q", "application/json", v)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"api": ["10.0.0.0/8"], "notifications": ["192.168.0.0/16"]}`))
+		case "/api/v2/settings":
+			if v := r.Header.Get("Content-Type"); v != "application/json" {
+				t.Fatalf("expected Content-Type header %q, got %q", "application/json", v)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			io.Copy(w, r.Body)
+		case "/api/v2/discovery":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(`{"name": "discovery"}`))
+		case "/api/v2/runs/run-123/actions/apply":
+			body, _ := ioutil.ReadAll(r.Body)
+			if strings.TrimSpace(string(body)) != `{"comment":"looks good"}` {
+				t.Fatalf("expected a plain JSON body, got %q", body)
+			}
+			w.WriteHeader(202)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("decodes a plain JSON response", func(t *testing.T) {
+		req, err := client.newJSONRequest("GET", "../meta/ip-ranges", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ranges := make(map[string][]string)
+		if err := client.do(ctx, req, &ranges); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ranges["api"]) != 1 || ranges["api"][0] != "10.0.0.0/8" {
+			t.Fatalf("unexpected IP ranges: %v", ranges)
+		}
+	})
+
+	t.Run("encodes a plain JSON request", func(t *testing.T) {
+		type settings struct {
+			Name string `json:"name"`
+		}
+
+		req, err := client.newJSONRequest("PATCH", "settings", &settings{Name: "my-settings"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := &settings{}
+		if err := client.do(ctx, req, out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Name != "my-settings" {
+			t.Fatalf("expected name %q, got %q", "my-settings", out.Name)
+		}
+	})
+
+	t.Run("decodes based on the response content type", func(t *testing.T) {
+		req, err := client.newRequest("GET", "discovery", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := &struct {
+			Name string `json:"name"`
+		}{}
+		if err := client.do(ctx, req, out); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.Name != "discovery" {
+			t.Fatalf("expected name %q, got %q", "discovery", out.Name)
+		}
+	})
+
+	t.Run("encodes options without JSONAPI annotations", func(t *testing.T) {
+		err := client.Runs.Apply(ctx, "run-123", RunApplyOptions{Comment: String("looks good")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClient_listMeta(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-RateLimit-Limit", "30")
+
+		switch r.URL.Path {
+		case "/api/v2/organizations/my-org/workspaces":
+			w.Write([]byte(`{
+				"data": [{"id": "ws-1", "type": "workspaces"}],
+				"meta": {
+					"status-counts": {"applied": 3, "errored": 1, "total": 4},
+					"pagination": {"current-page": 1, "total-pages": 4, "total-count": 4}
+				}
+			}`))
+		case "/api/v2/workspaces/ws-1/runs":
+			w.Write([]byte(`{"data": [{"id": "run-1", "type": "runs"}]}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with a meta object", func(t *testing.T) {
+		wl, err := client.Workspaces.List(ctx, "my-org", WorkspaceListOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(wl.Items) != 1 || wl.Items[0].ID != "ws-1" {
+			t.Fatalf("unexpected items: %v", wl.Items)
+		}
+		if wl.TotalCount != 4 {
+			t.Fatalf("expected a total count of 4, got %d", wl.TotalCount)
+		}
+		if wl.Meta == nil || wl.Meta.StatusCounts["applied"] != 3 || wl.Meta.StatusCounts["total"] != 4 {
+			t.Fatalf("unexpected meta details: %+v", wl.Meta)
+		}
+	})
+
+	t.Run("without a meta object", func(t *testing.T) {
+		rl, err := client.Runs.List(ctx, "ws-1", RunListOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rl.Items) != 1 || rl.Items[0].ID != "run-1" {
+			t.Fatalf("unexpected items: %v", rl.Items)
+		}
+		if rl.Pagination == nil || rl.TotalCount != 0 {
+			t.Fatalf("expected empty pagination details, got %+v", rl.Pagination)
+		}
+		if rl.Meta != nil {
+			t.Fatalf("expected no meta details, got %+v", rl.Meta)
+		}
+	})
+}
+
+func TestClient_concurrentRequests(t *testing.T) {
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Header().Set("X-Request-Id", r.URL.Path)
+
+		if got := r.Header["X-Custom"]; len(got) != 1 || got[0] != "original" {
+			w.WriteHeader(400)
+			fmt.Fprintf(w, `{"errors": [{"status": "400", "title": "unexpected X-Custom header: %s"}]}`, strings.Join(got, ", "))
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/api/v2/workspaces/")
+		w.WriteHeader(200)
+		fmt.Fprintf(w, `{"data": {"id": %q, "type": "workspaces"}}`, id)
+	})
+	defer ts.Close()
+
+	headers := make(http.Header)
+	headers.Set("X-Custom", "original")
+
+	client, err := NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "dummy-token",
+		Headers:    headers,
+		HTTPClient: ts.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the headers afterwards should not affect the client.
+	headers["X-Custom"][0] = "changed"
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			id := fmt.Sprintf("ws-%d", i)
+			w, err := client.Workspaces.ReadByID(context.Background(), id)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if w.ID != id {
+				errs <- fmt.Errorf("expected workspace %q, got %q", id, w.ID)
+				return
+			}
+
+			client.RetryServerErrors(i%2 == 0)
+			client.RateLimit()
+			client.LastRequestID()
+			client.RemoteAPIVersion()
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestClient_doDownloadGzip(t *testing.T) {
+	content := strings.Repeat("compressible log line\n", 1000)
+
+	compressed := &bytes.Buffer{}
+	zw := gzip.NewWriter(compressed)
+	zw.Write([]byte(content))
+	zw.Close()
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/identity":
+			// A server that ignores the Accept-Encoding header.
+			w.Write([]byte(content))
+			return
+		}
+
+		if v := r.Header.Get("Accept-Encoding"); v != "gzip" {
+			t.Fatalf("expected Accept-Encoding header %q, got %q", "gzip", v)
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+
+		switch r.URL.Path {
+		case "/api/v2/gzip":
+			w.Write(compressed.Bytes())
+		case "/api/v2/truncated":
+			w.Write(compressed.Bytes()[:compressed.Len()/2])
+		case "/api/v2/invalid":
+			w.Write([]byte("not compressed"))
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	for _, path := range []string{"gzip", "identity"} {
+		t.Run(path, func(t *testing.T) {
+			body, err := client.doDownload(ctx, path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer body.Close()
+
+			got, err := ioutil.ReadAll(body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != content {
+				t.Fatalf("expected %d bytes of content, got %d", len(content), len(got))
+			}
+		})
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		body, err := client.doDownload(ctx, "truncated")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer body.Close()
+
+		_, err = ioutil.ReadAll(body)
+		if err != io.ErrUnexpectedEOF {
+			t.Fatalf("expected %v, got: %v", io.ErrUnexpectedEOF, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		body, err := client.doDownload(ctx, "invalid")
+		if err == nil {
+			body.Close()
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func BenchmarkClient_doDownload(b *testing.B) {
+	content := []byte(strings.Repeat("2019/01/01 00:00:00 [INFO] compressible log line\n", 20000))
+
+	for _, compress := range []bool{false, true} {
+		name := "identity"
+		if compress {
+			name = "gzip"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			var transferred int64
+			ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+				cw := &countingWriter{w: w}
+				if compress && r.Header.Get("Accept-Encoding") == "gzip" {
+					w.Header().Set("Content-Encoding", "gzip")
+					zw := gzip.NewWriter(cw)
+					zw.Write(content)
+					zw.Close()
+				} else {
+					cw.Write(content)
+				}
+				atomic.AddInt64(&transferred, cw.n)
+			})
+			defer ts.Close()
+
+			client, err := NewClient(&Config{
+				Address:    ts.URL,
+				Token:      "dummy-token",
+				HTTPClient: ts.Client(),
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				body, err := client.doDownload(context.Background(), "content")
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := io.Copy(ioutil.Discard, body); err != nil {
+					b.Fatal(err)
+				}
+				body.Close()
+			}
+			b.ReportMetric(float64(atomic.LoadInt64(&transferred))/float64(b.N), "transferred-B/op")
+		})
+	}
+}
+
+// countingWriter counts the bytes that are written.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func TestClient_instrumentation(t *testing.T) {
+	attempts := 0
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+
+		switch r.URL.Path {
+		case "/api/v2/organizations/retried":
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(500)
+				return
+			}
+			w.WriteHeader(200)
+			w.Write([]byte(`{"data": {"id": "retried", "type": "organizations"}}`))
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	inst := &recordingInstrumentation{}
+	client, err := NewClient(&Config{
+		Address:         ts.URL,
+		Token:           "dummy-token",
+		HTTPClient:      ts.Client(),
+		Instrumentation: inst,
+
+		ServerErrorRetryWaitMin: time.Millisecond,
+		ServerErrorRetryWaitMax: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Organizations.Read(context.Background(), "retried"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Fail before a response exists by connecting to a closed server.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	client.RetryServerErrors(false)
+	if _, err := client.doDownload(context.Background(), closed.URL+"/object"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	expected := []string{
+		"GET /api/v2/ping 204",
+		"GET /api/v2/organizations/retried 500",
+		"GET /api/v2/organizations/retried 500",
+		"GET /api/v2/organizations/retried 200",
+		"GET /object 0",
+	}
+	if len(inst.done) != len(expected) {
+		t.Fatalf("expected %d reported requests, got: %q", len(expected), inst.done)
+	}
+	for i, v := range expected {
+		if inst.done[i] != v {
+			t.Fatalf("expected request %d to be reported as %q, got %q", i, v, inst.done[i])
+		}
+	}
+	if inst.lastErr == nil {
+		t.Fatal("expected the connection error to be reported")
+	}
+}
+
+// recordingInstrumentation records the reported requests.
+type recordingInstrumentation struct {
+	done    []string
+	lastErr error
+}
+
+func (i *recordingInstrumentation) RequestStart(method, path string) interface{} {
+	return method + " " + path
+}
+
+func (i *recordingInstrumentation) RequestDone(token interface{}, status int, err error, d time.Duration) {
+	i.done = append(i.done, fmt.Sprintf("%s %d", token, status))
+	i.lastErr = err
+}
+
+func TestClient_proxyURL(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get("Proxy-Authorization"); v != "Basic dXNlcjpzZWNyZXQ=" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		proxied = append(proxied, r.Host+r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch r.URL.Path {
+		case "/api/v2/ping":
+			w.WriteHeader(204) // We query the configured ping URL which should return a 204.
+		case "/api/v2/organizations/proxied":
+			w.WriteHeader(200)
+			w.Write([]byte(`{"data": {"id": "proxied", "type": "organizations"}}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyURL.User = url.UserPassword("user", "secret")
+
+	t.Run("sends requests through the proxy", func(t *testing.T) {
+		client, err := NewClient(&Config{
+			Address:  "http://tfe.invalid",
+			Token:    "dummy-token",
+			ProxyURL: proxyURL.String(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		org, err := client.Organizations.Read(context.Background(), "proxied")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if org.Name != "proxied" {
+			t.Fatalf("unexpected organization: %q", org.Name)
+		}
+
+		expected := []string{"tfe.invalid/api/v2/ping", "tfe.invalid/api/v2/organizations/proxied"}
+		if strings.Join(proxied, ",") != strings.Join(expected, ",") {
+			t.Fatalf("expected proxied requests %q, got %q", expected, proxied)
+		}
+	})
+
+	t.Run("with a custom HTTP client", func(t *testing.T) {
+		_, err := NewClient(&Config{
+			Address:    "http://tfe.invalid",
+			Token:      "dummy-token",
+			HTTPClient: &http.Client{},
+			ProxyURL:   proxyURL.String(),
+		})
+		if err == nil || err.Error() != "invalid proxy: ProxyURL cannot be used with a custom HTTPClient" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("with an invalid proxy URL", func(t *testing.T) {
+		_, err := NewClient(&Config{
+			Address:  "http://tfe.invalid",
+			Token:    "dummy-token",
+			ProxyURL: "ftp://proxy.local",
+		})
+		if err == nil || err.Error() != "invalid proxy: scheme must be http, https or socks5" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClient_tlsConfig(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(204) // We query the configured ping URL which should return a 204.
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "go-tfe-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caCertFile := filepath.Join(dir, "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := ioutil.WriteFile(caCertFile, caCert, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	if err := ioutil.WriteFile(invalidFile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("with a CA bundle", func(t *testing.T) {
+		_, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "dummy-token",
+			CACertFile: caCertFile,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("without a CA bundle", func(t *testing.T) {
+		_, err := NewClient(&Config{
+			Address: ts.URL,
+			Token:   "dummy-token",
+		})
+		if err == nil || !strings.Contains(err.Error(), "certificate") {
+			t.Fatalf("expected a certificate error, got: %v", err)
+		}
+	})
+
+	t.Run("with an invalid CA bundle", func(t *testing.T) {
+		_, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "dummy-token",
+			CACertFile: invalidFile,
+		})
+		expected := "invalid TLS configuration: no certificates found in " + invalidFile
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected error %q, got: %v", expected, err)
+		}
+	})
+
+	t.Run("with insecure", func(t *testing.T) {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+		defer log.SetOutput(os.Stderr)
+
+		client, err := NewClient(&Config{
+			Address:  ts.URL,
+			Token:    "dummy-token",
+			Insecure: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !client.Insecure() {
+			t.Fatal("expected the client to be insecure")
+		}
+		if err := client.Ping(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := client.Ping(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := strings.Count(buf.String(), "certificate verification is disabled"); n != 1 {
+			t.Fatalf("expected the insecure warning once, got: %q", buf.String())
+		}
+	})
+
+	t.Run("with a custom HTTP client", func(t *testing.T) {
+		_, err := NewClient(&Config{
+			Address:    ts.URL,
+			Token:      "dummy-token",
+			HTTPClient: ts.Client(),
+			CACertFile: caCertFile,
+		})
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid TLS configuration") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClient_fetchPage(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+
+		if v := r.Header.Get("Authorization"); v != "Bearer dummy-token" {
+			t.Fatalf("unexpected Authorization header: %q", v)
+		}
+
+		switch r.URL.Query().Get("page[cursor]") {
+		case "":
+			w.Write([]byte(`{
+				"data": [{"id": "ws-1", "type": "workspaces"}],
+				"links": {
+					"next": "/api/v2/organizations/foo/workspaces?page%5Bcursor%5D=abc",
+					"prev": null
+				}
+			}`))
+		case "abc":
+			fmt.Fprintf(w, `{
+				"data": [{"id": "ws-2", "type": "workspaces"}],
+				"links": {
+					"prev": {"href": "%s/api/v2/organizations/foo/workspaces"}
+				}
+			}`, "http://"+r.Host)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	wl, err := client.Workspaces.List(ctx, "foo", WorkspaceListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wl.NextPageURL != "/api/v2/organizations/foo/workspaces?page%5Bcursor%5D=abc" || wl.PrevPageURL != "" {
+		t.Fatalf("unexpected links: %q and %q", wl.NextPageURL, wl.PrevPageURL)
+	}
+
+	next := &WorkspaceList{}
+	if err := client.FetchPage(ctx, wl.NextPageURL, next); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next.Items) != 1 || next.Items[0].ID != "ws-2" {
+		t.Fatalf("unexpected items: %+v", next.Items)
+	}
+	if next.NextPageURL != "" || next.PrevPageURL != ts.URL+"/api/v2/organizations/foo/workspaces" {
+		t.Fatalf("unexpected links: %q and %q", next.NextPageURL, next.PrevPageURL)
+	}
+
+	prev := &WorkspaceList{}
+	if err := client.FetchPage(ctx, next.PrevPageURL, prev); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prev.Items) != 1 || prev.Items[0].ID != "ws-1" {
+		t.Fatalf("unexpected items: %+v", prev.Items)
+	}
+
+	err = client.FetchPage(ctx, "https://example.com/api/v2/organizations/foo/workspaces", &WorkspaceList{})
+	if err == nil || !strings.HasPrefix(err.Error(), "invalid link: must point to") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_relationshipRequests(t *testing.T) {
+	var method, contentType string
+	var body []byte
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		contentType = r.Header.Get("Content-Type")
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(204)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	policies := []*Policy{
+		{ID: "pol-u3S5p2Uwk21keu1s", Name: "ignored"},
+		{ID: "pol-2s8ssAw7mKq7ZLGq"},
+	}
+	workspaces := []*Workspace{
+		{ID: "ws-u3S5p2Uwk21keu1s", Name: "ignored"},
+	}
+
+	cases := map[string]struct {
+		method  string
+		fixture string
+		call    func() error
+	}{
+		"add-policies": {"POST", "policies.json", func() error {
+			return client.PolicySets.AddPolicies(ctx, "polset-123", PolicySetAddPoliciesOptions{Policies: policies})
+		}},
+		"remove-policies": {"DELETE", "policies.json", func() error {
+			return client.PolicySets.RemovePolicies(ctx, "polset-123", PolicySetRemovePoliciesOptions{Policies: policies})
+		}},
+		"add-workspaces": {"POST", "workspaces.json", func() error {
+			return client.PolicySets.AddWorkspaces(ctx, "polset-123", PolicySetAddWorkspacesOptions{Workspaces: workspaces})
+		}},
+		"remove-workspaces": {"DELETE", "workspaces.json", func() error {
+			return client.PolicySets.RemoveWorkspaces(ctx, "polset-123", PolicySetRemoveWorkspacesOptions{Workspaces: workspaces})
+		}},
+		"add-remote-state-consumers": {"POST", "workspaces.json", func() error {
+			return client.Workspaces.AddRemoteStateConsumers(ctx, "ws-123", WorkspaceAddRemoteStateConsumersOptions{Workspaces: workspaces})
+		}},
+		"remove-remote-state-consumers": {"DELETE", "workspaces.json", func() error {
+			return client.Workspaces.RemoveRemoteStateConsumers(ctx, "ws-123", WorkspaceRemoveRemoteStateConsumersOptions{Workspaces: workspaces})
+		}},
+		"update-remote-state-consumers": {"PATCH", "workspaces.json", func() error {
+			return client.Workspaces.UpdateRemoteStateConsumers(ctx, "ws-123", WorkspaceUpdateRemoteStateConsumersOptions{Workspaces: workspaces})
+		}},
+		"clear-remote-state-consumers": {"PATCH", "empty.json", func() error {
+			return client.Workspaces.UpdateRemoteStateConsumers(ctx, "ws-123", WorkspaceUpdateRemoteStateConsumersOptions{Workspaces: []*Workspace{}})
+		}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.call(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if method != tc.method {
+				t.Fatalf("expected method %s, got %s", tc.method, method)
+			}
+			if contentType != "application/vnd.api+json" {
+				t.Fatalf("unexpected content type: %q", contentType)
+			}
+
+			fixture, err := ioutil.ReadFile(filepath.Join("test-fixtures", "relationships", tc.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var expected, got interface{}
+			if err := json.Unmarshal(fixture, &expected); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("invalid request body %q: %v", body, err)
+			}
+			if !reflect.DeepEqual(expected, got) {
+				t.Fatalf("expected request body %s, got %s", fixture, body)
+			}
+		})
+	}
+
+	t.Run("without a primary annotation", func(t *testing.T) {
+		_, err := client.newRelationshipRequest("DELETE", "foo", []*struct{ ID string }{{ID: "foo"}})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestClient_setToken(t *testing.T) {
+	var client *Client
+	var mu sync.Mutex
+	var retried []string
+
+	ts := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		auth := r.Header.Get("Authorization")
+
+		switch r.URL.Path {
+		case "/api/v2/workspaces/ws-retried":
+			mu.Lock()
+			retried = append(retried, auth)
+			attempt := len(retried)
+			mu.Unlock()
+
+			// Rotate the token while the request is in flight.
+			if attempt == 1 {
+				client.SetToken("rotated-token")
+				w.WriteHeader(503)
+				return
+			}
+			w.Write([]byte(`{"data": {"id": "ws-retried", "type": "workspaces"}}`))
+		default:
+			if !strings.HasPrefix(auth, "Bearer token-") && auth != "Bearer rotated-token" {
+				w.WriteHeader(401)
+				return
+			}
+			w.Write([]byte(`{"data": {"id": "ws-123", "type": "workspaces"}}`))
+		}
+	})
+	defer ts.Close()
+
+	var err error
+	client, err = NewClient(&Config{
+		Address:    ts.URL,
+		Token:      "initial-token",
+		HTTPClient: ts.Client(),
+
+		ServerErrorRetryWaitMin: time.Millisecond,
+		ServerErrorRetryWaitMax: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("keeps the token of in-flight requests", func(t *testing.T) {
+		if _, err := client.Workspaces.ReadByID(context.Background(), "ws-retried"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(retried) != 2 || retried[0] != "Bearer initial-token" || retried[1] != "Bearer initial-token" {
+			t.Fatalf("expected both attempts to use the initial token, got: %q", retried)
+		}
+		if v := client.RedactedToken(); v != "*********oken" {
+			t.Fatalf("expected the rotated token to be used, got: %q", v)
+		}
+	})
+
+	t.Run("rotates the token concurrently", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 100)
+
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if i%10 == 0 {
+					if err := client.SetToken(fmt.Sprintf("token-%d", i)); err != nil {
+						errs <- err
+					}
+					return
+				}
+				if _, err := client.Workspaces.ReadByID(context.Background(), "ws-123"); err != nil {
+					errs <- err
+				}
+			}(i)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Error(err)
+		}
+	})
+
+	t.Run("with an invalid token", func(t *testing.T) {
+		before := client.RedactedToken()
+		if err := client.SetToken("invalid token"); err == nil {
+			t.Fatal("expected an error")
+		}
+		if client.RedactedToken() != before {
+			t.Fatal("expected the token to be unchanged")
+		}
+	})
+
+	t.Run("redacts short tokens completely", func(t *testing.T) {
+		if err := client.SetToken("short"); err != nil {
+			t.Fatal(err)
+		}
+		if v := client.RedactedToken(); v != "*****" {
+			t.Fatalf("unexpected redacted token: %q", v)
+		}
+	})
+}
+
+func BenchmarkUnmarshalResponse_list(b *testing.B) {
+	for _, size := range []struct {
+		name  string
+		items int
+		attr  int
+	}{
+		{name: "small", items: 20, attr: 64},
+		{name: "10MB", items: 100, attr: 100 << 10},
+	} {
+		payload := testStateVersionListPayload(size.items, size.attr)
+
+		b.Run(size.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+
+			for i := 0; i < b.N; i++ {
+				svl := &StateVersionList{}
+				if err := unmarshalResponse(bytes.NewReader(payload), int64(len(payload)), svl); err != nil {
+					b.Fatal(err)
+				}
+				if len(svl.Items) != size.items {
+					b.Fatalf("expected %d items, got %d", size.items, len(svl.Items))
+				}
+			}
+		})
+	}
+}
+
+// testStateVersionListPayload returns a JSONAPI list of state versions,
+// each with a commit URL attribute of the given size.
+func testStateVersionListPayload(items, attr int) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(`{"data": [`)
+	for i := 0; i < items; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(buf, `{
+			"id": "sv-%d",
+			"type": "state-versions",
+			"attributes": {
+				"created-at": "2019-01-01T00:00:00.000Z",
+				"hosted-state-download-url": "https://archivist.terraform.io/v1/object/%d",
+				"serial": %d,
+				"vcs-commit-sha": "abcdef",
+				"vcs-commit-url": "https://example.com/%s"
+			},
+			"relationships": {"run": {"data": {"id": "run-%d", "type": "runs"}}}
+		}`, i, i, i, strings.Repeat("x", attr), i)
+	}
+	fmt.Fprintf(buf, `], "meta": {"pagination": {"current-page": 1, "total-pages": 1, "total-count": %d}}}`, items)
+	return buf.Bytes()
+}
+
+func TestClient_lockConflicts(t *testing.T) {
+	const lockedByRun = `{"errors": [{
+		"status": "409",
+		"title": "conflict",
+		"detail": "Unable to unlock workspace. The workspace is locked by Run run-123."
+	}]}`
+	const notLocked = `{"errors": [{
+		"status": "409",
+		"title": "conflict",
+		"detail": "Unable to unlock workspace. The workspace is not locked."
+	}]}`
+
+	cases := map[string]struct {
+		action string
+		body   string
+		err    error
+	}{
+		"lock a locked workspace":                {"lock", `{"errors": ["conflict"]}`, ErrWorkspaceLocked},
+		"unlock an unlocked workspace":           {"unlock", notLocked, ErrWorkspaceNotLocked},
+		"unlock without an error payload":        {"unlock", "", ErrWorkspaceNotLocked},
+		"unlock a workspace locked by a run":     {"unlock", lockedByRun, ErrWorkspaceLockedByRun},
+		"force unlock an unlocked workspace":     {"force-unlock", notLocked, ErrWorkspaceNotLocked},
+		"force unlock a workspace locked by run": {"force-unlock", lockedByRun, ErrWorkspaceLockedByRun},
+		"unlock with a locked by run title":      {"unlock", `{"errors": ["Locked by Run run-123"]}`, ErrWorkspaceLockedByRun},
+		"unlock with an unknown conflict":        {"unlock", `{"errors": [{"status": "409", "title": "conflict", "detail": "Something else."}]}`, ErrWorkspaceNotLocked},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := testResponse(t, 409, tc.body)
+			resp.Request.URL.Path = "/api/v2/workspaces/ws-123/actions/" + tc.action
+
+			err := checkResponseCode(resp)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got: %v", tc.err, err)
+			}
+
+			var errResp *ErrorResponse
+			if !errors.As(err, &errResp) {
+				t.Fatalf("expected an *ErrorResponse, got: %T", err)
+			}
+			if errResp.StatusCode != 409 || errResp.Path != resp.Request.URL.Path {
+				t.Fatalf("unexpected status code %d or path %q", errResp.StatusCode, errResp.Path)
+			}
+		})
+	}
+}
+
+func TestClient_safeDeleteConflicts(t *testing.T) {
+	cases := map[string]struct {
+		body     string
+		count    int
+		expected string
+	}{
+		"with a resource count": {
+			body: `{"errors": [{
+				"status": "409",
+				"title": "conflict",
+				"detail": "Workspace cannot be safely deleted because it is still managing 3 resources"
+			}]}`,
+			count:    3,
+			expected: "workspace not safe to delete: it still manages 3 resources",
+		},
+		"without a resource count": {
+			body: `{"errors": [{
+				"status": "409",
+				"title": "conflict",
+				"detail": "Workspace cannot be safely deleted"
+			}]}`,
+			expected: "workspace not safe to delete",
+		},
+		"without an error payload": {
+			body:     "",
+			expected: "workspace not safe to delete",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := testResponse(t, 409, tc.body)
+			resp.Request.URL.Path = "/api/v2/workspaces/ws-123/actions/safe-delete"
+
+			err := checkResponseCode(resp)
+			if !errors.Is(err, ErrWorkspaceNotSafeToDelete) {
+				t.Fatalf("expected ErrWorkspaceNotSafeToDelete, got: %v", err)
+			}
+
+			var notSafe *WorkspaceNotSafeToDeleteError
+			if !errors.As(err, &notSafe) {
+				t.Fatalf("expected a *WorkspaceNotSafeToDeleteError, got: %T", err)
+			}
+			if notSafe.ResourceCount != tc.count {
+				t.Fatalf("expected resource count %d, got: %d", tc.count, notSafe.ResourceCount)
+			}
+			if err.Error() != tc.expected {
+				t.Fatalf("expected error %q, got: %q", tc.expected, err.Error())
+			}
+		})
+	}
+
+	t.Run("with another conflict", func(t *testing.T) {
+		resp := testResponse(t, 409, `{"errors": ["conflict"]}`)
+		resp.Request.URL.Path = "/api/v2/workspaces/ws-123"
+
+		if err := checkResponseCode(resp); errors.Is(err, ErrWorkspaceNotSafeToDelete) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClient_runActionConflicts(t *testing.T) {
+	cases := map[string]struct {
+		path string
+		err  error
+	}{
+		"apply a run":              {"/api/v2/runs/run-123/actions/apply", ErrRunNotConfirmable},
+		"cancel a run":             {"/api/v2/runs/run-123/actions/cancel", ErrRunNotCancelable},
+		"discard a run":            {"/api/v2/runs/run-123/actions/discard", ErrRunNotDiscardable},
+		"force cancel a run":       {"/api/v2/runs/run-123/actions/force-cancel", ErrRunNotForceCancelable},
+		"force execute a run":      {"/api/v2/runs/run-123/actions/force-execute", ErrRunNotForceExecutable},
+		"with an unknown action":   {"/api/v2/runs/run-123/actions/unknown", nil},
+		"with a nested run action": {"/api/v2/runs/run-123/plan/actions/apply", nil},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := testResponse(t, 409, `{"errors": [{"status": "409", "title": "conflict"}]}`)
+			resp.Request.URL.Path = tc.path
+
+			err := checkResponseCode(resp)
+			if tc.err == nil {
+				if _, ok := err.(*ErrorResponse); !ok {
+					t.Fatalf("expected an *ErrorResponse, got: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+// closeCountingTransport counts the response bodies that are opened
+// and closed.
+type closeCountingTransport struct {
+	transport http.RoundTripper
+
+	mu     sync.Mutex
+	opened int
+	closed int
+}
+
+func (t *closeCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.opened++
+	t.mu.Unlock()
+
+	resp.Body = &closeCountingBody{ReadCloser: resp.Body, transport: t}
+	return resp, nil
+}
+
+func (t *closeCountingTransport) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.opened = 0
+	t.closed = 0
+}
+
+func (t *closeCountingTransport) counts() (int, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.opened, t.closed
+}
+
+type closeCountingBody struct {
+	io.ReadCloser
+	transport *closeCountingTransport
+}
+
+func (b *closeCountingBody) Close() error {
+	b.transport.mu.Lock()
+	b.transport.closed++
+	b.transport.mu.Unlock()
+	return b.ReadCloser.Close()
+}
+
+func testResponse(t *testing.T, status int, body string) *http.Response {
+	req, err := http.NewRequest("GET", "https://app.terraform.io/api/v2/ping", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
 func setupEnvVars(token, address string) func() {
 	origToken := os.Getenv("TFE_TOKEN")
 	origAddress := os.Getenv("TFE_ADDRESS")
@@ -0,0 +1,299 @@
+// Package tfetest provides a fake Terraform Enterprise API server that can
+// be used to test code that is built on top of the go-tfe client.
+//
+// A test registers handlers for the endpoints it expects to be called and
+// uses the client returned by NewTestClient to talk to the server:
+//
+//	client, server := tfetest.NewTestClient(t)
+//	defer server.Close()
+//
+//	server.HandlePayload("GET", "workspaces/ws-123", 200, &tfe.Workspace{
+//		ID:   "ws-123",
+//		Name: "my-workspace",
+//	})
+//
+// Every request is checked for a valid Authorization header and for a
+// supported content type, so regressions in the client are reported as
+// test failures.
+package tfetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/svanharmelen/jsonapi"
+)
+
+// Token is the API token the server expects on every request.
+const Token = "tfetest-token"
+
+const (
+	defaultPageSize  = 20
+	mediaTypeJSON    = "application/json"
+	mediaTypeJSONAPI = "application/vnd.api+json"
+)
+
+// Server is a fake Terraform Enterprise API server.
+type Server struct {
+	*httptest.Server
+
+	t        testing.TB
+	mu       sync.Mutex
+	handlers map[string]route
+}
+
+// route is a registered handler together with the checks that should be
+// performed before it is called.
+type route struct {
+	handler http.HandlerFunc
+	raw     bool
+}
+
+// NewServer starts and returns a new fake server. The caller should call
+// Close when finished, to shut it down.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		t:        t,
+		handlers: make(map[string]route),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewTestClient starts a new fake server and returns a client that is
+// configured to use it. The caller should call Close on the returned
+// server when finished, to shut it down.
+func NewTestClient(t testing.TB) (*tfe.Client, *Server) {
+	t.Helper()
+
+	s := NewServer(t)
+	client, err := tfe.NewClient(&tfe.Config{
+		Address: s.URL,
+		Token:   Token,
+	})
+	if err != nil {
+		s.Close()
+		t.Fatalf("error creating client: %v", err)
+	}
+
+	return client, s
+}
+
+// Handle registers the handler for the given method and path. The path is
+// relative to the API base path, e.g. "organizations/my-org/workspaces".
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.register(method, path, route{handler: handler})
+}
+
+// HandleRaw registers the handler for the given method and path without
+// checking the request headers. This is meant for endpoints the client
+// calls without credentials, like configuration version upload URLs.
+func (s *Server) HandleRaw(method, path string, handler http.HandlerFunc) {
+	s.register(method, path, route{handler: handler, raw: true})
+}
+
+// HandlePayload registers a handler for the given method and path that
+// responds with the given status code and v encoded as a JSON:API document.
+func (s *Server) HandlePayload(method, path string, status int, v interface{}) {
+	payload := MarshalPayload(s.t, v)
+	s.Handle(method, path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSONAPI)
+		w.WriteHeader(status)
+		w.Write(payload)
+	})
+}
+
+// HandleList registers a handler for the given method and path that
+// responds with a page of items, which must be a slice of struct pointers.
+// The page is selected using the page[number] and page[size] parameters
+// and the pagination details are added to the meta object.
+func (s *Server) HandleList(method, path string, items interface{}) {
+	s.Handle(method, path, func(w http.ResponseWriter, r *http.Request) {
+		if err := WriteList(w, r, items); err != nil {
+			s.t.Errorf("error writing list for %s %s: %v", method, path, err)
+		}
+	})
+}
+
+func (s *Server) register(method, path string, r route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[routeKey(method, path)] = r
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// The client pings the API when it is created.
+	if r.Method == "GET" && r.URL.Path == tfe.DefaultBasePath+"ping" {
+		s.checkAuthorization(r)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	s.mu.Lock()
+	rt, ok := s.handlers[routeKey(r.Method, strings.TrimPrefix(r.URL.Path, tfe.DefaultBasePath))]
+	s.mu.Unlock()
+
+	if !ok {
+		s.t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		WriteError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if !rt.raw {
+		if !s.checkAuthorization(r) {
+			WriteError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		if !s.checkContentType(r) {
+			WriteError(w, http.StatusUnsupportedMediaType, "unsupported media type")
+			return
+		}
+	}
+
+	rt.handler(w, r)
+}
+
+// checkAuthorization reports a test error if the request is not
+// authenticated with the expected token.
+func (s *Server) checkAuthorization(r *http.Request) bool {
+	if got := r.Header.Get("Authorization"); got != "Bearer "+Token {
+		s.t.Errorf("%s %s: expected Authorization header %q, got %q", r.Method, r.URL.Path, "Bearer "+Token, got)
+		return false
+	}
+	return true
+}
+
+// checkContentType reports a test error if the request does not accept a
+// JSON response, or if it has a body that is not JSON encoded.
+func (s *Server) checkContentType(r *http.Request) bool {
+	if got := r.Header.Get("Accept"); got != mediaTypeJSONAPI && got != mediaTypeJSON {
+		s.t.Errorf("%s %s: unexpected Accept header %q", r.Method, r.URL.Path, got)
+		return false
+	}
+	if r.ContentLength == 0 {
+		return true
+	}
+	if got := r.Header.Get("Content-Type"); got != mediaTypeJSONAPI && got != mediaTypeJSON {
+		s.t.Errorf("%s %s: unexpected Content-Type header %q", r.Method, r.URL.Path, got)
+		return false
+	}
+	return true
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + strings.TrimPrefix(path, "/")
+}
+
+// MarshalPayload encodes v, which must be a struct pointer or a slice of
+// struct pointers, as a JSON:API document. Any error is reported as a
+// fatal test error.
+func MarshalPayload(t testing.TB, v interface{}) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	if err := jsonapi.MarshalPayload(buf, v); err != nil {
+		t.Fatalf("error marshaling payload: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// WritePayload writes v encoded as a JSON:API document using the given
+// status code.
+func WritePayload(w http.ResponseWriter, status int, v interface{}) error {
+	buf := &bytes.Buffer{}
+	if err := jsonapi.MarshalPayload(buf, v); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", mediaTypeJSONAPI)
+	w.WriteHeader(status)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteList writes the page of items requested by r, together with the
+// pagination details. Items must be a slice of struct pointers.
+func WriteList(w http.ResponseWriter, r *http.Request, items interface{}) error {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("items must be a slice, got %T", items)
+	}
+
+	pageNumber := queryInt(r, "page[number]", 1)
+	pageSize := queryInt(r, "page[size]", defaultPageSize)
+
+	totalCount := v.Len()
+	totalPages := (totalCount + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	start := (pageNumber - 1) * pageSize
+	if start > totalCount {
+		start = totalCount
+	}
+	end := start + pageSize
+	if end > totalCount {
+		end = totalCount
+	}
+
+	payload, err := jsonapi.Marshal(v.Slice(start, end).Interface())
+	if err != nil {
+		return err
+	}
+	many, ok := payload.(*jsonapi.ManyPayload)
+	if !ok {
+		return fmt.Errorf("unexpected payload type %T", payload)
+	}
+
+	pagination := map[string]interface{}{
+		"current-page": pageNumber,
+		"prev-page":    nil,
+		"next-page":    nil,
+		"total-pages":  totalPages,
+		"total-count":  totalCount,
+	}
+	if pageNumber > 1 {
+		pagination["prev-page"] = pageNumber - 1
+	}
+	if pageNumber < totalPages {
+		pagination["next-page"] = pageNumber + 1
+	}
+	many.Meta = &jsonapi.Meta{"pagination": pagination}
+
+	w.Header().Set("Content-Type", mediaTypeJSONAPI)
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(many)
+}
+
+// WriteError writes a JSON:API error document using the given status code.
+func WriteError(w http.ResponseWriter, status int, title string) error {
+	w.Header().Set("Content-Type", mediaTypeJSONAPI)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{
+			"status": strconv.Itoa(status),
+			"title":  title,
+		}},
+	})
+}
+
+// queryInt returns the positive integer value of the given query parameter
+// or def if it is missing or invalid.
+func queryInt(r *http.Request, key string, def int) int {
+	v, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil || v < 1 {
+		return def
+	}
+	return v
+}
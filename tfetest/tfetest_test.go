@@ -0,0 +1,358 @@
+package tfetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	tfe "github.com/hashicorp/go-tfe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/svanharmelen/jsonapi"
+)
+
+func TestServer_handlePayload(t *testing.T) {
+	client, server := NewTestClient(t)
+	defer server.Close()
+
+	server.HandlePayload("GET", "workspaces/ws-123", http.StatusOK, &tfe.Workspace{
+		ID:   "ws-123",
+		Name: "my-workspace",
+	})
+
+	w, err := client.Workspaces.ReadByID(context.Background(), "ws-123")
+	require.NoError(t, err)
+	assert.Equal(t, "ws-123", w.ID)
+	assert.Equal(t, "my-workspace", w.Name)
+}
+
+func TestServer_handleList(t *testing.T) {
+	client, server := NewTestClient(t)
+	defer server.Close()
+
+	var workspaces []*tfe.Workspace
+	for i := 1; i <= 5; i++ {
+		workspaces = append(workspaces, &tfe.Workspace{
+			ID:   fmt.Sprintf("ws-%d", i),
+			Name: fmt.Sprintf("workspace-%d", i),
+		})
+	}
+	server.HandleList("GET", "organizations/my-org/workspaces", workspaces)
+
+	wl, err := client.Workspaces.List(context.Background(), "my-org", tfe.WorkspaceListOptions{
+		ListOptions: tfe.ListOptions{PageNumber: 2, PageSize: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, wl.Items, 2)
+	assert.Equal(t, "ws-3", wl.Items[0].ID)
+	assert.Equal(t, "ws-4", wl.Items[1].ID)
+	assert.Equal(t, 2, wl.CurrentPage)
+	assert.Equal(t, 1, wl.PreviousPage)
+	assert.Equal(t, 3, wl.NextPage)
+	assert.Equal(t, 3, wl.TotalPages)
+	assert.Equal(t, 5, wl.TotalCount)
+
+	wl, err = client.Workspaces.List(context.Background(), "my-org", tfe.WorkspaceListOptions{
+		ListOptions: tfe.ListOptions{PageNumber: 3, PageSize: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, wl.Items, 1)
+	assert.Equal(t, "ws-5", wl.Items[0].ID)
+	assert.Equal(t, 0, wl.NextPage)
+}
+
+func TestServer_handle(t *testing.T) {
+	client, server := NewTestClient(t)
+	defer server.Close()
+
+	server.Handle("PATCH", "workspaces/ws-123", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"name":"renamed"`)
+
+		WritePayload(w, http.StatusOK, &tfe.Workspace{ID: "ws-123", Name: "renamed"})
+	})
+
+	w, err := client.Workspaces.UpdateByID(context.Background(), "ws-123", tfe.WorkspaceUpdateOptions{
+		Name: tfe.String("renamed"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", w.Name)
+}
+
+func TestServer_handleRaw(t *testing.T) {
+	client, server := NewTestClient(t)
+	defer server.Close()
+
+	uploaded := false
+	server.HandleRaw("PUT", "/upload", func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+		uploaded = true
+	})
+
+	err := client.ConfigurationVersions.Upload(context.Background(), server.URL+"/upload", "../test-fixtures/config-version")
+	require.NoError(t, err)
+	assert.True(t, uploaded)
+}
+
+func TestServer_errors(t *testing.T) {
+	t.Run("with an unexpected request", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		server := NewServer(rt)
+		defer server.Close()
+
+		client, err := tfe.NewClient(&tfe.Config{Address: server.URL, Token: Token})
+		require.NoError(t, err)
+
+		_, err = client.Workspaces.ReadByID(context.Background(), "ws-123")
+		assert.True(t, errors.Is(err, tfe.ErrResourceNotFound), err)
+		require.Len(t, rt.errors, 1)
+		assert.Contains(t, rt.errors[0], "unexpected request: GET /api/v2/workspaces/ws-123")
+	})
+
+	t.Run("with an invalid token", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		server := NewServer(rt)
+		defer server.Close()
+
+		server.HandlePayload("GET", "workspaces/ws-123", http.StatusOK, &tfe.Workspace{ID: "ws-123"})
+
+		client, err := tfe.NewClient(&tfe.Config{Address: server.URL, Token: "wrong"})
+		require.NoError(t, err)
+
+		_, err = client.Workspaces.ReadByID(context.Background(), "ws-123")
+		assert.True(t, strings.HasPrefix(err.Error(), "unauthorized"), err.Error())
+
+		// Both the ping and the request should be reported.
+		require.Len(t, rt.errors, 2)
+		for _, e := range rt.errors {
+			assert.Contains(t, e, "expected Authorization header")
+		}
+	})
+}
+
+// TestServer_speculativePlan shows how to drive a speculative plan, like
+// the ones used for pull requests, against the fake server: create a
+// speculative configuration version, upload the configuration to it, wait
+// until it is uploaded and create a run for it. Runs of a speculative
+// configuration version are always plan-only and never change the current
+// configuration version of the workspace.
+func TestServer_speculativePlan(t *testing.T) {
+	client, server := NewTestClient(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	cv := &tfe.ConfigurationVersion{
+		ID:          "cv-123",
+		Speculative: true,
+		Status:      tfe.ConfigurationPending,
+		UploadURL:   server.URL + "/upload/cv-123",
+	}
+	server.Handle("POST", "workspaces/ws-123/configuration-versions", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"speculative":true`)
+		assert.NotContains(t, string(body), `"provisional"`)
+
+		WritePayload(w, http.StatusCreated, cv)
+	})
+	server.HandleRaw("PUT", "/upload/cv-123", func(w http.ResponseWriter, r *http.Request) {
+		cv.Status = tfe.ConfigurationUploaded
+	})
+	server.Handle("GET", "configuration-versions/cv-123", func(w http.ResponseWriter, r *http.Request) {
+		WritePayload(w, http.StatusOK, cv)
+	})
+	server.Handle("POST", "runs", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"configuration-version":{"data":{"type":"configuration-versions","id":"cv-123"}}`)
+
+		// Like the real API, runs of a speculative configuration
+		// version are plan-only.
+		WritePayload(w, http.StatusCreated, &tfe.Run{
+			ID:                   "run-123",
+			PlanOnly:             true,
+			Status:               tfe.RunPending,
+			ConfigurationVersion: cv,
+		})
+	})
+
+	created, err := client.ConfigurationVersions.Create(ctx, "ws-123", tfe.ConfigurationVersionCreateOptions{
+		AutoQueueRuns: tfe.Bool(false),
+		Speculative:   tfe.Bool(true),
+	})
+	require.NoError(t, err)
+	assert.True(t, created.Speculative)
+
+	err = client.ConfigurationVersions.Upload(ctx, created.UploadURL, "../test-fixtures/config-version")
+	require.NoError(t, err)
+
+	uploaded, err := client.ConfigurationVersions.Read(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, tfe.ConfigurationUploaded, uploaded.Status)
+
+	run, err := client.Runs.Create(ctx, tfe.RunCreateOptions{
+		Workspace:            &tfe.Workspace{ID: "ws-123"},
+		ConfigurationVersion: uploaded,
+		Message:              tfe.String("Speculative plan for pull request #4"),
+	})
+	require.NoError(t, err)
+	assert.True(t, run.PlanOnly)
+	assert.Equal(t, "cv-123", run.ConfigurationVersion.ID)
+}
+
+// TestServer_variableSync shows how to script the fake server through a
+// sync of the variables of a workspace, with a variable to create, one to
+// update, one that is unchanged, a sensitive one that cannot be compared and
+// one that is no longer managed.
+func TestServer_variableSync(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*tfe.Client, *Server, *[]string) {
+		client, server := NewTestClient(t)
+
+		vars := []*tfe.Variable{
+			{ID: "var-region", Key: "region", Value: "us-east-1", Category: tfe.CategoryTerraform},
+			{ID: "var-instance", Key: "instance_type", Value: "t3.micro", Category: tfe.CategoryTerraform},
+			{ID: "var-secret", Key: "AWS_SECRET_ACCESS_KEY", Value: "secret", Category: tfe.CategoryEnv, Sensitive: true},
+			{ID: "var-legacy", Key: "legacy", Value: "true", Category: tfe.CategoryTerraform},
+		}
+		var requests []string
+
+		server.Handle("GET", "workspaces/ws-123/vars", func(w http.ResponseWriter, r *http.Request) {
+			// Like the real API, never return sensitive values.
+			var items []*tfe.Variable
+			for _, v := range vars {
+				item := *v
+				if item.Sensitive {
+					item.Value = ""
+				}
+				items = append(items, &item)
+			}
+			require.NoError(t, WriteList(w, r, items))
+		})
+		server.Handle("POST", "workspaces/ws-123/vars", func(w http.ResponseWriter, r *http.Request) {
+			v := &tfe.Variable{}
+			require.NoError(t, jsonapi.UnmarshalPayload(r.Body, v))
+			requests = append(requests, "POST "+v.Key)
+			if v.Key == "broken" {
+				require.NoError(t, WriteError(w, http.StatusUnprocessableEntity, "invalid attribute"))
+				return
+			}
+
+			v.ID = "var-" + v.Key
+			vars = append(vars, v)
+			require.NoError(t, WritePayload(w, http.StatusCreated, v))
+		})
+		for _, v := range vars {
+			v := v
+			server.Handle("PATCH", "workspaces/ws-123/vars/"+v.ID, func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				requests = append(requests, "PATCH "+v.Key+" "+string(body))
+				require.NoError(t, WritePayload(w, http.StatusOK, v))
+			})
+			server.Handle("DELETE", "workspaces/ws-123/vars/"+v.ID, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, "DELETE "+v.Key)
+				w.WriteHeader(http.StatusNoContent)
+			})
+		}
+
+		return client, server, &requests
+	}
+
+	desired := []*tfe.VariableCreateOptions{
+		{Key: tfe.String("region"), Value: tfe.String("us-west-2"), Category: tfe.Category(tfe.CategoryTerraform)},
+		{Key: tfe.String("instance_type"), Value: tfe.String("t3.micro"), Category: tfe.Category(tfe.CategoryTerraform)},
+		{Key: tfe.String("AWS_SECRET_ACCESS_KEY"), Value: tfe.String("rotated"), Category: tfe.Category(tfe.CategoryEnv), Sensitive: tfe.Bool(true)},
+		{Key: tfe.String("tags"), Value: tfe.String(`{ team = "networking" }`), Category: tfe.Category(tfe.CategoryTerraform), HCL: tfe.Bool(true)},
+	}
+
+	t.Run("with unmanaged variables deleted", func(t *testing.T) {
+		client, server, requests := setup(t)
+		defer server.Close()
+
+		result, err := client.Variables.Sync(ctx, "ws-123", desired, tfe.VariableSyncOptions{DeleteUnmanaged: true})
+		require.NoError(t, err)
+		assert.Equal(t, &tfe.VariableSyncResult{
+			Created: []string{"tags"},
+			Updated: []string{"region"},
+			Deleted: []string{"legacy"},
+		}, result)
+
+		require.Len(t, *requests, 3)
+		assert.Contains(t, (*requests)[0], "PATCH region")
+		assert.Contains(t, (*requests)[0], `"value":"us-west-2"`)
+		assert.Equal(t, "POST tags", (*requests)[1])
+		assert.Equal(t, "DELETE legacy", (*requests)[2])
+	})
+
+	t.Run("with unmanaged variables kept", func(t *testing.T) {
+		client, server, requests := setup(t)
+		defer server.Close()
+
+		result, err := client.Variables.Sync(ctx, "ws-123", desired, tfe.VariableSyncOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tags"}, result.Created)
+		assert.Equal(t, []string{"region"}, result.Updated)
+		assert.Empty(t, result.Deleted)
+		assert.Len(t, *requests, 2)
+	})
+
+	t.Run("when a change fails", func(t *testing.T) {
+		client, server, requests := setup(t)
+		defer server.Close()
+
+		broken := append([]*tfe.VariableCreateOptions{}, desired...)
+		broken = append(broken,
+			&tfe.VariableCreateOptions{Key: tfe.String("broken"), Category: tfe.Category(tfe.CategoryTerraform)},
+			&tfe.VariableCreateOptions{Key: tfe.String("never"), Category: tfe.Category(tfe.CategoryTerraform)},
+		)
+
+		result, err := client.Variables.Sync(ctx, "ws-123", broken, tfe.VariableSyncOptions{DeleteUnmanaged: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid attribute")
+
+		// The changes made before the error are reported.
+		assert.Equal(t, []string{"tags"}, result.Created)
+		assert.Equal(t, []string{"region"}, result.Updated)
+		assert.Empty(t, result.Deleted)
+		assert.Equal(t, "POST broken", (*requests)[len(*requests)-1])
+	})
+
+	t.Run("with duplicate variables", func(t *testing.T) {
+		client, server, requests := setup(t)
+		defer server.Close()
+
+		duplicate := append([]*tfe.VariableCreateOptions{}, desired...)
+		duplicate = append(duplicate, &tfe.VariableCreateOptions{
+			Key:      tfe.String("region"),
+			Category: tfe.Category(tfe.CategoryTerraform),
+		})
+
+		result, err := client.Variables.Sync(ctx, "ws-123", duplicate, tfe.VariableSyncOptions{})
+		assert.Nil(t, result)
+		assert.EqualError(t, err, `duplicate terraform variable "region"`)
+		assert.Empty(t, *requests)
+	})
+}
+
+// recordingT records the reported errors instead of failing the test.
+type recordingT struct {
+	testing.TB
+
+	mu     sync.Mutex
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
@@ -15,6 +15,15 @@ func Bool(v bool) *bool {
 	return &v
 }
 
+// BoolValue returns the value of the given bool pointer, or false if the
+// pointer is nil.
+func BoolValue(v *bool) bool {
+	if v == nil {
+		return false
+	}
+	return *v
+}
+
 // Category returns a pointer to the given category type.
 func Category(v CategoryType) *CategoryType {
 	return &v
@@ -25,16 +34,39 @@ func EnforcementMode(v EnforcementLevel) *EnforcementLevel {
 	return &v
 }
 
+// ExecutionMode returns a pointer to the given execution mode.
+func ExecutionMode(v ExecutionModeType) *ExecutionModeType {
+	return &v
+}
+
 // Int returns a pointer to the given int.
 func Int(v int) *int {
 	return &v
 }
 
+// IntValue returns the value of the given int pointer, or 0 if the pointer
+// is nil.
+func IntValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
 // Int64 returns a pointer to the given int64.
 func Int64(v int64) *int64 {
 	return &v
 }
 
+// Int64Value returns the value of the given int64 pointer, or 0 if the
+// pointer is nil.
+func Int64Value(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
 // NotificationDestination returns a pointer to the given notification configuration destination type
 func NotificationDestination(v NotificationDestinationType) *NotificationDestinationType {
 	return &v
@@ -54,3 +86,12 @@ func ServiceProvider(v ServiceProviderType) *ServiceProviderType {
 func String(v string) *string {
 	return &v
 }
+
+// StringValue returns the value of the given string pointer, or an empty
+// string if the pointer is nil.
+func StringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,41 @@
+package tfe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeHelpers_pointers(t *testing.T) {
+	assert.Equal(t, AccessAdmin, *Access(AccessAdmin))
+	assert.Equal(t, AuthPolicyPassword, *AuthPolicy(AuthPolicyPassword))
+	assert.Equal(t, true, *Bool(true))
+	assert.Equal(t, CategoryEnv, *Category(CategoryEnv))
+	assert.Equal(t, EnforcementHard, *EnforcementMode(EnforcementHard))
+	assert.Equal(t, ExecutionModeAgent, *ExecutionMode(ExecutionModeAgent))
+	assert.Equal(t, 42, *Int(42))
+	assert.Equal(t, int64(42), *Int64(42))
+	assert.Equal(t, NotificationDestinationTypeSlack, *NotificationDestination(NotificationDestinationTypeSlack))
+	assert.Equal(t, PlanExportSentinelMockBundleV0, *PlanExportType(PlanExportSentinelMockBundleV0))
+	assert.Equal(t, ServiceProviderGithub, *ServiceProvider(ServiceProviderGithub))
+	assert.Equal(t, "foo", *String("foo"))
+
+	// Every call returns a new pointer.
+	assert.False(t, String("foo") == String("foo"))
+}
+
+func TestTypeHelpers_values(t *testing.T) {
+	t.Run("with nil pointers", func(t *testing.T) {
+		assert.Equal(t, false, BoolValue(nil))
+		assert.Equal(t, 0, IntValue(nil))
+		assert.Equal(t, int64(0), Int64Value(nil))
+		assert.Equal(t, "", StringValue(nil))
+	})
+
+	t.Run("with values", func(t *testing.T) {
+		assert.Equal(t, true, BoolValue(Bool(true)))
+		assert.Equal(t, 42, IntValue(Int(42)))
+		assert.Equal(t, int64(42), Int64Value(Int64(42)))
+		assert.Equal(t, "foo", StringValue(String("foo")))
+	})
+}
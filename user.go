@@ -2,6 +2,7 @@ package tfe
 
 import (
 	"context"
+	"errors"
 )
 
 // Compile-time proof of interface implementation.
@@ -73,8 +74,22 @@ type UserUpdateOptions struct {
 	Email *string `jsonapi:"attr,email,omitempty"`
 }
 
+func (o UserUpdateOptions) valid() error {
+	if o.Username != nil && !validStringID(o.Username) {
+		return errors.New("invalid value for username")
+	}
+	if o.Email != nil && !validString(o.Email) {
+		return errors.New("invalid value for email")
+	}
+	return nil
+}
+
 // Update attributes of the currently authenticated user.
 func (s *users) Update(ctx context.Context, options UserUpdateOptions) (*User, error) {
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
+
 	// Make sure we don't send a user provided ID.
 	options.ID = ""
 
@@ -2,18 +2,42 @@ package tfe
 
 import (
 	"regexp"
+	"strings"
 )
 
 // A regular expression used to validate common string ID patterns.
 var reStringID = regexp.MustCompile(`^[a-zA-Z0-9\-\._]+$`)
 
+// A regular expression used to validate tag names.
+var reTagName = regexp.MustCompile(`^[a-z0-9\-_]+$`)
+
 // validString checks if the given input is present and non-empty.
 func validString(v *string) bool {
 	return v != nil && *v != ""
 }
 
 // validStringID checks if the given string pointer is non-nil and
-// contains a typical string identifier.
+// contains a typical string identifier. The "." and ".." identifiers are
+// rejected, as they would be resolved as dot-segments of the request path.
 func validStringID(v *string) bool {
-	return v != nil && reStringID.MatchString(*v)
+	return v != nil && *v != "." && *v != ".." && reStringID.MatchString(*v)
+}
+
+// validResourceID checks if the given string pointer contains the ID of a
+// resource, which is a string identifier made of the given prefix (e.g.
+// "run-" for runs) followed by the identifier itself.
+func validResourceID(v *string, prefix string) bool {
+	return validString(v) && *v != prefix && strings.HasPrefix(*v, prefix) && validStringID(v)
+}
+
+// validWorkspaceID checks if the given string pointer contains a workspace
+// ID, which is a string identifier starting with "ws-".
+func validWorkspaceID(v *string) bool {
+	return validResourceID(v, "ws-")
+}
+
+// validTagName checks if the given string pointer is non-nil and contains a
+// tag name, which can only include lowercase letters, numbers, - and _.
+func validTagName(v *string) bool {
+	return v != nil && reTagName.MatchString(*v)
 }
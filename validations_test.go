@@ -0,0 +1,115 @@
+package tfe
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidations_resourceID(t *testing.T) {
+	cases := map[string]struct {
+		id    *string
+		valid bool
+	}{
+		"with a valid ID":           {String("run-CZcmD7eagjhyX0vN"), true},
+		"without an ID":             {nil, false},
+		"with an empty ID":          {String(""), false},
+		"with only the prefix":      {String("run-"), false},
+		"with another prefix":       {String("ws-CZcmD7eagjhyX0vN"), false},
+		"without a prefix":          {String("CZcmD7eagjhyX0vN"), false},
+		"with invalid characters":   {String("run-CZcmD7/../eagjhyX0vN"), false},
+		"with the prefix elsewhere": {String("my-run-CZcmD7eagjhyX0vN"), false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.valid, validResourceID(tc.id, "run-"))
+		})
+	}
+}
+
+func TestValidations_workspaceID(t *testing.T) {
+	assert.True(t, validWorkspaceID(String("ws-CZcmD7eagjhyX0vN")))
+	assert.False(t, validWorkspaceID(String("")))
+	assert.False(t, validWorkspaceID(String("my-workspace")))
+}
+
+func TestValidations_workspaceIDsUseValidWorkspaceID(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Workspace IDs must always be checked for their "ws-" prefix.
+	re := regexp.MustCompile(`validStringID\(&[\w.]*[wW]orkspace(ID|\.ID)\)`)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range re.FindAll(src, -1) {
+			t.Errorf("%s: use validWorkspaceID instead of %s", file, match)
+		}
+	}
+}
+
+func TestValidations_workspaceIDMethods(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	calls := map[string]func(workspaceID string) error{
+		"ConfigurationVersions.List": func(workspaceID string) error {
+			_, err := client.ConfigurationVersions.List(ctx, workspaceID, ConfigurationVersionListOptions{})
+			return err
+		},
+		"NotificationConfigurations.List": func(workspaceID string) error {
+			_, err := client.NotificationConfigurations.List(ctx, workspaceID, NotificationConfigurationListOptions{})
+			return err
+		},
+		"Runs.List": func(workspaceID string) error {
+			_, err := client.Runs.List(ctx, workspaceID, RunListOptions{})
+			return err
+		},
+		"RunTriggers.List": func(workspaceID string) error {
+			_, err := client.RunTriggers.List(ctx, workspaceID, RunTriggerListOptions{})
+			return err
+		},
+		"StateVersions.Current": func(workspaceID string) error {
+			_, err := client.StateVersions.Current(ctx, workspaceID)
+			return err
+		},
+		"StateVersionOutputs.ReadCurrent": func(workspaceID string) error {
+			_, err := client.StateVersionOutputs.ReadCurrent(ctx, workspaceID, StateVersionOutputsListOptions{})
+			return err
+		},
+		"Variables.List": func(workspaceID string) error {
+			_, err := client.Variables.List(ctx, workspaceID, VariableListOptions{})
+			return err
+		},
+		"Workspaces.Lock": func(workspaceID string) error {
+			_, err := client.Workspaces.Lock(ctx, workspaceID, WorkspaceLockOptions{})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call("run-CZcmD7eagjhyX0vN")
+			if err == nil || !strings.Contains(err.Error(), "invalid value for workspace ID") {
+				t.Fatalf("expected an invalid workspace ID error, got: %v", err)
+			}
+		})
+	}
+}
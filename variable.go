@@ -29,6 +29,10 @@ type Variables interface {
 
 	// Delete a variable by its ID.
 	Delete(ctx context.Context, workspaceID string, variableID string) error
+
+	// Sync creates, updates and optionally deletes the variables of the
+	// given workspace to match the desired variables.
+	Sync(ctx context.Context, workspaceID string, desired []*VariableCreateOptions, options VariableSyncOptions) (*VariableSyncResult, error)
 }
 
 // variables implements Variables.
@@ -39,7 +43,7 @@ type variables struct {
 // CategoryType represents a category type.
 type CategoryType string
 
-//List all available categories.
+// List all available categories.
 const (
 	CategoryEnv       CategoryType = "env"
 	CategoryPolicySet CategoryType = "policy-set"
@@ -52,15 +56,17 @@ type VariableList struct {
 	Items []*Variable
 }
 
-// Variable represents a Terraform Enterprise variable.
+// Variable represents a Terraform Enterprise variable. The API never returns
+// the value of a sensitive variable, so Value is always empty for them and
+// must not be written back as the value of the variable.
 type Variable struct {
-	ID        string       `jsonapi:"primary,vars"`
-	Key       string       `jsonapi:"attr,key"`
-	Value     string       `jsonapi:"attr,value"`
-	Description string     `jsonapi:"attr,description"`
-	Category  CategoryType `jsonapi:"attr,category"`
-	HCL       bool         `jsonapi:"attr,hcl"`
-	Sensitive bool         `jsonapi:"attr,sensitive"`
+	ID          string       `jsonapi:"primary,vars"`
+	Key         string       `jsonapi:"attr,key"`
+	Value       string       `jsonapi:"attr,value"`
+	Description string       `jsonapi:"attr,description"`
+	Category    CategoryType `jsonapi:"attr,category"`
+	HCL         bool         `jsonapi:"attr,hcl"`
+	Sensitive   bool         `jsonapi:"attr,sensitive"`
 
 	// Relations
 	Workspace *Workspace `jsonapi:"relation,configurable"`
@@ -73,11 +79,11 @@ type VariableListOptions struct {
 
 // List all the variables associated with the given workspace.
 func (s *variables) List(ctx context.Context, workspaceID string, options VariableListOptions) (*VariableList, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
-	u := fmt.Sprintf("workspaces/%s/vars", workspaceID)
+	u := fmt.Sprintf("workspaces/%s/vars", url.QueryEscape(workspaceID))
 	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
 		return nil, err
@@ -106,7 +112,8 @@ type VariableCreateOptions struct {
 	// The description of the variable.
 	Description *string `jsonapi:"attr,description,omitempty"`
 
-	// Whether this is a Terraform or environment variable.
+	// Whether this is a Terraform or environment variable. Only
+	// CategoryTerraform and CategoryEnv are valid for workspace variables.
 	Category *CategoryType `jsonapi:"attr,category"`
 
 	// Whether to evaluate the value of the variable as a string of HCL code.
@@ -123,12 +130,17 @@ func (o VariableCreateOptions) valid() error {
 	if o.Category == nil {
 		return errors.New("category is required")
 	}
+	switch *o.Category {
+	case CategoryEnv, CategoryTerraform:
+	default:
+		return errors.New("invalid value for category")
+	}
 	return nil
 }
 
 // Create is used to create a new variable.
 func (s *variables) Create(ctx context.Context, workspaceID string, options VariableCreateOptions) (*Variable, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 	if err := options.valid(); err != nil {
@@ -155,10 +167,10 @@ func (s *variables) Create(ctx context.Context, workspaceID string, options Vari
 
 // Read a variable by its ID.
 func (s *variables) Read(ctx context.Context, workspaceID string, variableID string) (*Variable, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
-	if !validStringID(&variableID) {
+	if !validResourceID(&variableID, "var-") {
 		return nil, errors.New("invalid value for variable ID")
 	}
 
@@ -198,14 +210,24 @@ type VariableUpdateOptions struct {
 	Sensitive *bool `jsonapi:"attr,sensitive,omitempty"`
 }
 
+func (o VariableUpdateOptions) valid() error {
+	if o.Key != nil && !validString(o.Key) {
+		return errors.New("invalid value for key")
+	}
+	return nil
+}
+
 // Update values of an existing variable.
 func (s *variables) Update(ctx context.Context, workspaceID string, variableID string, options VariableUpdateOptions) (*Variable, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
-	if !validStringID(&variableID) {
+	if !validResourceID(&variableID, "var-") {
 		return nil, errors.New("invalid value for variable ID")
 	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
 
 	// Make sure we don't send a user provided ID.
 	options.ID = variableID
@@ -227,10 +249,10 @@ func (s *variables) Update(ctx context.Context, workspaceID string, variableID s
 
 // Delete a variable by its ID.
 func (s *variables) Delete(ctx context.Context, workspaceID string, variableID string) error {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return errors.New("invalid value for workspace ID")
 	}
-	if !validStringID(&variableID) {
+	if !validResourceID(&variableID, "var-") {
 		return errors.New("invalid value for variable ID")
 	}
 
@@ -242,3 +264,133 @@ func (s *variables) Delete(ctx context.Context, workspaceID string, variableID s
 
 	return s.client.do(ctx, req, nil)
 }
+
+// VariableSyncOptions represents the options for syncing variables.
+type VariableSyncOptions struct {
+	// Whether to delete the existing variables that are not desired.
+	DeleteUnmanaged bool
+}
+
+// VariableSyncResult represents the keys of the variables that were
+// changed by a sync, in the order in which they were changed.
+type VariableSyncResult struct {
+	Created []string
+	Updated []string
+	Deleted []string
+}
+
+// Sync creates, updates and optionally deletes the variables of the given
+// workspace to match the desired variables. Variables are matched by their
+// category and key. As the values of sensitive variables are never returned,
+// the desired value of an existing sensitive variable is only sent when any
+// of its other attributes changed. Syncing stops at the first error, in
+// which case the result describes the changes that were made before it.
+func (s *variables) Sync(ctx context.Context, workspaceID string, desired []*VariableCreateOptions, options VariableSyncOptions) (*VariableSyncResult, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+
+	seen := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		if d == nil {
+			return nil, errors.New("desired variables cannot be nil")
+		}
+		if err := d.valid(); err != nil {
+			return nil, err
+		}
+		id := variableSyncKey(*d.Category, *d.Key)
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate %s variable %q", *d.Category, *d.Key)
+		}
+		seen[id] = true
+	}
+
+	var existing []*Variable
+	err := ForEachPage(ListOptions{PageSize: 100}, func(lo ListOptions) (*Pagination, error) {
+		vl, err := s.List(ctx, workspaceID, VariableListOptions{ListOptions: lo})
+		if err != nil {
+			return nil, err
+		}
+		existing = append(existing, vl.Items...)
+		return vl.Pagination, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*Variable, len(existing))
+	for _, v := range existing {
+		byKey[variableSyncKey(v.Category, v.Key)] = v
+	}
+
+	result := &VariableSyncResult{}
+	for _, d := range desired {
+		v, ok := byKey[variableSyncKey(*d.Category, *d.Key)]
+		if !ok {
+			if _, err := s.Create(ctx, workspaceID, *d); err != nil {
+				return result, err
+			}
+			result.Created = append(result.Created, *d.Key)
+			continue
+		}
+
+		update, changed := variableSyncUpdate(v, d)
+		if !changed {
+			continue
+		}
+		if _, err := s.Update(ctx, workspaceID, v.ID, update); err != nil {
+			return result, err
+		}
+		result.Updated = append(result.Updated, *d.Key)
+	}
+
+	if options.DeleteUnmanaged {
+		for _, v := range existing {
+			if seen[variableSyncKey(v.Category, v.Key)] {
+				continue
+			}
+			if err := s.Delete(ctx, workspaceID, v.ID); err != nil {
+				return result, err
+			}
+			result.Deleted = append(result.Deleted, v.Key)
+		}
+	}
+
+	return result, nil
+}
+
+// variableSyncKey returns the key used to match desired and existing
+// variables, as the same key can be used for a Terraform and an environment
+// variable.
+func variableSyncKey(category CategoryType, key string) string {
+	return string(category) + "/" + key
+}
+
+// variableSyncUpdate returns the options to update the existing variable to
+// the desired one, and whether the variable needs to be updated at all.
+func variableSyncUpdate(v *Variable, d *VariableCreateOptions) (VariableUpdateOptions, bool) {
+	changed := false
+	if d.Description != nil && *d.Description != v.Description {
+		changed = true
+	}
+	if d.HCL != nil && *d.HCL != v.HCL {
+		changed = true
+	}
+	if d.Sensitive != nil && *d.Sensitive != v.Sensitive {
+		changed = true
+	}
+	// The value of a sensitive variable cannot be compared.
+	if d.Value != nil && !v.Sensitive && *d.Value != v.Value {
+		changed = true
+	}
+	if !changed {
+		return VariableUpdateOptions{}, false
+	}
+
+	return VariableUpdateOptions{
+		Value:       d.Value,
+		Description: d.Description,
+		HCL:         d.HCL,
+		Sensitive:   d.Sensitive,
+	}, true
+}
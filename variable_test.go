@@ -2,6 +2,9 @@ package tfe
 
 import (
 	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -55,6 +58,37 @@ func TestVariablesList(t *testing.T) {
 	})
 }
 
+func TestVariablesListFixture(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/workspaces/ws-cZE9LERN3rGPRAmH/vars":
+			data, err := ioutil.ReadFile("test-fixtures/variable/list.json")
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(data)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	vl, err := client.Variables.List(context.Background(), "ws-cZE9LERN3rGPRAmH", VariableListOptions{})
+	require.NoError(t, err)
+	require.Len(t, vl.Items, 2)
+	assert.Equal(t, 2, vl.TotalCount)
+
+	assert.Equal(t, "region", vl.Items[0].Key)
+	assert.Equal(t, "us-east-1", vl.Items[0].Value)
+	assert.Equal(t, CategoryTerraform, vl.Items[0].Category)
+	assert.Equal(t, "ws-cZE9LERN3rGPRAmH", vl.Items[0].Workspace.ID)
+
+	// The value of a sensitive variable is never returned.
+	assert.Equal(t, "AWS_SECRET_ACCESS_KEY", vl.Items[1].Key)
+	assert.Equal(t, CategoryEnv, vl.Items[1].Category)
+	assert.True(t, vl.Items[1].Sensitive)
+	assert.Empty(t, vl.Items[1].Value)
+}
+
 func TestVariablesCreate(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -64,9 +98,9 @@ func TestVariablesCreate(t *testing.T) {
 
 	t.Run("with valid options", func(t *testing.T) {
 		options := VariableCreateOptions{
-			Key:      String(randomString(t)),
-			Value:    String(randomString(t)),
-			Category: Category(CategoryTerraform),
+			Key:         String(randomString(t)),
+			Value:       String(randomString(t)),
+			Category:    Category(CategoryTerraform),
 			Description: String(randomString(t)),
 		}
 
@@ -207,9 +241,9 @@ func TestVariablesRead(t *testing.T) {
 	})
 
 	t.Run("when the variable does not exist", func(t *testing.T) {
-		v, err := client.Variables.Read(ctx, vTest.Workspace.ID, "nonexisting")
+		v, err := client.Variables.Read(ctx, vTest.Workspace.ID, "var-nonexisting")
 		assert.Nil(t, v)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid workspace ID", func(t *testing.T) {
@@ -308,8 +342,8 @@ func TestVariablesDelete(t *testing.T) {
 	})
 
 	t.Run("with non existing variable ID", func(t *testing.T) {
-		err := client.Variables.Delete(ctx, wTest.ID, "nonexisting")
-		assert.Equal(t, err, ErrResourceNotFound)
+		err := client.Variables.Delete(ctx, wTest.ID, "var-nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("with invalid workspace ID", func(t *testing.T) {
@@ -322,3 +356,75 @@ func TestVariablesDelete(t *testing.T) {
 		assert.EqualError(t, err, "invalid value for variable ID")
 	})
 }
+
+func TestVariablesCreateOptionsValid(t *testing.T) {
+	t.Run("with a terraform variable", func(t *testing.T) {
+		options := VariableCreateOptions{
+			Key:      String("region"),
+			Category: Category(CategoryTerraform),
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with an environment variable", func(t *testing.T) {
+		options := VariableCreateOptions{
+			Key:      String("AWS_REGION"),
+			Category: Category(CategoryEnv),
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with an empty key", func(t *testing.T) {
+		options := VariableCreateOptions{
+			Key:      String(""),
+			Category: Category(CategoryTerraform),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "key is required")
+	})
+
+	t.Run("with a policy set category", func(t *testing.T) {
+		options := VariableCreateOptions{
+			Key:      String("region"),
+			Category: Category(CategoryPolicySet),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for category")
+	})
+
+	t.Run("with an unknown category", func(t *testing.T) {
+		options := VariableCreateOptions{
+			Key:      String("region"),
+			Category: Category("unknown"),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for category")
+	})
+}
+
+func TestVariablesUpdateOptionsValid(t *testing.T) {
+	t.Run("without a key", func(t *testing.T) {
+		options := VariableUpdateOptions{
+			Value: String("bar"),
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with an empty key", func(t *testing.T) {
+		options := VariableUpdateOptions{
+			Key: String(""),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for key")
+	})
+}
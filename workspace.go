@@ -1,11 +1,16 @@
 package tfe
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"github.com/svanharmelen/jsonapi"
 )
 
 // Compile-time proof of interface implementation.
@@ -25,21 +30,42 @@ type Workspaces interface {
 	// Read a workspace by its name.
 	Read(ctx context.Context, organization string, workspace string) (*Workspace, error)
 
+	// ReadWithOptions reads a workspace by its name using the given options.
+	ReadWithOptions(ctx context.Context, organization string, workspace string, options WorkspaceReadOptions) (*Workspace, error)
+
 	// ReadByID reads a workspace by its ID.
 	ReadByID(ctx context.Context, workspaceID string) (*Workspace, error)
 
+	// ReadByIDWithOptions reads a workspace by its ID using the given
+	// options.
+	ReadByIDWithOptions(ctx context.Context, workspaceID string, options WorkspaceReadOptions) (*Workspace, error)
+
+	// Readme returns the raw markdown of the README of a workspace.
+	Readme(ctx context.Context, workspaceID string) (io.Reader, error)
+
 	// Update settings of an existing workspace.
 	Update(ctx context.Context, organization string, workspace string, options WorkspaceUpdateOptions) (*Workspace, error)
 
 	// UpdateByID updates the settings of an existing workspace.
 	UpdateByID(ctx context.Context, workspaceID string, options WorkspaceUpdateOptions) (*Workspace, error)
 
-	// Delete a workspace by its name.
+	// Delete a workspace by its name, even if it still manages resources.
+	// Use SafeDelete to prevent losing track of existing infrastructure.
 	Delete(ctx context.Context, organization string, workspace string) error
 
-	// DeleteByID deletes a workspace by its ID.
+	// DeleteByID deletes a workspace by its ID, even if it still manages
+	// resources. Use SafeDeleteByID to prevent losing track of existing
+	// infrastructure.
 	DeleteByID(ctx context.Context, workspaceID string) error
 
+	// SafeDelete a workspace by its name, only if it does not manage any
+	// resources.
+	SafeDelete(ctx context.Context, organization string, workspace string) error
+
+	// SafeDeleteByID deletes a workspace by its ID, only if it does not
+	// manage any resources.
+	SafeDeleteByID(ctx context.Context, workspaceID string) error
+
 	// RemoveVCSConnection from a workspace.
 	RemoveVCSConnection(ctx context.Context, organization, workspace string) (*Workspace, error)
 
@@ -60,6 +86,46 @@ type Workspaces interface {
 
 	// UnassignSSHKey from a workspace.
 	UnassignSSHKey(ctx context.Context, workspaceID string) (*Workspace, error)
+
+	// ListRemoteStateConsumers lists the workspaces that can access the
+	// state of a workspace.
+	ListRemoteStateConsumers(ctx context.Context, workspaceID string, options RemoteStateConsumersListOptions) (*WorkspaceList, error)
+
+	// AddRemoteStateConsumers adds remote state consumers to a workspace.
+	AddRemoteStateConsumers(ctx context.Context, workspaceID string, options WorkspaceAddRemoteStateConsumersOptions) error
+
+	// RemoveRemoteStateConsumers removes remote state consumers from a
+	// workspace.
+	RemoveRemoteStateConsumers(ctx context.Context, workspaceID string, options WorkspaceRemoveRemoteStateConsumersOptions) error
+
+	// UpdateRemoteStateConsumers replaces all the remote state consumers of
+	// a workspace.
+	UpdateRemoteStateConsumers(ctx context.Context, workspaceID string, options WorkspaceUpdateRemoteStateConsumersOptions) error
+
+	// ListTags lists the tags of a workspace.
+	ListTags(ctx context.Context, workspaceID string, options WorkspaceTagListOptions) (*TagList, error)
+
+	// AddTags adds tags to a workspace.
+	AddTags(ctx context.Context, workspaceID string, options WorkspaceAddTagsOptions) error
+
+	// RemoveTags removes tags from a workspace.
+	RemoveTags(ctx context.Context, workspaceID string, options WorkspaceRemoveTagsOptions) error
+
+	// ReadDataRetentionPolicy reads the data retention policy of a
+	// workspace.
+	ReadDataRetentionPolicy(ctx context.Context, workspaceID string) (*DataRetentionPolicyChoice, error)
+
+	// SetDataRetentionPolicyDeleteOlder sets a data retention policy that
+	// deletes data older than the given number of days.
+	SetDataRetentionPolicyDeleteOlder(ctx context.Context, workspaceID string, options DataRetentionPolicyDeleteOlderSetOptions) (*DataRetentionPolicyDeleteOlder, error)
+
+	// SetDataRetentionPolicyDontDelete sets a data retention policy that
+	// never deletes any data.
+	SetDataRetentionPolicyDontDelete(ctx context.Context, workspaceID string, options DataRetentionPolicyDontDeleteSetOptions) (*DataRetentionPolicyDontDelete, error)
+
+	// DeleteDataRetentionPolicy deletes the data retention policy of a
+	// workspace, so it inherits the policy of its organization.
+	DeleteDataRetentionPolicy(ctx context.Context, workspaceID string) error
 }
 
 // workspaces implements Workspaces.
@@ -70,9 +136,17 @@ type workspaces struct {
 // WorkspaceList represents a list of workspaces.
 type WorkspaceList struct {
 	*Pagination
+	Meta  *WorkspaceListMeta
 	Items []*Workspace
 }
 
+// WorkspaceListMeta represents the meta details of a list of workspaces.
+type WorkspaceListMeta struct {
+	// The number of workspaces per status of their current run, including
+	// the "total" count.
+	StatusCounts map[string]int `json:"status-counts"`
+}
+
 // Workspace represents a Terraform Enterprise workspace.
 type Workspace struct {
 	ID                   string                `jsonapi:"primary,workspaces"`
@@ -80,7 +154,9 @@ type Workspace struct {
 	AutoApply            bool                  `jsonapi:"attr,auto-apply"`
 	CanQueueDestroyPlan  bool                  `jsonapi:"attr,can-queue-destroy-plan"`
 	CreatedAt            time.Time             `jsonapi:"attr,created-at,iso8601"`
+	Description          string                `jsonapi:"attr,description"`
 	Environment          string                `jsonapi:"attr,environment"`
+	ExecutionMode        ExecutionModeType     `jsonapi:"attr,execution-mode"`
 	FileTriggersEnabled  bool                  `jsonapi:"attr,file-triggers-enabled"`
 	Locked               bool                  `jsonapi:"attr,locked"`
 	MigrationEnvironment string                `jsonapi:"attr,migration-environment"`
@@ -89,14 +165,32 @@ type Workspace struct {
 	Permissions          *WorkspacePermissions `jsonapi:"attr,permissions"`
 	QueueAllRuns         bool                  `jsonapi:"attr,queue-all-runs"`
 	TerraformVersion     string                `jsonapi:"attr,terraform-version"`
+	TriggerPatterns      []string              `jsonapi:"attr,trigger-patterns"`
 	TriggerPrefixes      []string              `jsonapi:"attr,trigger-prefixes"`
 	VCSRepo              *VCSRepo              `jsonapi:"attr,vcs-repo"`
 	WorkingDirectory     string                `jsonapi:"attr,working-directory"`
 
 	// Relations
-	CurrentRun   *Run          `jsonapi:"relation,current-run"`
-	Organization *Organization `jsonapi:"relation,organization"`
-	SSHKey       *SSHKey       `jsonapi:"relation,ssh-key"`
+	CurrentConfigurationVersion *ConfigurationVersion `jsonapi:"relation,current-configuration-version"`
+	CurrentRun                  *Run                  `jsonapi:"relation,current-run"`
+	Organization                *Organization         `jsonapi:"relation,organization"`
+	Outputs                     []*WorkspaceOutput    `jsonapi:"relation,outputs"`
+	Project                     *Project              `jsonapi:"relation,project"`
+	SSHKey                      *SSHKey               `jsonapi:"relation,ssh-key"`
+}
+
+// WorkspaceOutput represents an output of the current state version of a
+// workspace.
+type WorkspaceOutput struct {
+	ID        string `jsonapi:"primary,workspace-outputs"`
+	Name      string `jsonapi:"attr,name"`
+	Sensitive bool   `jsonapi:"attr,sensitive"`
+	Type      string `jsonapi:"attr,output-type"`
+
+	// The value of the output, decoded the same way as the Value of a
+	// StateVersionOutput. The value of a sensitive output is nil, as it
+	// is never included.
+	Value interface{}
 }
 
 // VCSRepo contains the configuration of a VCS integration.
@@ -105,6 +199,7 @@ type VCSRepo struct {
 	Identifier        string `json:"identifier"`
 	IngressSubmodules bool   `json:"ingress-submodules"`
 	OAuthTokenID      string `json:"oauth-token-id"`
+	TagsRegex         string `json:"tags-regex"`
 }
 
 // WorkspaceActions represents the workspace actions.
@@ -126,12 +221,46 @@ type WorkspacePermissions struct {
 	CanUpdateVariable bool `json:"can-update-variable"`
 }
 
+// WSIncludeOpt represents the available options for include query params.
+// https://www.terraform.io/docs/enterprise/api/workspaces.html#available-related-resources
+type WSIncludeOpt string
+
+// List all available workspace include options.
+const (
+	WSOrganization            WSIncludeOpt = "organization"
+	WSCurrentRun              WSIncludeOpt = "current_run"
+	WSCurrentRunPlan          WSIncludeOpt = "current_run.plan"
+	WSCurrentRunConfigVer     WSIncludeOpt = "current_run.configuration_version"
+	WSCurrentConfigVer        WSIncludeOpt = "current_configuration_version"
+	WSCurrentConfigVerIngress WSIncludeOpt = "current_configuration_version.ingress_attributes"
+	WSOutputs                 WSIncludeOpt = "outputs"
+)
+
 // WorkspaceListOptions represents the options for listing workspaces.
 type WorkspaceListOptions struct {
 	ListOptions
 
 	// A search string (partial workspace name) used to filter the results.
 	Search *string `url:"search[name],omitempty"`
+
+	// A list of tags, of which the workspaces must have all.
+	Tags []string `url:"search[tags],omitempty,comma"`
+
+	// A list of tags, of which the workspaces must have none.
+	ExcludeTags []string `url:"search[exclude-tags],omitempty,comma"`
+
+	// A workspace name with a leading and/or trailing * used as a wildcard,
+	// e.g. "*-prod" or "app-*", used to filter the results.
+	WildcardName *string `url:"search[wildcard-name],omitempty"`
+
+	// The status of the current run of the workspaces.
+	CurrentRunStatus RunStatus `url:"filter[current-run][status],omitempty"`
+
+	// A list of relations to include.
+	Include []WSIncludeOpt `url:"include,omitempty,comma"`
+
+	// A sparse fieldset to limit the returned attributes.
+	Fields Fieldsets `url:"fields,omitempty"`
 }
 
 // List all the workspaces within an organization.
@@ -140,6 +269,15 @@ func (s *workspaces) List(ctx context.Context, organization string, options Work
 		return nil, errors.New("invalid value for organization")
 	}
 
+	// The API handles an empty filter differently from a missing one, so
+	// make sure empty filters are not sent at all.
+	if options.Search != nil && *options.Search == "" {
+		options.Search = nil
+	}
+	if options.WildcardName != nil && *options.WildcardName == "" {
+		options.WildcardName = nil
+	}
+
 	u := fmt.Sprintf("organizations/%s/workspaces", url.QueryEscape(organization))
 	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
@@ -147,10 +285,25 @@ func (s *workspaces) List(ctx context.Context, organization string, options Work
 	}
 
 	wl := &WorkspaceList{}
-	err = s.client.do(ctx, req, wl)
-	if err != nil {
+	if !includesOutputs(options.Include) {
+		if err := s.client.do(ctx, req, wl); err != nil {
+			return nil, err
+		}
+		return wl, nil
+	}
+
+	body := &bytes.Buffer{}
+	if err := s.client.do(ctx, req, body); err != nil {
+		return nil, err
+	}
+	if err := unmarshalResponse(bytes.NewReader(body.Bytes()), int64(body.Len()), wl); err != nil {
 		return nil, err
 	}
+	for _, w := range wl.Items {
+		if err := decodeWorkspaceOutputValues(body.Bytes(), w.Outputs); err != nil {
+			return nil, err
+		}
+	}
 
 	return wl, nil
 }
@@ -163,10 +316,17 @@ type WorkspaceCreateOptions struct {
 	// Whether to automatically apply changes when a Terraform plan is successful.
 	AutoApply *bool `jsonapi:"attr,auto-apply,omitempty"`
 
+	// A description for the workspace.
+	Description *string `jsonapi:"attr,description,omitempty"`
+
+	// Which execution mode to use. When set, this takes precedence over
+	// Operations.
+	ExecutionMode *ExecutionModeType `jsonapi:"attr,execution-mode,omitempty"`
+
 	// Whether to filter runs based on the changed files in a VCS push. If
-	// enabled, the working directory and trigger prefixes describe a set of
-	// paths which must contain changes for a VCS push to trigger a run. If
-	// disabled, any push will trigger a run.
+	// enabled, the working directory and trigger prefixes or patterns describe
+	// a set of paths which must contain changes for a VCS push to trigger a
+	// run. If disabled, any push will trigger a run.
 	FileTriggersEnabled *bool `jsonapi:"attr,file-triggers-enabled,omitempty"`
 
 	// The legacy TFE environment to use as the source of the migration, in the
@@ -190,8 +350,14 @@ type WorkspaceCreateOptions struct {
 	// workspace, the latest version is selected unless otherwise specified.
 	TerraformVersion *string `jsonapi:"attr,terraform-version,omitempty"`
 
+	// List of glob patterns that describe the files to be tracked for
+	// changes. Cannot be combined with TriggerPrefixes. See
+	// FileTriggersEnabled above for more details.
+	TriggerPatterns []string `jsonapi:"attr,trigger-patterns,omitempty"`
+
 	// List of repository-root-relative paths which list all locations to be
-	// tracked for changes. See FileTriggersEnabled above for more details.
+	// tracked for changes. Cannot be combined with TriggerPatterns. See
+	// FileTriggersEnabled above for more details.
 	TriggerPrefixes []string `jsonapi:"attr,trigger-prefixes,omitempty"`
 
 	// Settings for the workspace's VCS repository. If omitted, the workspace is
@@ -203,6 +369,10 @@ type WorkspaceCreateOptions struct {
 	// root of your repository and is typically set to a subdirectory matching the
 	// environment when multiple environments exist within the same repository.
 	WorkingDirectory *string `jsonapi:"attr,working-directory,omitempty"`
+
+	// The project to create the workspace in. If omitted, the workspace is
+	// created in the default project of the organization.
+	Project *Project `jsonapi:"relation,project,omitempty"`
 }
 
 // VCSRepoOptions represents the configuration options of a VCS integration.
@@ -211,6 +381,7 @@ type VCSRepoOptions struct {
 	Identifier        *string `json:"identifier,omitempty"`
 	IngressSubmodules *bool   `json:"ingress-submodules,omitempty"`
 	OAuthTokenID      *string `json:"oauth-token-id,omitempty"`
+	TagsRegex         *string `json:"tags-regex,omitempty"`
 }
 
 func (o WorkspaceCreateOptions) valid() error {
@@ -220,6 +391,12 @@ func (o WorkspaceCreateOptions) valid() error {
 	if !validStringID(o.Name) {
 		return errors.New("invalid value for name")
 	}
+	if o.ExecutionMode != nil && !validExecutionMode(*o.ExecutionMode) {
+		return errors.New("invalid value for execution mode")
+	}
+	if len(o.TriggerPrefixes) > 0 && len(o.TriggerPatterns) > 0 {
+		return errors.New("only one of trigger prefixes or trigger patterns can be set")
+	}
 	return nil
 }
 
@@ -252,6 +429,17 @@ func (s *workspaces) Create(ctx context.Context, organization string, options Wo
 
 // Read a workspace by its name.
 func (s *workspaces) Read(ctx context.Context, organization, workspace string) (*Workspace, error) {
+	return s.ReadWithOptions(ctx, organization, workspace, WorkspaceReadOptions{})
+}
+
+// WorkspaceReadOptions represents the options for reading a workspace.
+type WorkspaceReadOptions struct {
+	// A list of relations to include.
+	Include []WSIncludeOpt `url:"include,omitempty,comma"`
+}
+
+// ReadWithOptions reads a workspace by its name using the given options.
+func (s *workspaces) ReadWithOptions(ctx context.Context, organization, workspace string, options WorkspaceReadOptions) (*Workspace, error) {
 	if !validStringID(&organization) {
 		return nil, errors.New("invalid value for organization")
 	}
@@ -264,39 +452,114 @@ func (s *workspaces) Read(ctx context.Context, organization, workspace string) (
 		url.QueryEscape(organization),
 		url.QueryEscape(workspace),
 	)
-	req, err := s.client.newRequest("GET", u, nil)
+	req, err := s.client.newRequest("GET", u, &options)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.doWorkspace(ctx, req, options.Include)
+}
+
+// doWorkspace sends the request and decodes the returned workspace. If the
+// outputs are included, the response is buffered to also decode their
+// values.
+func (s *workspaces) doWorkspace(ctx context.Context, req *retryablehttp.Request, include []WSIncludeOpt) (*Workspace, error) {
 	w := &Workspace{}
-	err = s.client.do(ctx, req, w)
-	if err != nil {
+	if !includesOutputs(include) {
+		if err := s.client.do(ctx, req, w); err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+
+	body := &bytes.Buffer{}
+	if err := s.client.do(ctx, req, body); err != nil {
+		return nil, err
+	}
+	if err := jsonapi.UnmarshalPayload(bytes.NewReader(body.Bytes()), w); err != nil {
+		return nil, err
+	}
+	if err := decodeWorkspaceOutputValues(body.Bytes(), w.Outputs); err != nil {
 		return nil, err
 	}
 
 	return w, nil
 }
 
+// includesOutputs reports if the outputs of the workspaces are included.
+func includesOutputs(include []WSIncludeOpt) bool {
+	for _, i := range include {
+		if i == WSOutputs {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeWorkspaceOutputValues sets the values of the outputs, using the
+// workspace outputs included in the JSONAPI document in body.
+func decodeWorkspaceOutputValues(body []byte, outputs []*WorkspaceOutput) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+
+	byID, err := decodeOutputResources(body, "workspace-outputs")
+	if err != nil {
+		return err
+	}
+
+	for _, o := range outputs {
+		if o == nil {
+			continue
+		}
+		if r, ok := byID[o.ID]; ok {
+			o.Value = r.Attributes.Value
+		}
+	}
+
+	return nil
+}
+
 // ReadByID reads a workspace by its ID.
 func (s *workspaces) ReadByID(ctx context.Context, workspaceID string) (*Workspace, error) {
-	if !validStringID(&workspaceID) {
-		return nil, errors.New("invalid value for workspace ID")
+	return s.ReadByIDWithOptions(ctx, workspaceID, WorkspaceReadOptions{})
+}
+
+// ReadByIDWithOptions reads a workspace by its ID using the given options.
+func (s *workspaces) ReadByIDWithOptions(ctx context.Context, workspaceID string, options WorkspaceReadOptions) (*Workspace, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New(`invalid value for workspace ID: must start with "ws-"`)
 	}
 
 	u := fmt.Sprintf("workspaces/%s", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("GET", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.doWorkspace(ctx, req, options.Include)
+}
+
+// Readme returns the raw markdown of the README of a workspace. If the
+// workspace has no README, an empty reader is returned.
+func (s *workspaces) Readme(ctx context.Context, workspaceID string) (io.Reader, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New(`invalid value for workspace ID: must start with "ws-"`)
+	}
+
+	u := fmt.Sprintf("workspaces/%s/readme", url.QueryEscape(workspaceID))
 	req, err := s.client.newRequest("GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	w := &Workspace{}
-	err = s.client.do(ctx, req, w)
+	buf := &bytes.Buffer{}
+	err = s.client.do(ctx, req, buf)
 	if err != nil {
 		return nil, err
 	}
 
-	return w, nil
+	return buf, nil
 }
 
 // WorkspaceUpdateOptions represents the options for updating a workspace.
@@ -313,10 +576,17 @@ type WorkspaceUpdateOptions struct {
 	// API and UI.
 	Name *string `jsonapi:"attr,name,omitempty"`
 
+	// A new description for the workspace.
+	Description *string `jsonapi:"attr,description,omitempty"`
+
+	// Which execution mode to use. When set, this takes precedence over
+	// Operations.
+	ExecutionMode *ExecutionModeType `jsonapi:"attr,execution-mode,omitempty"`
+
 	// Whether to filter runs based on the changed files in a VCS push. If
-	// enabled, the working directory and trigger prefixes describe a set of
-	// paths which must contain changes for a VCS push to trigger a run. If
-	// disabled, any push will trigger a run.
+	// enabled, the working directory and trigger prefixes or patterns describe
+	// a set of paths which must contain changes for a VCS push to trigger a
+	// run. If disabled, any push will trigger a run.
 	FileTriggersEnabled *bool `jsonapi:"attr,file-triggers-enabled,omitempty"`
 
 	// Whether the workspace will use remote or local execution mode.
@@ -329,22 +599,84 @@ type WorkspaceUpdateOptions struct {
 	// The version of Terraform to use for this workspace.
 	TerraformVersion *string `jsonapi:"attr,terraform-version,omitempty"`
 
+	// List of glob patterns that describe the files to be tracked for
+	// changes. Cannot be combined with TriggerPrefixes. A nil list leaves the
+	// patterns unchanged, use an empty non-nil list to remove all of them.
+	// See FileTriggersEnabled above for more details.
+	TriggerPatterns []string `jsonapi:"attr,trigger-patterns,omitempty"`
+
 	// List of repository-root-relative paths which list all locations to be
-	// tracked for changes. See FileTriggersEnabled above for more details.
+	// tracked for changes. Cannot be combined with TriggerPatterns. A nil
+	// list leaves the prefixes unchanged, use an empty non-nil list to remove
+	// all of them. See FileTriggersEnabled above for more details.
 	TriggerPrefixes []string `jsonapi:"attr,trigger-prefixes,omitempty"`
 
-	// To delete a workspace's existing VCS repo, specify null instead of an
-	// object. To modify a workspace's existing VCS repo, include whichever of
-	// the keys below you wish to modify. To add a new VCS repo to a workspace
-	// that didn't previously have one, include at least the oauth-token-id and
-	// identifier keys.
+	// To modify a workspace's existing VCS repo, include whichever of the keys
+	// below you wish to modify. To add a new VCS repo to a workspace that
+	// didn't previously have one, include at least the oauth-token-id and
+	// identifier keys. To delete the existing VCS repo, use
+	// RemoveVCSConnection.
 	VCSRepo *VCSRepoOptions `jsonapi:"attr,vcs-repo,omitempty"`
 
+	// Whether to remove the workspace's existing VCS repo, which sends an
+	// explicit null value. Cannot be combined with VCSRepo.
+	//
+	// Deprecated: Use RemoveVCSConnection or RemoveVCSConnectionByID
+	// instead.
+	RemoveVCSRepo bool
+
 	// A relative path that Terraform will execute within. This defaults to the
 	// root of your repository and is typically set to a subdirectory matching
 	// the environment when multiple environments exist within the same
 	// repository.
 	WorkingDirectory *string `jsonapi:"attr,working-directory,omitempty"`
+
+	// The project to move the workspace to.
+	Project *Project `jsonapi:"relation,project,omitempty"`
+}
+
+func (o WorkspaceUpdateOptions) valid() error {
+	if o.Name != nil && !validStringID(o.Name) {
+		return errors.New("invalid value for name")
+	}
+	if o.ExecutionMode != nil && !validExecutionMode(*o.ExecutionMode) {
+		return errors.New("invalid value for execution mode")
+	}
+	if len(o.TriggerPrefixes) > 0 && len(o.TriggerPatterns) > 0 {
+		return errors.New("only one of trigger prefixes or trigger patterns can be set")
+	}
+	if o.RemoveVCSRepo && o.VCSRepo != nil {
+		return errors.New("only one of vcs repo or remove vcs repo can be set")
+	}
+	return nil
+}
+
+// payload returns the request body for the update. The JSONAPI encoder
+// omits a nil VCS repo, so when the VCS repo should be removed the encoded
+// document is amended with the same explicit null value that
+// RemoveVCSConnection sends.
+func (o *WorkspaceUpdateOptions) payload() (interface{}, error) {
+	if !o.RemoveVCSRepo {
+		return o, nil
+	}
+
+	p, err := jsonapi.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	one, ok := p.(*jsonapi.OnePayload)
+	if !ok {
+		return nil, fmt.Errorf("unexpected payload type %T", p)
+	}
+	if one.Data.Attributes == nil {
+		one.Data.Attributes = make(map[string]interface{})
+	}
+	one.Data.Attributes["vcs-repo"] = nil
+
+	// Relations are sent as resource identifiers only.
+	one.Included = nil
+
+	return one, nil
 }
 
 // Update settings of an existing workspace.
@@ -355,16 +687,24 @@ func (s *workspaces) Update(ctx context.Context, organization, workspace string,
 	if !validStringID(&workspace) {
 		return nil, errors.New("invalid value for workspace")
 	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
 
 	// Make sure we don't send a user provided ID.
 	options.ID = ""
 
+	payload, err := options.payload()
+	if err != nil {
+		return nil, err
+	}
+
 	u := fmt.Sprintf(
 		"organizations/%s/workspaces/%s",
 		url.QueryEscape(organization),
 		url.QueryEscape(workspace),
 	)
-	req, err := s.client.newRequest("PATCH", u, &options)
+	req, err := s.client.newRequest("PATCH", u, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -380,15 +720,23 @@ func (s *workspaces) Update(ctx context.Context, organization, workspace string,
 
 // UpdateByID updates the settings of an existing workspace.
 func (s *workspaces) UpdateByID(ctx context.Context, workspaceID string, options WorkspaceUpdateOptions) (*Workspace, error) {
-	if !validStringID(&workspaceID) {
-		return nil, errors.New("invalid value for workspace ID")
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New(`invalid value for workspace ID: must start with "ws-"`)
+	}
+	if err := options.valid(); err != nil {
+		return nil, err
 	}
 
 	// Make sure we don't send a user provided ID.
 	options.ID = ""
 
+	payload, err := options.payload()
+	if err != nil {
+		return nil, err
+	}
+
 	u := fmt.Sprintf("workspaces/%s", url.QueryEscape(workspaceID))
-	req, err := s.client.newRequest("PATCH", u, &options)
+	req, err := s.client.newRequest("PATCH", u, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -402,7 +750,9 @@ func (s *workspaces) UpdateByID(ctx context.Context, workspaceID string, options
 	return w, nil
 }
 
-// Delete a workspace by its name.
+// Delete a workspace by its name, even if it still manages resources. The
+// state of those resources is deleted as well, so prefer SafeDelete unless
+// the resources are managed elsewhere or have been destroyed.
 func (s *workspaces) Delete(ctx context.Context, organization, workspace string) error {
 	if !validStringID(&organization) {
 		return errors.New("invalid value for organization")
@@ -424,10 +774,13 @@ func (s *workspaces) Delete(ctx context.Context, organization, workspace string)
 	return s.client.do(ctx, req, nil)
 }
 
-// DeleteByID deletes a workspace by its ID.
+// DeleteByID deletes a workspace by its ID, even if it still manages
+// resources. The state of those resources is deleted as well, so prefer
+// SafeDeleteByID unless the resources are managed elsewhere or have been
+// destroyed.
 func (s *workspaces) DeleteByID(ctx context.Context, workspaceID string) error {
-	if !validStringID(&workspaceID) {
-		return errors.New("invalid value for workspace ID")
+	if !validWorkspaceID(&workspaceID) {
+		return errors.New(`invalid value for workspace ID: must start with "ws-"`)
 	}
 
 	u := fmt.Sprintf("workspaces/%s", url.QueryEscape(workspaceID))
@@ -439,6 +792,47 @@ func (s *workspaces) DeleteByID(ctx context.Context, workspaceID string) error {
 	return s.client.do(ctx, req, nil)
 }
 
+// SafeDelete a workspace by its name, only if it does not manage any
+// resources. If it does, an error wrapping ErrWorkspaceNotSafeToDelete is
+// returned.
+func (s *workspaces) SafeDelete(ctx context.Context, organization, workspace string) error {
+	if !validStringID(&organization) {
+		return errors.New("invalid value for organization")
+	}
+	if !validStringID(&workspace) {
+		return errors.New("invalid value for workspace")
+	}
+
+	u := fmt.Sprintf(
+		"organizations/%s/workspaces/%s/actions/safe-delete",
+		url.QueryEscape(organization),
+		url.QueryEscape(workspace),
+	)
+	req, err := s.client.newRequest("POST", u, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.do(ctx, req, nil)
+}
+
+// SafeDeleteByID deletes a workspace by its ID, only if it does not manage
+// any resources. If it does, an error wrapping ErrWorkspaceNotSafeToDelete
+// is returned.
+func (s *workspaces) SafeDeleteByID(ctx context.Context, workspaceID string) error {
+	if !validWorkspaceID(&workspaceID) {
+		return errors.New(`invalid value for workspace ID: must start with "ws-"`)
+	}
+
+	u := fmt.Sprintf("workspaces/%s/actions/safe-delete", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("POST", u, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.do(ctx, req, nil)
+}
+
 // workspaceRemoveVCSConnectionOptions
 type workspaceRemoveVCSConnectionOptions struct {
 	ID      string          `jsonapi:"primary,workspaces"`
@@ -476,7 +870,7 @@ func (s *workspaces) RemoveVCSConnection(ctx context.Context, organization, work
 
 // RemoveVCSConnectionByID removes a VCS connection from a workspace.
 func (s *workspaces) RemoveVCSConnectionByID(ctx context.Context, workspaceID string) (*Workspace, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -504,7 +898,7 @@ type WorkspaceLockOptions struct {
 
 // Lock a workspace by its ID.
 func (s *workspaces) Lock(ctx context.Context, workspaceID string, options WorkspaceLockOptions) (*Workspace, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -525,7 +919,7 @@ func (s *workspaces) Lock(ctx context.Context, workspaceID string, options Works
 
 // Unlock a workspace by its ID.
 func (s *workspaces) Unlock(ctx context.Context, workspaceID string) (*Workspace, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -546,7 +940,7 @@ func (s *workspaces) Unlock(ctx context.Context, workspaceID string) (*Workspace
 
 // ForceUnlock a workspace by its ID.
 func (s *workspaces) ForceUnlock(ctx context.Context, workspaceID string) (*Workspace, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -587,7 +981,7 @@ func (o WorkspaceAssignSSHKeyOptions) valid() error {
 
 // AssignSSHKey to a workspace.
 func (s *workspaces) AssignSSHKey(ctx context.Context, workspaceID string, options WorkspaceAssignSSHKeyOptions) (*Workspace, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 	if err := options.valid(); err != nil {
@@ -624,7 +1018,7 @@ type workspaceUnassignSSHKeyOptions struct {
 
 // UnassignSSHKey from a workspace.
 func (s *workspaces) UnassignSSHKey(ctx context.Context, workspaceID string) (*Workspace, error) {
-	if !validStringID(&workspaceID) {
+	if !validWorkspaceID(&workspaceID) {
 		return nil, errors.New("invalid value for workspace ID")
 	}
 
@@ -642,3 +1036,345 @@ func (s *workspaces) UnassignSSHKey(ctx context.Context, workspaceID string) (*W
 
 	return w, nil
 }
+
+// RemoteStateConsumersListOptions represents the options for listing the
+// remote state consumers of a workspace.
+type RemoteStateConsumersListOptions struct {
+	ListOptions
+}
+
+// ListRemoteStateConsumers lists the workspaces that can access the state
+// of a workspace.
+func (s *workspaces) ListRemoteStateConsumers(ctx context.Context, workspaceID string, options RemoteStateConsumersListOptions) (*WorkspaceList, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/remote-state-consumers", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("GET", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	wl := &WorkspaceList{}
+	err = s.client.do(ctx, req, wl)
+	if err != nil {
+		return nil, err
+	}
+
+	return wl, nil
+}
+
+// WorkspaceAddRemoteStateConsumersOptions represents the options for adding
+// remote state consumers to a workspace.
+type WorkspaceAddRemoteStateConsumersOptions struct {
+	// The workspaces to add as remote state consumers. Only the IDs of the
+	// workspaces are used.
+	Workspaces []*Workspace
+}
+
+func (o WorkspaceAddRemoteStateConsumersOptions) valid() error {
+	if o.Workspaces == nil {
+		return errors.New("workspaces is required")
+	}
+	if len(o.Workspaces) == 0 {
+		return errors.New("must provide at least one workspace")
+	}
+	return nil
+}
+
+// AddRemoteStateConsumers adds remote state consumers to a workspace.
+func (s *workspaces) AddRemoteStateConsumers(ctx context.Context, workspaceID string, options WorkspaceAddRemoteStateConsumersOptions) error {
+	if !validWorkspaceID(&workspaceID) {
+		return errors.New("invalid value for workspace ID")
+	}
+	if err := options.valid(); err != nil {
+		return err
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/remote-state-consumers", url.QueryEscape(workspaceID))
+	req, err := s.client.newRelationshipRequest("POST", u, options.Workspaces)
+	if err != nil {
+		return err
+	}
+
+	// Adding workspaces that are already consumers has no effect.
+	return s.client.do(ctx, withRetryable(req), nil)
+}
+
+// WorkspaceRemoveRemoteStateConsumersOptions represents the options for
+// removing remote state consumers from a workspace.
+type WorkspaceRemoveRemoteStateConsumersOptions struct {
+	// The workspaces to remove as remote state consumers. Only the IDs of
+	// the workspaces are used.
+	Workspaces []*Workspace
+}
+
+func (o WorkspaceRemoveRemoteStateConsumersOptions) valid() error {
+	if o.Workspaces == nil {
+		return errors.New("workspaces is required")
+	}
+	if len(o.Workspaces) == 0 {
+		return errors.New("must provide at least one workspace")
+	}
+	return nil
+}
+
+// RemoveRemoteStateConsumers removes remote state consumers from a
+// workspace.
+func (s *workspaces) RemoveRemoteStateConsumers(ctx context.Context, workspaceID string, options WorkspaceRemoveRemoteStateConsumersOptions) error {
+	if !validWorkspaceID(&workspaceID) {
+		return errors.New("invalid value for workspace ID")
+	}
+	if err := options.valid(); err != nil {
+		return err
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/remote-state-consumers", url.QueryEscape(workspaceID))
+	req, err := s.client.newRelationshipRequest("DELETE", u, options.Workspaces)
+	if err != nil {
+		return err
+	}
+
+	return s.client.do(ctx, req, nil)
+}
+
+// WorkspaceUpdateRemoteStateConsumersOptions represents the options for
+// replacing the remote state consumers of a workspace.
+type WorkspaceUpdateRemoteStateConsumersOptions struct {
+	// The workspaces that replace the current remote state consumers. Only
+	// the IDs of the workspaces are used. Use an empty slice to remove all
+	// the remote state consumers.
+	Workspaces []*Workspace
+}
+
+func (o WorkspaceUpdateRemoteStateConsumersOptions) valid() error {
+	if o.Workspaces == nil {
+		return errors.New("workspaces is required")
+	}
+	return nil
+}
+
+// UpdateRemoteStateConsumers replaces all the remote state consumers of a
+// workspace.
+func (s *workspaces) UpdateRemoteStateConsumers(ctx context.Context, workspaceID string, options WorkspaceUpdateRemoteStateConsumersOptions) error {
+	if !validWorkspaceID(&workspaceID) {
+		return errors.New("invalid value for workspace ID")
+	}
+	if err := options.valid(); err != nil {
+		return err
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/remote-state-consumers", url.QueryEscape(workspaceID))
+	req, err := s.client.newRelationshipRequest("PATCH", u, options.Workspaces)
+	if err != nil {
+		return err
+	}
+
+	// Replacing the consumers with the same set has no effect.
+	return s.client.do(ctx, withRetryable(req), nil)
+}
+
+// WorkspaceTagListOptions represents the options for listing the tags of a
+// workspace.
+type WorkspaceTagListOptions struct {
+	ListOptions
+}
+
+// ListTags lists the tags of a workspace.
+func (s *workspaces) ListTags(ctx context.Context, workspaceID string, options WorkspaceTagListOptions) (*TagList, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/tags", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("GET", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	tl := &TagList{}
+	err = s.client.do(ctx, req, tl)
+	if err != nil {
+		return nil, err
+	}
+
+	return tl, nil
+}
+
+// WorkspaceAddTagsOptions represents the options for adding tags to a
+// workspace.
+type WorkspaceAddTagsOptions struct {
+	// The tags to add to the workspace. Tags without an ID are referenced
+	// by name and are created if they do not exist yet.
+	Tags []*Tag
+}
+
+func (o WorkspaceAddTagsOptions) valid() error {
+	return validTags(o.Tags)
+}
+
+// AddTags adds tags to a workspace.
+func (s *workspaces) AddTags(ctx context.Context, workspaceID string, options WorkspaceAddTagsOptions) error {
+	if !validWorkspaceID(&workspaceID) {
+		return errors.New("invalid value for workspace ID")
+	}
+	if err := options.valid(); err != nil {
+		return err
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/tags", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("POST", u, newTagsPayload(options.Tags))
+	if err != nil {
+		return err
+	}
+
+	// Adding tags that are already attached has no effect.
+	return s.client.do(ctx, withRetryable(req), nil)
+}
+
+// WorkspaceRemoveTagsOptions represents the options for removing tags from
+// a workspace.
+type WorkspaceRemoveTagsOptions struct {
+	// The tags to remove from the workspace, referenced by ID or by name.
+	Tags []*Tag
+}
+
+func (o WorkspaceRemoveTagsOptions) valid() error {
+	return validTags(o.Tags)
+}
+
+// RemoveTags removes tags from a workspace.
+func (s *workspaces) RemoveTags(ctx context.Context, workspaceID string, options WorkspaceRemoveTagsOptions) error {
+	if !validWorkspaceID(&workspaceID) {
+		return errors.New("invalid value for workspace ID")
+	}
+	if err := options.valid(); err != nil {
+		return err
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/tags", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("DELETE", u, newTagsPayload(options.Tags))
+	if err != nil {
+		return err
+	}
+
+	return s.client.do(ctx, req, nil)
+}
+
+// validTags checks if all the tags are referenced by a valid ID or name.
+func validTags(tags []*Tag) error {
+	if tags == nil {
+		return errors.New("tags is required")
+	}
+	if len(tags) == 0 {
+		return errors.New("must provide at least one tag")
+	}
+	for _, tag := range tags {
+		switch {
+		case tag == nil:
+			return errors.New("tags cannot contain nil values")
+		case tag.ID != "":
+			if !validStringID(&tag.ID) {
+				return errors.New("invalid value for tag ID")
+			}
+		case tag.Name == "":
+			return errors.New("tag ID or name is required")
+		case !validTagName(&tag.Name):
+			return fmt.Errorf("invalid value for tag name %q: must only contain lowercase letters, numbers, - and _", tag.Name)
+		}
+	}
+	return nil
+}
+
+// ReadDataRetentionPolicy reads the data retention policy of a workspace.
+// If the workspace has no policy of its own, an empty choice is returned.
+func (s *workspaces) ReadDataRetentionPolicy(ctx context.Context, workspaceID string) (*DataRetentionPolicyChoice, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/data-retention-policy", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	err = s.client.do(ctx, req, buf)
+	if err != nil {
+		return nil, s.client.dataRetentionPolicyError(err)
+	}
+
+	return unmarshalDataRetentionPolicy(buf.Bytes())
+}
+
+// SetDataRetentionPolicyDeleteOlder sets a data retention policy that
+// deletes data older than the given number of days, replacing any existing
+// policy of the workspace.
+func (s *workspaces) SetDataRetentionPolicyDeleteOlder(ctx context.Context, workspaceID string, options DataRetentionPolicyDeleteOlderSetOptions) (*DataRetentionPolicyDeleteOlder, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+	if err := options.valid(); err != nil {
+		return nil, err
+	}
+
+	// Make sure we don't send a user provided ID.
+	options.ID = ""
+
+	u := fmt.Sprintf("workspaces/%s/relationships/data-retention-policy", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("POST", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DataRetentionPolicyDeleteOlder{}
+	err = s.client.do(ctx, withRetryable(req), d)
+	if err != nil {
+		return nil, s.client.dataRetentionPolicyError(err)
+	}
+
+	return d, nil
+}
+
+// SetDataRetentionPolicyDontDelete sets a data retention policy that never
+// deletes any data, replacing any existing policy of the workspace.
+func (s *workspaces) SetDataRetentionPolicyDontDelete(ctx context.Context, workspaceID string, options DataRetentionPolicyDontDeleteSetOptions) (*DataRetentionPolicyDontDelete, error) {
+	if !validWorkspaceID(&workspaceID) {
+		return nil, errors.New("invalid value for workspace ID")
+	}
+
+	// Make sure we don't send a user provided ID.
+	options.ID = ""
+
+	u := fmt.Sprintf("workspaces/%s/relationships/data-retention-policy", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("POST", u, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DataRetentionPolicyDontDelete{}
+	err = s.client.do(ctx, withRetryable(req), d)
+	if err != nil {
+		return nil, s.client.dataRetentionPolicyError(err)
+	}
+
+	return d, nil
+}
+
+// DeleteDataRetentionPolicy deletes the data retention policy of a
+// workspace, so it inherits the policy of its organization.
+func (s *workspaces) DeleteDataRetentionPolicy(ctx context.Context, workspaceID string) error {
+	if !validWorkspaceID(&workspaceID) {
+		return errors.New("invalid value for workspace ID")
+	}
+
+	u := fmt.Sprintf("workspaces/%s/relationships/data-retention-policy", url.QueryEscape(workspaceID))
+	req, err := s.client.newRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.client.dataRetentionPolicyError(s.client.do(ctx, req, nil))
+}
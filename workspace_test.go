@@ -2,6 +2,10 @@ package tfe
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -77,6 +81,110 @@ func TestWorkspacesList(t *testing.T) {
 	})
 }
 
+func TestWorkspacesListFilters(t *testing.T) {
+	var query string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": []}`))
+	})
+	defer ts.Close()
+
+	cases := map[string]struct {
+		options WorkspaceListOptions
+		query   string
+	}{
+		"without filters": {
+			options: WorkspaceListOptions{},
+			query:   "",
+		},
+		"with empty filters": {
+			options: WorkspaceListOptions{
+				Search:           String(""),
+				Tags:             []string{},
+				ExcludeTags:      []string{},
+				WildcardName:     String(""),
+				CurrentRunStatus: "",
+			},
+			query: "",
+		},
+		"with a name search": {
+			options: WorkspaceListOptions{Search: String("app")},
+			query:   "search%5Bname%5D=app",
+		},
+		"with tag filters": {
+			options: WorkspaceListOptions{
+				Tags:        []string{"prod", "eu"},
+				ExcludeTags: []string{"legacy"},
+			},
+			query: "search%5Bexclude-tags%5D=legacy&search%5Btags%5D=prod%2Ceu",
+		},
+		"with a wildcard name": {
+			options: WorkspaceListOptions{WildcardName: String("*-prod")},
+			query:   "search%5Bwildcard-name%5D=%2A-prod",
+		},
+		"with all filters": {
+			options: WorkspaceListOptions{
+				ListOptions:      ListOptions{PageNumber: 2, PageSize: 10},
+				Search:           String("app"),
+				Tags:             []string{"prod"},
+				ExcludeTags:      []string{"legacy", "test"},
+				WildcardName:     String("app-*"),
+				CurrentRunStatus: RunErrored,
+			},
+			query: "filter%5Bcurrent-run%5D%5Bstatus%5D=errored&page%5Bnumber%5D=2&page%5Bsize%5D=10" +
+				"&search%5Bexclude-tags%5D=legacy%2Ctest&search%5Bname%5D=app&search%5Btags%5D=prod&search%5Bwildcard-name%5D=app-%2A",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.Workspaces.List(context.Background(), "my-org", tc.options)
+			require.NoError(t, err)
+			assert.Equal(t, tc.query, query)
+		})
+	}
+}
+
+func TestWorkspacesListWithOutputs(t *testing.T) {
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{
+			"data": [{
+				"id": "ws-SihZTyXKfNXUWuUa",
+				"type": "workspaces",
+				"attributes": {"name": "my-workspace"},
+				"relationships": {"outputs": {"data": [{"id": "wsout-V22qbeM92xb5mw9n", "type": "workspace-outputs"}]}}
+			}],
+			"included": [{
+				"id": "wsout-V22qbeM92xb5mw9n",
+				"type": "workspace-outputs",
+				"attributes": {"name": "ips", "output-type": "array", "value": ["10.0.0.1"]}
+			}],
+			"meta": {"pagination": {"current-page": 1, "total-pages": 1, "total-count": 1}}
+		}`))
+	})
+	defer ts.Close()
+
+	t.Run("with the outputs included", func(t *testing.T) {
+		wl, err := client.Workspaces.List(context.Background(), "my-org", WorkspaceListOptions{
+			Include: []WSIncludeOpt{WSOutputs},
+		})
+		require.NoError(t, err)
+		require.Len(t, wl.Items, 1)
+		require.Len(t, wl.Items[0].Outputs, 1)
+		assert.Equal(t, []interface{}{"10.0.0.1"}, wl.Items[0].Outputs[0].Value)
+	})
+
+	t.Run("without the outputs included", func(t *testing.T) {
+		wl, err := client.Workspaces.List(context.Background(), "my-org", WorkspaceListOptions{})
+		require.NoError(t, err)
+		require.Len(t, wl.Items, 1)
+		assert.Equal(t, "my-workspace", wl.Items[0].Name)
+		assert.Equal(t, 1, wl.TotalCount)
+	})
+}
+
 func TestWorkspacesCreate(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -204,6 +312,191 @@ func TestWorkspacesRead(t *testing.T) {
 	})
 }
 
+func TestWorkspacesReadPayload(t *testing.T) {
+	var path string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": {
+			"id": "ws-123",
+			"type": "workspaces",
+			"attributes": {
+				"name": "my.workspace",
+				"description": "My workspace",
+				"execution-mode": "agent"
+			}
+		}}`))
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with dots in the name", func(t *testing.T) {
+		w, err := client.Workspaces.Read(ctx, "my-org", "my.workspace")
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v2/organizations/my-org/workspaces/my.workspace", path)
+		assert.Equal(t, "My workspace", w.Description)
+		assert.Equal(t, ExecutionModeAgent, w.ExecutionMode)
+	})
+
+	t.Run("with a dot-segment as the name", func(t *testing.T) {
+		for _, name := range []string{".", ".."} {
+			w, err := client.Workspaces.Read(ctx, "my-org", name)
+			assert.Nil(t, w)
+			assert.EqualError(t, err, "invalid value for workspace")
+		}
+	})
+
+	t.Run("with an ID without the workspace prefix", func(t *testing.T) {
+		path = ""
+
+		w, err := client.Workspaces.ReadByID(ctx, "my-workspace")
+		assert.Nil(t, w)
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+
+		w, err = client.Workspaces.UpdateByID(ctx, "my-workspace", WorkspaceUpdateOptions{})
+		assert.Nil(t, w)
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+
+		err = client.Workspaces.DeleteByID(ctx, "my-workspace")
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+
+		// None of the calls should have reached the server.
+		assert.Empty(t, path)
+	})
+}
+
+func TestWorkspacesReadWithOptions(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	orgTest, orgTestCleanup := createOrganization(t, client)
+	defer orgTestCleanup()
+
+	wTest, wTestCleanup := createWorkspace(t, client, orgTest)
+	defer wTestCleanup()
+
+	rTest, _ := createRun(t, client, wTest)
+
+	options := WorkspaceReadOptions{
+		Include: []WSIncludeOpt{WSOrganization, WSCurrentRun, WSCurrentRunPlan},
+	}
+
+	t.Run("when the workspace exists", func(t *testing.T) {
+		w, err := client.Workspaces.ReadWithOptions(ctx, orgTest.Name, wTest.Name, options)
+		require.NoError(t, err)
+		assert.Equal(t, wTest.ID, w.ID)
+
+		t.Run("the organization is included", func(t *testing.T) {
+			require.NotNil(t, w.Organization)
+			assert.Equal(t, orgTest.Email, w.Organization.Email)
+		})
+
+		t.Run("the current run is included", func(t *testing.T) {
+			require.NotNil(t, w.CurrentRun)
+			assert.Equal(t, rTest.ID, w.CurrentRun.ID)
+			assert.NotEmpty(t, w.CurrentRun.Status)
+			require.NotNil(t, w.CurrentRun.Plan)
+			assert.NotEmpty(t, w.CurrentRun.Plan.Status)
+		})
+	})
+
+	t.Run("when the workspace does not exist", func(t *testing.T) {
+		w, err := client.Workspaces.ReadWithOptions(ctx, orgTest.Name, "nonexisting", options)
+		assert.Nil(t, w)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("without a valid workspace", func(t *testing.T) {
+		w, err := client.Workspaces.ReadWithOptions(ctx, orgTest.Name, badIdentifier, options)
+		assert.Nil(t, w)
+		assert.EqualError(t, err, "invalid value for workspace")
+	})
+}
+
+func TestWorkspacesReadWithOptionsFixture(t *testing.T) {
+	workspace, err := ioutil.ReadFile("test-fixtures/workspace/read-with-includes.json")
+	require.NoError(t, err)
+
+	var path, include string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		include = r.URL.Query().Get("include")
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(workspace)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	options := WorkspaceReadOptions{
+		Include: []WSIncludeOpt{
+			WSOrganization,
+			WSCurrentRun,
+			WSCurrentRunPlan,
+			WSCurrentConfigVerIngress,
+			WSOutputs,
+		},
+	}
+
+	check := func(t *testing.T, w *Workspace) {
+		assert.Equal(t, "organization,current_run,current_run.plan,current_configuration_version.ingress_attributes,outputs", include)
+
+		require.NotNil(t, w.Organization)
+		assert.Equal(t, "admin@example.com", w.Organization.Email)
+
+		require.NotNil(t, w.CurrentRun)
+		assert.Equal(t, "run-CZcmD7eagjhyX0vN", w.CurrentRun.ID)
+		assert.Equal(t, RunPlanned, w.CurrentRun.Status)
+		require.NotNil(t, w.CurrentRun.Plan)
+		assert.Equal(t, PlanFinished, w.CurrentRun.Plan.Status)
+		assert.Equal(t, 2, w.CurrentRun.Plan.ResourceAdditions)
+
+		require.NotNil(t, w.CurrentConfigurationVersion)
+		assert.Equal(t, ConfigurationUploaded, w.CurrentConfigurationVersion.Status)
+		require.NotNil(t, w.CurrentConfigurationVersion.IngressAttributes)
+		assert.Equal(t, "main", w.CurrentConfigurationVersion.IngressAttributes.Branch)
+		assert.Equal(t, "hashicorp/my-repo", w.CurrentConfigurationVersion.IngressAttributes.Identifier)
+
+		assert.Equal(t, []*WorkspaceOutput{{
+			ID:    "wsout-V22qbeM92xb5mw9n",
+			Name:  "ip",
+			Type:  "string",
+			Value: "10.0.0.1",
+		}, {
+			ID:    "wsout-fZ3QhbmRpR1CnPdb",
+			Name:  "port",
+			Type:  "number",
+			Value: json.Number("8080"),
+		}, {
+			ID:        "wsout-kFRtCgTd3xnpMfSK",
+			Name:      "password",
+			Sensitive: true,
+			Type:      "string",
+		}}, w.Outputs)
+	}
+
+	t.Run("by name", func(t *testing.T) {
+		w, err := client.Workspaces.ReadWithOptions(ctx, "my-org", "my-workspace", options)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v2/organizations/my-org/workspaces/my-workspace", path)
+		check(t, w)
+	})
+
+	t.Run("by ID", func(t *testing.T) {
+		w, err := client.Workspaces.ReadByIDWithOptions(ctx, "ws-SihZTyXKfNXUWuUa", options)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v2/workspaces/ws-SihZTyXKfNXUWuUa", path)
+		check(t, w)
+	})
+
+	t.Run("without options", func(t *testing.T) {
+		w, err := client.Workspaces.ReadByID(ctx, "ws-SihZTyXKfNXUWuUa")
+		require.NoError(t, err)
+		assert.Empty(t, include)
+		assert.Equal(t, "my-workspace", w.Name)
+	})
+}
+
 func TestWorkspacesReadByID(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -233,15 +526,71 @@ func TestWorkspacesReadByID(t *testing.T) {
 	})
 
 	t.Run("when the workspace does not exist", func(t *testing.T) {
-		w, err := client.Workspaces.ReadByID(ctx, "nonexisting")
+		w, err := client.Workspaces.ReadByID(ctx, "ws-nonexisting")
 		assert.Nil(t, w)
-		assert.Error(t, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("without a workspace ID prefix", func(t *testing.T) {
+		w, err := client.Workspaces.ReadByID(ctx, wTest.Name)
+		assert.Nil(t, w)
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
 	})
 
 	t.Run("without a valid workspace ID", func(t *testing.T) {
 		w, err := client.Workspaces.ReadByID(ctx, badIdentifier)
 		assert.Nil(t, w)
-		assert.EqualError(t, err, "invalid value for workspace ID")
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+	})
+}
+
+func TestWorkspacesReadmeFixture(t *testing.T) {
+	readme, err := ioutil.ReadFile("test-fixtures/workspace-readme/README.md")
+	require.NoError(t, err)
+
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/workspaces/ws-123/readme":
+			w.Header().Set("Content-Type", "text/markdown")
+			w.Write(readme)
+		case "/api/v2/workspaces/ws-456/readme":
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("with a readme", func(t *testing.T) {
+		r, err := client.Workspaces.Readme(ctx, "ws-123")
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, string(readme), string(body))
+	})
+
+	t.Run("without a readme", func(t *testing.T) {
+		r, err := client.Workspaces.Readme(ctx, "ws-456")
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Empty(t, body)
+	})
+
+	t.Run("when the workspace does not exist", func(t *testing.T) {
+		r, err := client.Workspaces.Readme(ctx, "ws-nonexisting")
+		assert.Nil(t, r)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with an invalid workspace ID", func(t *testing.T) {
+		r, err := client.Workspaces.Readme(ctx, "my-workspace")
+		assert.Nil(t, r)
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
 	})
 }
 
@@ -401,7 +750,149 @@ func TestWorkspacesUpdateByID(t *testing.T) {
 	t.Run("without a valid workspace ID", func(t *testing.T) {
 		w, err := client.Workspaces.UpdateByID(ctx, badIdentifier, WorkspaceUpdateOptions{})
 		assert.Nil(t, w)
-		assert.EqualError(t, err, "invalid value for workspace ID")
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+	})
+	t.Run("without a workspace ID prefix", func(t *testing.T) {
+		w, err := client.Workspaces.UpdateByID(ctx, wTest.Name, WorkspaceUpdateOptions{})
+		assert.Nil(t, w)
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+	})
+}
+
+func TestWorkspacesUpdatePayload(t *testing.T) {
+	var body map[string]interface{}
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write([]byte(`{"data": {
+			"id": "ws-123",
+			"type": "workspaces",
+			"attributes": {
+				"name": "my-workspace",
+				"trigger-patterns": ["/modules/**/*.tf"],
+				"vcs-repo": {
+					"identifier": "my-org/my-repo",
+					"tags-regex": "^v\\d+$"
+				}
+			}
+		}}`))
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	attributes := func() map[string]interface{} {
+		data, ok := body["data"].(map[string]interface{})
+		require.True(t, ok)
+		attrs, _ := data["attributes"].(map[string]interface{})
+		return attrs
+	}
+
+	t.Run("with trigger patterns and a tags regex", func(t *testing.T) {
+		w, err := client.Workspaces.UpdateByID(ctx, "ws-123", WorkspaceUpdateOptions{
+			TriggerPatterns: []string{"/modules/**/*.tf"},
+			VCSRepo: &VCSRepoOptions{
+				Identifier: String("my-org/my-repo"),
+				TagsRegex:  String(`^v\d+$`),
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/modules/**/*.tf"}, w.TriggerPatterns)
+		assert.Equal(t, `^v\d+$`, w.VCSRepo.TagsRegex)
+
+		attrs := attributes()
+		assert.Equal(t, []interface{}{"/modules/**/*.tf"}, attrs["trigger-patterns"])
+		assert.NotContains(t, attrs, "trigger-prefixes")
+		assert.Equal(t, map[string]interface{}{
+			"identifier": "my-org/my-repo",
+			"tags-regex": `^v\d+$`,
+		}, attrs["vcs-repo"])
+	})
+
+	t.Run("with a project", func(t *testing.T) {
+		_, err := client.Workspaces.UpdateByID(ctx, "ws-123", WorkspaceUpdateOptions{
+			Description: String("Shared network"),
+			Project:     &Project{ID: "prj-123", Name: "networking"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Shared network", attributes()["description"])
+		data := body["data"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{
+			"project": map[string]interface{}{
+				"data": map[string]interface{}{"type": "projects", "id": "prj-123"},
+			},
+		}, data["relationships"])
+		assert.NotContains(t, body, "included")
+	})
+
+	t.Run("without a VCS repo", func(t *testing.T) {
+		_, err := client.Workspaces.UpdateByID(ctx, "ws-123", WorkspaceUpdateOptions{
+			AutoApply: Bool(true),
+		})
+		require.NoError(t, err)
+		assert.NotContains(t, attributes(), "vcs-repo")
+	})
+
+	t.Run("when removing the VCS connection", func(t *testing.T) {
+		_, err := client.Workspaces.RemoveVCSConnectionByID(ctx, "ws-123")
+		require.NoError(t, err)
+
+		attrs := attributes()
+		require.Contains(t, attrs, "vcs-repo")
+		assert.Nil(t, attrs["vcs-repo"])
+	})
+
+	t.Run("when removing the VCS repo", func(t *testing.T) {
+		_, err := client.Workspaces.Update(ctx, "my-org", "my-workspace", WorkspaceUpdateOptions{
+			AutoApply:     Bool(true),
+			RemoveVCSRepo: true,
+		})
+		require.NoError(t, err)
+
+		attrs := attributes()
+		assert.Equal(t, true, attrs["auto-apply"])
+		require.Contains(t, attrs, "vcs-repo")
+		assert.Nil(t, attrs["vcs-repo"])
+	})
+
+	t.Run("when removing the VCS repo and moving to a project", func(t *testing.T) {
+		_, err := client.Workspaces.UpdateByID(ctx, "ws-123", WorkspaceUpdateOptions{
+			Project:       &Project{ID: "prj-123", Name: "networking"},
+			RemoveVCSRepo: true,
+		})
+		require.NoError(t, err)
+
+		assert.Nil(t, attributes()["vcs-repo"])
+		data := body["data"].(map[string]interface{})
+		assert.Contains(t, data["relationships"], "project")
+		assert.NotContains(t, body, "included")
+	})
+
+	t.Run("when removing the trigger prefixes", func(t *testing.T) {
+		_, err := client.Workspaces.UpdateByID(ctx, "ws-123", WorkspaceUpdateOptions{
+			TriggerPrefixes: []string{},
+		})
+		require.NoError(t, err)
+
+		attrs := attributes()
+		require.Contains(t, attrs, "trigger-prefixes")
+		assert.Equal(t, []interface{}{}, attrs["trigger-prefixes"])
+		assert.NotContains(t, attrs, "trigger-patterns")
+	})
+
+	t.Run("when switching from trigger prefixes to patterns", func(t *testing.T) {
+		_, err := client.Workspaces.UpdateByID(ctx, "ws-123", WorkspaceUpdateOptions{
+			TriggerPrefixes: []string{},
+			TriggerPatterns: []string{"/modules/**/*.tf"},
+		})
+		require.NoError(t, err)
+
+		attrs := attributes()
+		assert.Equal(t, []interface{}{}, attrs["trigger-prefixes"])
+		assert.Equal(t, []interface{}{"/modules/**/*.tf"}, attrs["trigger-patterns"])
 	})
 }
 
@@ -420,7 +911,7 @@ func TestWorkspacesDelete(t *testing.T) {
 
 		// Try loading the workspace - it should fail.
 		_, err = client.Workspaces.Read(ctx, orgTest.Name, wTest.Name)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("when organization is invalid", func(t *testing.T) {
@@ -449,12 +940,79 @@ func TestWorkspacesDeleteByID(t *testing.T) {
 
 		// Try loading the workspace - it should fail.
 		_, err = client.Workspaces.ReadByID(ctx, wTest.ID)
-		assert.Equal(t, ErrResourceNotFound, err)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
 	})
 
 	t.Run("without a valid workspace ID", func(t *testing.T) {
 		err := client.Workspaces.DeleteByID(ctx, badIdentifier)
-		assert.EqualError(t, err, "invalid value for workspace ID")
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+	})
+	t.Run("without a workspace ID prefix", func(t *testing.T) {
+		err := client.Workspaces.DeleteByID(ctx, "my-workspace")
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+	})
+}
+
+func TestWorkspacesSafeDelete(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	orgTest, orgTestCleanup := createOrganization(t, client)
+	defer orgTestCleanup()
+
+	wTest, _ := createWorkspace(t, client, orgTest)
+
+	t.Run("with valid options", func(t *testing.T) {
+		err := client.Workspaces.SafeDelete(ctx, orgTest.Name, wTest.Name)
+		require.NoError(t, err)
+
+		// Try loading the workspace - it should fail.
+		_, err = client.Workspaces.Read(ctx, orgTest.Name, wTest.Name)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("when the workspace does not exist", func(t *testing.T) {
+		err := client.Workspaces.SafeDelete(ctx, orgTest.Name, "nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with invalid organization", func(t *testing.T) {
+		err := client.Workspaces.SafeDelete(ctx, badIdentifier, wTest.Name)
+		assert.EqualError(t, err, "invalid value for organization")
+	})
+
+	t.Run("without a valid workspace", func(t *testing.T) {
+		err := client.Workspaces.SafeDelete(ctx, orgTest.Name, badIdentifier)
+		assert.EqualError(t, err, "invalid value for workspace")
+	})
+}
+
+func TestWorkspacesSafeDeleteByID(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	orgTest, orgTestCleanup := createOrganization(t, client)
+	defer orgTestCleanup()
+
+	wTest, _ := createWorkspace(t, client, orgTest)
+
+	t.Run("with valid options", func(t *testing.T) {
+		err := client.Workspaces.SafeDeleteByID(ctx, wTest.ID)
+		require.NoError(t, err)
+
+		// Try loading the workspace - it should fail.
+		_, err = client.Workspaces.ReadByID(ctx, wTest.ID)
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("without a valid workspace ID", func(t *testing.T) {
+		err := client.Workspaces.SafeDeleteByID(ctx, badIdentifier)
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
+	})
+
+	t.Run("without a workspace ID prefix", func(t *testing.T) {
+		err := client.Workspaces.SafeDeleteByID(ctx, "my-workspace")
+		assert.EqualError(t, err, `invalid value for workspace ID: must start with "ws-"`)
 	})
 }
 
@@ -507,7 +1065,7 @@ func TestWorkspacesLock(t *testing.T) {
 
 	t.Run("when workspace is already locked", func(t *testing.T) {
 		_, err := client.Workspaces.Lock(ctx, wTest.ID, WorkspaceLockOptions{})
-		assert.Equal(t, ErrWorkspaceLocked, err)
+		assert.True(t, errors.Is(err, ErrWorkspaceLocked), err)
 	})
 
 	t.Run("without a valid workspace ID", func(t *testing.T) {
@@ -541,7 +1099,7 @@ func TestWorkspacesUnlock(t *testing.T) {
 
 	t.Run("when workspace is already unlocked", func(t *testing.T) {
 		_, err := client.Workspaces.Unlock(ctx, wTest.ID)
-		assert.Equal(t, ErrWorkspaceNotLocked, err)
+		assert.True(t, errors.Is(err, ErrWorkspaceNotLocked), err)
 	})
 
 	t.Run("without a valid workspace ID", func(t *testing.T) {
@@ -575,7 +1133,7 @@ func TestWorkspacesForceUnlock(t *testing.T) {
 
 	t.Run("when workspace is already unlocked", func(t *testing.T) {
 		_, err := client.Workspaces.ForceUnlock(ctx, wTest.ID)
-		assert.Equal(t, ErrWorkspaceNotLocked, err)
+		assert.True(t, errors.Is(err, ErrWorkspaceNotLocked), err)
 	})
 
 	t.Run("without a valid workspace ID", func(t *testing.T) {
@@ -630,6 +1188,61 @@ func TestWorkspacesAssignSSHKey(t *testing.T) {
 	})
 }
 
+func TestWorkspacesSSHKeyPayload(t *testing.T) {
+	workspace, err := ioutil.ReadFile("test-fixtures/ssh-key/workspace.json")
+	require.NoError(t, err)
+
+	var method, path string
+	var body []byte
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		body, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(workspace)
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	t.Run("when assigning an SSH key", func(t *testing.T) {
+		w, err := client.Workspaces.AssignSSHKey(ctx, "ws-SihZTyXKfNXUWuUa", WorkspaceAssignSSHKeyOptions{
+			SSHKeyID: String("sshkey-GxrePWre1Ezug7aM"),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, w.SSHKey)
+		assert.Equal(t, "sshkey-GxrePWre1Ezug7aM", w.SSHKey.ID)
+
+		expected, err := ioutil.ReadFile("test-fixtures/ssh-key/assign.json")
+		require.NoError(t, err)
+		assert.Equal(t, "PATCH", method)
+		assert.Equal(t, "/api/v2/workspaces/ws-SihZTyXKfNXUWuUa/relationships/ssh-key", path)
+		assert.JSONEq(t, string(expected), string(body))
+	})
+
+	t.Run("when unassigning an SSH key", func(t *testing.T) {
+		_, err := client.Workspaces.UnassignSSHKey(ctx, "ws-SihZTyXKfNXUWuUa")
+		require.NoError(t, err)
+
+		expected, err := ioutil.ReadFile("test-fixtures/ssh-key/unassign.json")
+		require.NoError(t, err)
+		assert.Equal(t, "PATCH", method)
+		assert.Equal(t, "/api/v2/workspaces/ws-SihZTyXKfNXUWuUa/relationships/ssh-key", path)
+		assert.JSONEq(t, string(expected), string(body))
+	})
+
+	t.Run("without an SSH key ID", func(t *testing.T) {
+		method = ""
+
+		w, err := client.Workspaces.AssignSSHKey(ctx, "ws-SihZTyXKfNXUWuUa", WorkspaceAssignSSHKeyOptions{
+			SSHKeyID: String(""),
+		})
+		assert.Nil(t, w)
+		assert.EqualError(t, err, "SSH key ID is required")
+		assert.Empty(t, method)
+	})
+}
+
 func TestWorkspacesUnassignSSHKey(t *testing.T) {
 	client := testClient(t)
 	ctx := context.Background()
@@ -665,3 +1278,511 @@ func TestWorkspacesUnassignSSHKey(t *testing.T) {
 		assert.EqualError(t, err, "invalid value for workspace ID")
 	})
 }
+
+func TestWorkspacesRemoteStateConsumers(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	orgTest, orgTestCleanup := createOrganization(t, client)
+	defer orgTestCleanup()
+
+	wTest, _ := createWorkspace(t, client, orgTest)
+	wTestConsumer1, _ := createWorkspace(t, client, orgTest)
+	wTestConsumer2, _ := createWorkspace(t, client, orgTest)
+	wTestConsumer3, _ := createWorkspace(t, client, orgTest)
+
+	consumerIDs := func(t *testing.T) []string {
+		wl, err := client.Workspaces.ListRemoteStateConsumers(ctx, wTest.ID, RemoteStateConsumersListOptions{})
+		require.NoError(t, err)
+
+		var ids []string
+		for _, w := range wl.Items {
+			ids = append(ids, w.ID)
+		}
+		return ids
+	}
+
+	t.Run("add remote state consumers", func(t *testing.T) {
+		err := client.Workspaces.AddRemoteStateConsumers(ctx, wTest.ID, WorkspaceAddRemoteStateConsumersOptions{
+			Workspaces: []*Workspace{wTestConsumer1, {ID: wTestConsumer2.ID}},
+		})
+		require.NoError(t, err)
+
+		ids := consumerIDs(t)
+		assert.Len(t, ids, 2)
+		assert.Contains(t, ids, wTestConsumer1.ID)
+		assert.Contains(t, ids, wTestConsumer2.ID)
+	})
+
+	t.Run("list remote state consumers with pagination", func(t *testing.T) {
+		wl, err := client.Workspaces.ListRemoteStateConsumers(ctx, wTest.ID, RemoteStateConsumersListOptions{
+			ListOptions: ListOptions{
+				PageNumber: 1,
+				PageSize:   1,
+			},
+		})
+		require.NoError(t, err)
+		assert.Len(t, wl.Items, 1)
+		assert.Equal(t, 1, wl.CurrentPage)
+		assert.Equal(t, 2, wl.TotalCount)
+	})
+
+	t.Run("remove a remote state consumer", func(t *testing.T) {
+		err := client.Workspaces.RemoveRemoteStateConsumers(ctx, wTest.ID, WorkspaceRemoveRemoteStateConsumersOptions{
+			Workspaces: []*Workspace{wTestConsumer1},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{wTestConsumer2.ID}, consumerIDs(t))
+	})
+
+	t.Run("replace the remote state consumers", func(t *testing.T) {
+		err := client.Workspaces.UpdateRemoteStateConsumers(ctx, wTest.ID, WorkspaceUpdateRemoteStateConsumersOptions{
+			Workspaces: []*Workspace{wTestConsumer1, wTestConsumer3},
+		})
+		require.NoError(t, err)
+
+		ids := consumerIDs(t)
+		assert.Len(t, ids, 2)
+		assert.Contains(t, ids, wTestConsumer1.ID)
+		assert.Contains(t, ids, wTestConsumer3.ID)
+	})
+
+	t.Run("without workspaces", func(t *testing.T) {
+		err := client.Workspaces.AddRemoteStateConsumers(ctx, wTest.ID, WorkspaceAddRemoteStateConsumersOptions{})
+		assert.EqualError(t, err, "workspaces is required")
+
+		err = client.Workspaces.RemoveRemoteStateConsumers(ctx, wTest.ID, WorkspaceRemoveRemoteStateConsumersOptions{
+			Workspaces: []*Workspace{},
+		})
+		assert.EqualError(t, err, "must provide at least one workspace")
+
+		err = client.Workspaces.UpdateRemoteStateConsumers(ctx, wTest.ID, WorkspaceUpdateRemoteStateConsumersOptions{})
+		assert.EqualError(t, err, "workspaces is required")
+	})
+
+	t.Run("without a valid workspace ID", func(t *testing.T) {
+		wl, err := client.Workspaces.ListRemoteStateConsumers(ctx, badIdentifier, RemoteStateConsumersListOptions{})
+		assert.Nil(t, wl)
+		assert.EqualError(t, err, "invalid value for workspace ID")
+
+		err = client.Workspaces.AddRemoteStateConsumers(ctx, badIdentifier, WorkspaceAddRemoteStateConsumersOptions{
+			Workspaces: []*Workspace{wTestConsumer1},
+		})
+		assert.EqualError(t, err, "invalid value for workspace ID")
+	})
+}
+
+func TestWorkspacesTags(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	orgTest, orgTestCleanup := createOrganization(t, client)
+	defer orgTestCleanup()
+
+	wTest, _ := createWorkspace(t, client, orgTest)
+
+	t.Run("add tags by name", func(t *testing.T) {
+		err := client.Workspaces.AddTags(ctx, wTest.ID, WorkspaceAddTagsOptions{
+			Tags: []*Tag{{Name: "production"}, {Name: "networking"}},
+		})
+		require.NoError(t, err)
+
+		tl, err := client.Workspaces.ListTags(ctx, wTest.ID, WorkspaceTagListOptions{})
+		require.NoError(t, err)
+		require.Len(t, tl.Items, 2)
+		assert.Equal(t, 2, tl.TotalCount)
+
+		for _, tag := range tl.Items {
+			assert.NotEmpty(t, tag.ID)
+			assert.Contains(t, []string{"production", "networking"}, tag.Name)
+			assert.Equal(t, 1, tag.InstanceCount)
+		}
+	})
+
+	t.Run("remove a tag", func(t *testing.T) {
+		err := client.Workspaces.RemoveTags(ctx, wTest.ID, WorkspaceRemoveTagsOptions{
+			Tags: []*Tag{{Name: "networking"}},
+		})
+		require.NoError(t, err)
+
+		tl, err := client.Workspaces.ListTags(ctx, wTest.ID, WorkspaceTagListOptions{})
+		require.NoError(t, err)
+		require.Len(t, tl.Items, 1)
+		assert.Equal(t, "production", tl.Items[0].Name)
+	})
+
+	t.Run("with an invalid tag name", func(t *testing.T) {
+		err := client.Workspaces.AddTags(ctx, wTest.ID, WorkspaceAddTagsOptions{
+			Tags: []*Tag{{Name: "Production"}},
+		})
+		assert.EqualError(t, err, `invalid value for tag name "Production": must only contain lowercase letters, numbers, - and _`)
+	})
+
+	t.Run("without a valid workspace ID", func(t *testing.T) {
+		tl, err := client.Workspaces.ListTags(ctx, badIdentifier, WorkspaceTagListOptions{})
+		assert.Nil(t, tl)
+		assert.EqualError(t, err, "invalid value for workspace ID")
+	})
+}
+
+func TestWorkspacesTagsPayload(t *testing.T) {
+	list, err := ioutil.ReadFile("test-fixtures/tags/list.json")
+	require.NoError(t, err)
+
+	var method string
+	var body []byte
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/workspaces/ws-123/relationships/tags":
+			method = r.Method
+			body, _ = ioutil.ReadAll(r.Body)
+			if r.Method != "GET" {
+				w.WriteHeader(204)
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write(list)
+		default:
+			w.WriteHeader(404)
+		}
+	})
+	defer ts.Close()
+
+	ctx := context.Background()
+	tags := []*Tag{
+		{Name: "production"},
+		{ID: "tag-1e1rRTqVB9riTQAn", Name: "ignored"},
+	}
+
+	expected, err := ioutil.ReadFile("test-fixtures/tags/tags.json")
+	require.NoError(t, err)
+
+	t.Run("when adding tags", func(t *testing.T) {
+		err := client.Workspaces.AddTags(ctx, "ws-123", WorkspaceAddTagsOptions{Tags: tags})
+		require.NoError(t, err)
+		assert.Equal(t, "POST", method)
+		assert.JSONEq(t, string(expected), string(body))
+	})
+
+	t.Run("when removing tags", func(t *testing.T) {
+		err := client.Workspaces.RemoveTags(ctx, "ws-123", WorkspaceRemoveTagsOptions{Tags: tags})
+		require.NoError(t, err)
+		assert.Equal(t, "DELETE", method)
+		assert.JSONEq(t, string(expected), string(body))
+	})
+
+	t.Run("when listing tags", func(t *testing.T) {
+		tl, err := client.Workspaces.ListTags(ctx, "ws-123", WorkspaceTagListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []*Tag{
+			{ID: "tag-1e1rRTqVB9riTQAn", Name: "production", InstanceCount: 12},
+			{ID: "tag-2ZsNYnRfGcMBU1Ah", Name: "networking", InstanceCount: 1},
+		}, tl.Items)
+		assert.Equal(t, 2, tl.TotalCount)
+	})
+}
+
+func TestWorkspacesDataRetentionPolicyFixture(t *testing.T) {
+	var apiVersion string
+	var requests []string
+	client, ts := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("TFP-API-Version", apiVersion)
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+
+		var fixture string
+		switch r.URL.Path {
+		case "/api/v2/workspaces/ws-older/relationships/data-retention-policy":
+			fixture = "delete-older.json"
+		case "/api/v2/workspaces/ws-dont/relationships/data-retention-policy":
+			fixture = "dont-delete.json"
+		case "/api/v2/workspaces/ws-none/relationships/data-retention-policy":
+			if r.Method == "DELETE" {
+				w.WriteHeader(204)
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.api+json")
+			w.Write([]byte(`{"data": null}`))
+			return
+		default:
+			w.WriteHeader(404)
+			return
+		}
+
+		data, err := ioutil.ReadFile("test-fixtures/data-retention-policy/" + fixture)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.Write(data)
+	})
+	defer ts.Close()
+
+	apiVersion = "2.6"
+	ctx := context.Background()
+
+	t.Run("when reading a delete older policy", func(t *testing.T) {
+		choice, err := client.Workspaces.ReadDataRetentionPolicy(ctx, "ws-older")
+		require.NoError(t, err)
+		require.True(t, choice.IsPopulated())
+		assert.Nil(t, choice.DontDelete)
+		require.NotNil(t, choice.DeleteOlder)
+		assert.Equal(t, "drp-7ZVzp4VGiDyNwqdU", choice.DeleteOlder.ID)
+		assert.Equal(t, 30, choice.DeleteOlder.DeleteOlderThanNDays)
+	})
+
+	t.Run("when reading a dont delete policy", func(t *testing.T) {
+		choice, err := client.Workspaces.ReadDataRetentionPolicy(ctx, "ws-dont")
+		require.NoError(t, err)
+		assert.Nil(t, choice.DeleteOlder)
+		require.NotNil(t, choice.DontDelete)
+		assert.Equal(t, "drp-ZAsBSm3kYbQbdPPB", choice.DontDelete.ID)
+	})
+
+	t.Run("without a policy", func(t *testing.T) {
+		choice, err := client.Workspaces.ReadDataRetentionPolicy(ctx, "ws-none")
+		require.NoError(t, err)
+		assert.False(t, choice.IsPopulated())
+	})
+
+	t.Run("when setting a delete older policy", func(t *testing.T) {
+		requests = nil
+
+		d, err := client.Workspaces.SetDataRetentionPolicyDeleteOlder(ctx, "ws-older", DataRetentionPolicyDeleteOlderSetOptions{
+			ID:                   "drp-user-provided",
+			DeleteOlderThanNDays: 30,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 30, d.DeleteOlderThanNDays)
+
+		require.Len(t, requests, 1)
+		assert.Contains(t, requests[0], "POST /api/v2/workspaces/ws-older/relationships/data-retention-policy")
+		assert.Contains(t, requests[0], `"type":"data-retention-policy-delete-olders"`)
+		assert.Contains(t, requests[0], `"delete-older-than-n-days":30`)
+		assert.NotContains(t, requests[0], "drp-user-provided")
+	})
+
+	t.Run("when setting a dont delete policy", func(t *testing.T) {
+		requests = nil
+
+		d, err := client.Workspaces.SetDataRetentionPolicyDontDelete(ctx, "ws-dont", DataRetentionPolicyDontDeleteSetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "drp-ZAsBSm3kYbQbdPPB", d.ID)
+
+		require.Len(t, requests, 1)
+		assert.Contains(t, requests[0], `"type":"data-retention-policy-dont-deletes"`)
+	})
+
+	t.Run("when deleting a policy", func(t *testing.T) {
+		err := client.Workspaces.DeleteDataRetentionPolicy(ctx, "ws-none")
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the workspace does not exist", func(t *testing.T) {
+		_, err := client.Workspaces.ReadDataRetentionPolicy(ctx, "ws-nonexisting")
+		assert.True(t, errors.Is(err, ErrResourceNotFound), err)
+	})
+
+	t.Run("with an older TFE version", func(t *testing.T) {
+		apiVersion = "2.5"
+
+		_, err := client.Workspaces.ReadDataRetentionPolicy(ctx, "ws-nonexisting")
+		assert.True(t, errors.Is(err, ErrDataRetentionPolicyNotSupported), err)
+
+		_, err = client.Workspaces.SetDataRetentionPolicyDontDelete(ctx, "ws-nonexisting", DataRetentionPolicyDontDeleteSetOptions{})
+		assert.True(t, errors.Is(err, ErrDataRetentionPolicyNotSupported), err)
+
+		err = client.Workspaces.DeleteDataRetentionPolicy(ctx, "ws-nonexisting")
+		assert.True(t, errors.Is(err, ErrDataRetentionPolicyNotSupported), err)
+	})
+}
+
+func TestWorkspacesUpdateOptionsValid(t *testing.T) {
+	t.Run("without any options", func(t *testing.T) {
+		options := WorkspaceUpdateOptions{}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with a valid name", func(t *testing.T) {
+		options := WorkspaceUpdateOptions{
+			Name: String("my-workspace"),
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with an invalid name", func(t *testing.T) {
+		options := WorkspaceUpdateOptions{
+			Name: String(badIdentifier),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for name")
+	})
+
+	t.Run("with a valid execution mode", func(t *testing.T) {
+		options := WorkspaceUpdateOptions{
+			ExecutionMode: ExecutionMode(ExecutionModeLocal),
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("with an unknown execution mode", func(t *testing.T) {
+		options := WorkspaceUpdateOptions{
+			ExecutionMode: ExecutionMode("cloud"),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for execution mode")
+	})
+
+	t.Run("with both trigger prefixes and patterns", func(t *testing.T) {
+		options := WorkspaceUpdateOptions{
+			TriggerPrefixes: []string{"/modules"},
+			TriggerPatterns: []string{"/modules/**/*.tf"},
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "only one of trigger prefixes or trigger patterns can be set")
+	})
+
+	t.Run("with both a VCS repo and removing it", func(t *testing.T) {
+		options := WorkspaceUpdateOptions{
+			VCSRepo:       &VCSRepoOptions{Branch: String("main")},
+			RemoveVCSRepo: true,
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "only one of vcs repo or remove vcs repo can be set")
+	})
+}
+
+func TestWorkspacesCreateOptionsValid(t *testing.T) {
+	t.Run("with valid options", func(t *testing.T) {
+		options := WorkspaceCreateOptions{
+			Name:          String("my.workspace"),
+			Description:   String("My workspace"),
+			ExecutionMode: ExecutionMode(ExecutionModeRemote),
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("without a name", func(t *testing.T) {
+		options := WorkspaceCreateOptions{}
+
+		err := options.valid()
+		assert.EqualError(t, err, "name is required")
+	})
+
+	t.Run("with an invalid name", func(t *testing.T) {
+		for _, name := range []string{badIdentifier, ".."} {
+			options := WorkspaceCreateOptions{
+				Name: String(name),
+			}
+
+			err := options.valid()
+			assert.EqualError(t, err, "invalid value for name")
+		}
+	})
+
+	t.Run("with an unknown execution mode", func(t *testing.T) {
+		options := WorkspaceCreateOptions{
+			Name:          String("my-workspace"),
+			ExecutionMode: ExecutionMode("cloud"),
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for execution mode")
+	})
+
+	t.Run("with both trigger prefixes and patterns", func(t *testing.T) {
+		options := WorkspaceCreateOptions{
+			Name:            String("my-workspace"),
+			TriggerPrefixes: []string{"/modules"},
+			TriggerPatterns: []string{"/modules/**/*.tf"},
+		}
+
+		err := options.valid()
+		assert.EqualError(t, err, "only one of trigger prefixes or trigger patterns can be set")
+	})
+}
+
+func TestDataRetentionPolicyDeleteOlderSetOptionsValid(t *testing.T) {
+	t.Run("with a number of days", func(t *testing.T) {
+		options := DataRetentionPolicyDeleteOlderSetOptions{
+			DeleteOlderThanNDays: 1,
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("without a number of days", func(t *testing.T) {
+		options := DataRetentionPolicyDeleteOlderSetOptions{}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for delete older than n days: must be at least 1")
+	})
+}
+
+func TestWorkspacesAddTagsOptionsValid(t *testing.T) {
+	t.Run("with valid tags", func(t *testing.T) {
+		options := WorkspaceAddTagsOptions{
+			Tags: []*Tag{{Name: "prod_2-eu"}, {ID: "tag-1e1rRTqVB9riTQAn"}},
+		}
+
+		err := options.valid()
+		assert.Nil(t, err)
+	})
+
+	t.Run("without tags", func(t *testing.T) {
+		options := WorkspaceAddTagsOptions{}
+
+		err := options.valid()
+		assert.EqualError(t, err, "tags is required")
+	})
+
+	t.Run("with an empty list of tags", func(t *testing.T) {
+		options := WorkspaceAddTagsOptions{Tags: []*Tag{}}
+
+		err := options.valid()
+		assert.EqualError(t, err, "must provide at least one tag")
+	})
+
+	t.Run("with a nil tag", func(t *testing.T) {
+		options := WorkspaceAddTagsOptions{Tags: []*Tag{nil}}
+
+		err := options.valid()
+		assert.EqualError(t, err, "tags cannot contain nil values")
+	})
+
+	t.Run("without an ID or name", func(t *testing.T) {
+		options := WorkspaceAddTagsOptions{Tags: []*Tag{{}}}
+
+		err := options.valid()
+		assert.EqualError(t, err, "tag ID or name is required")
+	})
+
+	t.Run("with an invalid ID", func(t *testing.T) {
+		options := WorkspaceAddTagsOptions{Tags: []*Tag{{ID: badIdentifier}}}
+
+		err := options.valid()
+		assert.EqualError(t, err, "invalid value for tag ID")
+	})
+
+	t.Run("with an invalid name", func(t *testing.T) {
+		for _, name := range []string{"Production", "prod env", "prod.env"} {
+			options := WorkspaceAddTagsOptions{Tags: []*Tag{{Name: name}}}
+
+			err := options.valid()
+			assert.Error(t, err, name)
+		}
+	})
+}